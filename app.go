@@ -3,59 +3,99 @@ package main
 
 import (
 	"context"
-	"encoding/base64"
 	"fmt"
+	"log/slog"
 	"os"
+	"sync"
 	"time"
 
+	"product-management-app/core/config"
 	"product-management-app/core/dto"
+	apperrors "product-management-app/core/errors"
 	"product-management-app/core/models"
 	service "product-management-app/core/services"
+	"product-management-app/core/validation"
 
 	"github.com/wailsapp/wails/v2/pkg/options"
 	"github.com/wailsapp/wails/v2/pkg/runtime"
 )
 
+// Timeouts applied to the calls the frontend makes. Reads and single writes
+// are quick; imports, exports, bulk operations and backups grow with the
+// catalog and get far longer.
+const (
+	queryTimeout       = 30 * time.Second
+	longCallTimeout    = 30 * time.Minute
+	healthCheckTimeout = 5 * time.Second
+)
+
+// errCancelledByUser is returned when the user dismisses a file dialog.
+var errCancelledByUser = apperrors.Cancelled("operation cancelled by user")
+
 // App struct
 type App struct {
 	ctx             context.Context
+	config          *config.Config
+	configErr       error
 	productService  *service.ProductService
-	currencyService *service.WailsCurrencyService
-	dbHealthy       bool
-	dbError         string
+	currencyService *service.CurrencyService
+
+	// dbMu guards the database status, which calls check while a restore
+	// or a reconnection changes it.
+	dbMu      sync.RWMutex
+	dbHealthy bool
+	dbError   string
+
+	// calls holds the cancel functions of the calls in progress, so
+	// CancelOperations can abort them.
+	callsMu  sync.Mutex
+	nextCall int
+	calls    map[int]context.CancelFunc
 }
 
-// NewApp creates a new App application struct
-func NewApp() *App {
-	return &App{}
+// NewApp creates a new App application struct. configErr is a non-fatal
+// problem met while loading cfg and is logged once the app has started.
+func NewApp(cfg *config.Config, configErr error) *App {
+	return &App{config: cfg, configErr: configErr}
 }
 
 // startup is called at application startup
 func (a *App) startup(ctx context.Context) {
 	// Perform your setup here
 	a.ctx = ctx
-	a.productService = service.NewProductService()
-	a.productService.SetContext(ctx)
+	if a.configErr != nil {
+		runtime.LogWarning(a.ctx, fmt.Sprintf("Configuration problem, continuing with defaults: %v", a.configErr))
+	}
+	runtime.LogInfo(a.ctx, fmt.Sprintf("Using database %s (from %s)", a.config.Database.Path, a.config.Database.PathSource))
+
+	// The services log through slog; the handler forwards to the Wails logger.
+	logger := slog.New(newWailsLogHandler(ctx))
+	emit := func(name string, data interface{}) {
+		runtime.EventsEmit(ctx, name, data)
+	}
+	a.productService = service.NewProductService(a.config.Database, validation.NewProductValidator(a.config.Validation), logger, emit)
 
 	// Initialize currency service
-	a.currencyService = service.NewWailsCurrencyService(ctx)
+	a.currencyService = service.NewCurrencyService(logger)
 	runtime.LogInfo(a.ctx, "Currency service initialized successfully")
 
+	// An archive export or import interrupted by a crash leaves a plain copy
+	// of the database in the backup directory.
+	a.productService.RemoveStaleArchiveFiles()
+
 	maxRetries := 3
 	retryDelay := time.Second * 2
 
 	for attempt := 1; attempt <= maxRetries; attempt++ {
 		err := a.productService.InitDatabase()
 		if err == nil {
-			a.dbHealthy = true
-			a.dbError = ""
+			a.setDatabaseStatus("")
 			runtime.LogInfo(a.ctx, fmt.Sprintf("Database initialized successfully on attempt %d", attempt))
 			return
 		}
 
 		runtime.LogError(a.ctx, fmt.Sprintf("Database initialization attempt %d/%d failed: %v", attempt, maxRetries, err))
-		a.dbHealthy = false
-		a.dbError = fmt.Sprintf("Database initialization failed: %v", err)
+		a.setDatabaseStatus(fmt.Sprintf("Database initialization failed: %v", err))
 
 		if attempt < maxRetries {
 			runtime.LogInfo(a.ctx, fmt.Sprintf("Waiting %v before next attempt...", retryDelay))
@@ -88,7 +128,9 @@ func (a *App) beforeClose(_ context.Context) (prevent bool) {
 	if a.productService != nil {
 		runtime.LogInfo(a.ctx, "Preparing database for shutdown...")
 		// Perform a health check to ensure database is in a good state
-		err := a.productService.HealthCheck()
+		ctx, cancel := context.WithTimeout(a.ctx, healthCheckTimeout)
+		err := a.productService.HealthCheck(ctx)
+		cancel()
 		if err != nil {
 			runtime.LogWarning(a.ctx, fmt.Sprintf("Database health check failed during shutdown: %v", err))
 		}
@@ -127,10 +169,37 @@ func (a *App) Greet(name string) string {
 
 // GetDatabaseStatus returns the current status of the database
 func (a *App) GetDatabaseStatus() map[string]interface{} {
-	return map[string]interface{}{
-		"healthy": a.dbHealthy,
-		"error":   a.dbError,
+	healthy, dbError := a.databaseStatus()
+	status := map[string]interface{}{
+		"healthy":        healthy,
+		"error":          dbError,
+		"fullTextSearch": a.productService != nil && a.productService.FullTextSearchAvailable(),
+		"path":           a.config.Database.Path,
+		"pathSource":     a.config.Database.PathSource,
+		"configFile":     a.config.File,
 	}
+
+	if a.productService != nil {
+		if schema, err := a.productService.SchemaStatus(); err == nil {
+			status["schemaVersion"] = schema.CurrentVersion
+			status["latestSchemaVersion"] = schema.LatestVersion
+		}
+		ctx, done := a.callContext(queryTimeout)
+		defer done()
+		if settings, err := a.productService.DatabaseSettings(ctx); err == nil {
+			status["settings"] = settings
+		}
+	}
+	return status
+}
+
+// GetSchemaStatus returns the database schema version and migration history.
+func (a *App) GetSchemaStatus() (*dto.SchemaStatusDTO, error) {
+	if err := a.checkDatabaseHealth(); err != nil {
+		runtime.LogError(a.ctx, fmt.Sprintf("GetSchemaStatus failed: %v", err))
+		return nil, err
+	}
+	return a.productService.SchemaStatus()
 }
 
 // RetryDatabaseConnection tries to reconnect to the database
@@ -139,17 +208,16 @@ func (a *App) RetryDatabaseConnection() map[string]interface{} {
 
 	err := a.productService.InitDatabase()
 	if err != nil {
-		a.dbHealthy = false
-		a.dbError = fmt.Sprintf("Reconnection failed: %v", err)
-		runtime.LogError(a.ctx, fmt.Sprintf("Reconnection failed: %v", err))
+		dbError := fmt.Sprintf("Reconnection failed: %v", err)
+		a.setDatabaseStatus(dbError)
+		runtime.LogError(a.ctx, dbError)
 		return map[string]interface{}{
 			"success": false,
-			"error":   a.dbError,
+			"error":   dbError,
 		}
 	}
 
-	a.dbHealthy = true
-	a.dbError = ""
+	a.setDatabaseStatus("")
 	runtime.LogInfo(a.ctx, "Database reconnection successful!")
 	return map[string]interface{}{
 		"success": true,
@@ -157,21 +225,321 @@ func (a *App) RetryDatabaseConnection() map[string]interface{} {
 	}
 }
 
+// StartMaintenance runs integrityCheck, quickCheck, vacuum, analyze or stats
+// as a background job. Progress is emitted as "maintenance:job" events.
+func (a *App) StartMaintenance(task dto.MaintenanceTask) (*dto.MaintenanceJobDTO, error) {
+	if err := a.checkDatabaseHealth(); err != nil {
+		runtime.LogError(a.ctx, fmt.Sprintf("StartMaintenance failed: %v", err))
+		return nil, err
+	}
+	return a.productService.StartMaintenance(task)
+}
+
+// CancelMaintenance stops a running maintenance job.
+func (a *App) CancelMaintenance(id string) error {
+	if err := a.checkDatabaseHealth(); err != nil {
+		runtime.LogError(a.ctx, fmt.Sprintf("CancelMaintenance failed: %v", err))
+		return err
+	}
+	return a.productService.CancelMaintenance(id)
+}
+
+// GetMaintenanceJob returns the state of a maintenance job, including its
+// result once it has completed.
+func (a *App) GetMaintenanceJob(id string) (*dto.MaintenanceJobDTO, error) {
+	if err := a.checkDatabaseHealth(); err != nil {
+		runtime.LogError(a.ctx, fmt.Sprintf("GetMaintenanceJob failed: %v", err))
+		return nil, err
+	}
+	return a.productService.MaintenanceJob(id)
+}
+
+// GetMaintenanceJobs returns the running and recently finished maintenance jobs.
+func (a *App) GetMaintenanceJobs() []dto.MaintenanceJobDTO {
+	if a.productService == nil {
+		return []dto.MaintenanceJobDTO{}
+	}
+	return a.productService.MaintenanceJobs()
+}
+
+// BackupDatabase asks where to save a backup of the database and writes a
+// consistent copy there while the app keeps running.
+func (a *App) BackupDatabase() (*dto.BackupDTO, error) {
+	if err := a.checkDatabaseHealth(); err != nil {
+		runtime.LogError(a.ctx, fmt.Sprintf("BackupDatabase failed: %v", err))
+		return nil, err
+	}
+
+	filename := fmt.Sprintf("products_backup_%s.db", time.Now().Format("2006-01-02"))
+	filePath, err := runtime.SaveFileDialog(a.ctx, runtime.SaveDialogOptions{
+		Title:           "Save Database Backup",
+		DefaultFilename: filename,
+		Filters: []runtime.FileFilter{
+			{
+				DisplayName: "Database Backups (*.db)",
+				Pattern:     "*.db",
+			},
+		},
+	})
+
+	if err != nil {
+		runtime.LogError(a.ctx, fmt.Sprintf("BackupDatabase dialog error: %v", err))
+		return nil, err
+	}
+
+	if filePath == "" {
+		return nil, errCancelledByUser
+	}
+
+	ctx, done := a.callContext(longCallTimeout)
+	defer done()
+	return a.productService.BackupDatabase(ctx, filePath)
+}
+
+// GetBackups returns the scheduled backups, newest first.
+func (a *App) GetBackups() ([]dto.BackupDTO, error) {
+	if err := a.checkDatabaseHealth(); err != nil {
+		runtime.LogError(a.ctx, fmt.Sprintf("GetBackups failed: %v", err))
+		return nil, err
+	}
+	return a.productService.ListBackups()
+}
+
+// RestoreDatabase asks for a backup file and restores it.
+func (a *App) RestoreDatabase() (*dto.RestoreResultDTO, error) {
+	filePath, err := runtime.OpenFileDialog(a.ctx, runtime.OpenDialogOptions{
+		Title:            "Restore Database Backup",
+		DefaultDirectory: a.config.Database.Backup.Dir,
+		Filters: []runtime.FileFilter{
+			{
+				DisplayName: "Database Backups (*.db)",
+				Pattern:     "*.db",
+			},
+		},
+	})
+
+	if err != nil {
+		runtime.LogError(a.ctx, fmt.Sprintf("RestoreDatabase dialog error: %v", err))
+		return nil, err
+	}
+
+	if filePath == "" {
+		return nil, errCancelledByUser
+	}
+
+	return a.RestoreBackup(filePath)
+}
+
+// RestoreBackup replaces the database with the backup at path. The calls in
+// progress are cancelled and new ones rejected as unavailable while the
+// database is swapped.
+func (a *App) RestoreBackup(path string) (*dto.RestoreResultDTO, error) {
+	if err := a.checkDatabaseHealth(); err != nil {
+		runtime.LogError(a.ctx, fmt.Sprintf("RestoreBackup failed: %v", err))
+		return nil, err
+	}
+
+	var result *dto.RestoreResultDTO
+	err := a.swapDatabase(func(ctx context.Context) error {
+		var err error
+		result, err = a.productService.RestoreBackup(ctx, path)
+		return err
+	})
+	if err != nil {
+		runtime.LogError(a.ctx, fmt.Sprintf("RestoreBackup failed: %v", err))
+		return nil, err
+	}
+	return result, nil
+}
+
+// ExportEncryptedBackup asks where to save an encrypted archive of the
+// database and writes it, protected by passphrase.
+func (a *App) ExportEncryptedBackup(passphrase string) (*dto.BackupDTO, error) {
+	if err := a.checkDatabaseHealth(); err != nil {
+		runtime.LogError(a.ctx, fmt.Sprintf("ExportEncryptedBackup failed: %v", err))
+		return nil, err
+	}
+	if err := dto.ValidatePassphrase(passphrase); err != nil {
+		return nil, err
+	}
+
+	filename := fmt.Sprintf("products_backup_%s.pmarchive", time.Now().Format("2006-01-02"))
+	filePath, err := runtime.SaveFileDialog(a.ctx, runtime.SaveDialogOptions{
+		Title:           "Save Encrypted Backup",
+		DefaultFilename: filename,
+		Filters: []runtime.FileFilter{
+			{
+				DisplayName: "Encrypted Backups (*.pmarchive)",
+				Pattern:     "*.pmarchive",
+			},
+		},
+	})
+
+	if err != nil {
+		runtime.LogError(a.ctx, fmt.Sprintf("ExportEncryptedBackup dialog error: %v", err))
+		return nil, err
+	}
+
+	if filePath == "" {
+		return nil, errCancelledByUser
+	}
+
+	ctx, done := a.callContext(longCallTimeout)
+	defer done()
+	return a.productService.ExportArchive(ctx, filePath, passphrase)
+}
+
+// ImportEncryptedBackup asks for an encrypted archive, verifies it with
+// passphrase and then replaces the database with it or merges it in,
+// depending on mode ("replace" or "merge").
+func (a *App) ImportEncryptedBackup(passphrase string, mode dto.ArchiveImportMode) (*dto.ArchiveImportResult, error) {
+	if err := a.checkDatabaseHealth(); err != nil {
+		runtime.LogError(a.ctx, fmt.Sprintf("ImportEncryptedBackup failed: %v", err))
+		return nil, err
+	}
+	if err := mode.Validate(); err != nil {
+		return nil, err
+	}
+
+	filePath, err := runtime.OpenFileDialog(a.ctx, runtime.OpenDialogOptions{
+		Title: "Import Encrypted Backup",
+		Filters: []runtime.FileFilter{
+			{
+				DisplayName: "Encrypted Backups (*.pmarchive)",
+				Pattern:     "*.pmarchive",
+			},
+		},
+	})
+
+	if err != nil {
+		runtime.LogError(a.ctx, fmt.Sprintf("ImportEncryptedBackup dialog error: %v", err))
+		return nil, err
+	}
+
+	if filePath == "" {
+		return nil, errCancelledByUser
+	}
+
+	if mode == dto.ArchiveMerge {
+		ctx, done := a.callContext(longCallTimeout)
+		defer done()
+		return a.productService.ImportArchive(ctx, filePath, passphrase, mode)
+	}
+
+	var result *dto.ArchiveImportResult
+	err = a.swapDatabase(func(ctx context.Context) error {
+		result, err = a.productService.ImportArchive(ctx, filePath, passphrase, mode)
+		return err
+	})
+	if err != nil {
+		runtime.LogError(a.ctx, fmt.Sprintf("ImportEncryptedBackup failed: %v", err))
+		return nil, err
+	}
+	return result, nil
+}
+
+// swapDatabase runs swap, which closes and reopens the database, with new
+// calls rejected as unavailable until it is done. The calls in progress are
+// cancelled first, so the swap does not wait for a long import to finish;
+// the product service holds the swap back until they have returned.
+func (a *App) swapDatabase(swap func(ctx context.Context) error) error {
+	a.setDatabaseStatus("restore in progress")
+	if cancelled := a.cancelCalls(); cancelled > 0 {
+		runtime.LogInfo(a.ctx, fmt.Sprintf("Cancelled %d operations before swapping the database", cancelled))
+	}
+
+	ctx, done := a.callContext(longCallTimeout)
+	err := swap(ctx)
+	done()
+
+	healthCtx, cancel := context.WithTimeout(a.ctx, healthCheckTimeout)
+	defer cancel()
+	if healthErr := a.productService.HealthCheck(healthCtx); healthErr != nil {
+		a.setDatabaseStatus(fmt.Sprintf("Database unavailable after restore: %v", healthErr))
+	} else {
+		a.setDatabaseStatus("")
+	}
+	return err
+}
+
+// setDatabaseStatus marks the database healthy, or unavailable because of
+// dbError when it is not empty.
+func (a *App) setDatabaseStatus(dbError string) {
+	a.dbMu.Lock()
+	defer a.dbMu.Unlock()
+	a.dbHealthy = dbError == ""
+	a.dbError = dbError
+}
+
+func (a *App) databaseStatus() (healthy bool, dbError string) {
+	a.dbMu.RLock()
+	defer a.dbMu.RUnlock()
+	return a.dbHealthy, a.dbError
+}
+
 // checkDatabaseHealth verifies if the database is healthy before operations
 func (a *App) checkDatabaseHealth() error {
-	if !a.dbHealthy {
-		return fmt.Errorf("database is not available: %s", a.dbError)
+	if healthy, dbError := a.databaseStatus(); !healthy {
+		return apperrors.Unavailable(nil, "database is not available: %s", dbError)
 	}
 	return nil
 }
 
+// callContext returns the context for one call from the frontend. It is
+// derived from the app context, so it carries the Wails runtime, and is
+// cancelled after timeout or by CancelOperations. done must be called when
+// the call returns.
+func (a *App) callContext(timeout time.Duration) (ctx context.Context, done func()) {
+	ctx, cancel := context.WithTimeout(a.ctx, timeout)
+
+	a.callsMu.Lock()
+	defer a.callsMu.Unlock()
+	if a.calls == nil {
+		a.calls = make(map[int]context.CancelFunc)
+	}
+	a.nextCall++
+	id := a.nextCall
+	a.calls[id] = cancel
+
+	return ctx, func() {
+		a.callsMu.Lock()
+		delete(a.calls, id)
+		a.callsMu.Unlock()
+		cancel()
+	}
+}
+
+// CancelOperations aborts every product, import, export and backup call in
+// progress, such as a query stuck in a large import, and returns how many
+// were cancelled. The aborted calls fail with a cancellation error; a bulk
+// operation or archive merge that was cut short is rolled back, while the
+// chunks an import had already committed are kept.
+func (a *App) CancelOperations() int {
+	cancelled := a.cancelCalls()
+	runtime.LogInfo(a.ctx, fmt.Sprintf("Cancelled %d operations in progress", cancelled))
+	return cancelled
+}
+
+// cancelCalls cancels the contexts of the calls in progress and returns how
+// many there were.
+func (a *App) cancelCalls() int {
+	a.callsMu.Lock()
+	defer a.callsMu.Unlock()
+	for _, cancel := range a.calls {
+		cancel()
+	}
+	return len(a.calls)
+}
+
 // CreateProduct creates a new product using the provided DTO.
 func (a *App) CreateProduct(createProductDTO dto.CreateProductDTO) (*models.Product, error) {
 	if err := a.checkDatabaseHealth(); err != nil {
 		runtime.LogError(a.ctx, fmt.Sprintf("CreateProduct failed: %v", err))
 		return nil, err
 	}
-	return a.productService.CreateProduct(createProductDTO)
+	ctx, done := a.callContext(queryTimeout)
+	defer done()
+	return a.productService.CreateProduct(ctx, createProductDTO)
 }
 
 // GetProduct retrieves a product by its ID.
@@ -180,34 +548,113 @@ func (a *App) GetProduct(id int) (*models.Product, error) {
 		runtime.LogError(a.ctx, fmt.Sprintf("GetProduct failed: %v", err))
 		return nil, err
 	}
-	return a.productService.GetProductByID(id)
+	ctx, done := a.callContext(queryTimeout)
+	defer done()
+	return a.productService.GetProductByID(ctx, id)
 }
 
-// GetAllProducts retrieves all products with pagination.
+// GetAllProducts retrieves products with pagination, search, sorting and filters.
 func (a *App) GetAllProducts(params dto.PaginationDTO) (*dto.PaginationResponse, error) {
 	if err := a.checkDatabaseHealth(); err != nil {
 		runtime.LogError(a.ctx, fmt.Sprintf("GetAllProducts failed: %v", err))
 		return nil, err
 	}
-	return a.productService.GetAllProducts(params)
+	ctx, done := a.callContext(queryTimeout)
+	defer done()
+	return a.productService.GetAllProducts(ctx, params)
+}
+
+// SearchProducts runs a ranked full-text search over product names,
+// descriptions and categories.
+func (a *App) SearchProducts(params dto.ProductSearchDTO) (*dto.ProductSearchResponse, error) {
+	if err := a.checkDatabaseHealth(); err != nil {
+		runtime.LogError(a.ctx, fmt.Sprintf("SearchProducts failed: %v", err))
+		return nil, err
+	}
+	ctx, done := a.callContext(queryTimeout)
+	defer done()
+	return a.productService.SearchProducts(ctx, params)
 }
 
-// UpdateProduct updates an existing product with new name and price.
-func (a *App) UpdateProduct(id int, name string, price float64) (*models.Product, error) {
+// UpdateProduct applies a partial update to an existing product. Only the
+// fields present in the DTO are changed.
+func (a *App) UpdateProduct(id int, update dto.UpdateProductDTO) (*models.Product, error) {
 	if err := a.checkDatabaseHealth(); err != nil {
 		runtime.LogError(a.ctx, fmt.Sprintf("UpdateProduct failed: %v", err))
 		return nil, err
 	}
-	return a.productService.UpdateProduct(id, name, price)
+	ctx, done := a.callContext(queryTimeout)
+	defer done()
+	return a.productService.UpdateProduct(ctx, id, update)
 }
 
-// DeleteProduct removes a product by its ID.
-func (a *App) DeleteProduct(id int) error {
+// DeleteProduct moves a product to the trash. The version must match the
+// version the caller last read.
+func (a *App) DeleteProduct(id, version int) error {
 	if err := a.checkDatabaseHealth(); err != nil {
 		runtime.LogError(a.ctx, fmt.Sprintf("DeleteProduct failed: %v", err))
 		return err
 	}
-	return a.productService.DeleteProduct(id)
+	ctx, done := a.callContext(queryTimeout)
+	defer done()
+	return a.productService.DeleteProduct(ctx, id, version)
+}
+
+// ApplyBulkOperation applies one operation to the selected products
+// atomically and reports the outcome for each of them.
+func (a *App) ApplyBulkOperation(op dto.BulkOperationDTO) (*dto.BulkOperationResult, error) {
+	if err := a.checkDatabaseHealth(); err != nil {
+		runtime.LogError(a.ctx, fmt.Sprintf("ApplyBulkOperation failed: %v", err))
+		return nil, err
+	}
+	ctx, done := a.callContext(longCallTimeout)
+	defer done()
+	return a.productService.ApplyBulkOperation(ctx, op)
+}
+
+// GetTrash retrieves trashed products with pagination.
+func (a *App) GetTrash(params dto.PaginationDTO) (*dto.PaginationResponse, error) {
+	if err := a.checkDatabaseHealth(); err != nil {
+		runtime.LogError(a.ctx, fmt.Sprintf("GetTrash failed: %v", err))
+		return nil, err
+	}
+	ctx, done := a.callContext(queryTimeout)
+	defer done()
+	return a.productService.GetTrash(ctx, params)
+}
+
+// RestoreProduct moves a trashed product back into the catalog.
+func (a *App) RestoreProduct(id, version int) (*models.Product, error) {
+	if err := a.checkDatabaseHealth(); err != nil {
+		runtime.LogError(a.ctx, fmt.Sprintf("RestoreProduct failed: %v", err))
+		return nil, err
+	}
+	ctx, done := a.callContext(queryTimeout)
+	defer done()
+	return a.productService.RestoreProduct(ctx, id, version)
+}
+
+// PurgeProduct permanently removes a single product from the trash.
+func (a *App) PurgeProduct(id int) error {
+	if err := a.checkDatabaseHealth(); err != nil {
+		runtime.LogError(a.ctx, fmt.Sprintf("PurgeProduct failed: %v", err))
+		return err
+	}
+	ctx, done := a.callContext(queryTimeout)
+	defer done()
+	return a.productService.PurgeProduct(ctx, id)
+}
+
+// PurgeTrash permanently removes every product that has been in the trash
+// for at least olderThanDays days and returns how many were removed.
+func (a *App) PurgeTrash(olderThanDays int) (int, error) {
+	if err := a.checkDatabaseHealth(); err != nil {
+		runtime.LogError(a.ctx, fmt.Sprintf("PurgeTrash failed: %v", err))
+		return 0, err
+	}
+	ctx, done := a.callContext(queryTimeout)
+	defer done()
+	return a.productService.PurgeTrash(ctx, olderThanDays)
 }
 
 func (a *App) ExportProductsToCSV(includeAll bool, productIDs []int) (string, error) {
@@ -216,7 +663,10 @@ func (a *App) ExportProductsToCSV(includeAll bool, productIDs []int) (string, er
 		return "", err
 	}
 
-	data, err := a.productService.ExportProductsToCSV(includeAll, productIDs)
+	ctx, done := a.callContext(longCallTimeout)
+	defer done()
+
+	data, err := a.productService.ExportProductsToCSV(ctx, includeAll, productIDs)
 	if err != nil {
 		return "", err
 	}
@@ -230,7 +680,10 @@ func (a *App) ExportProductsToXLSX(includeAll bool, productIDs []int) (string, e
 		return "", err
 	}
 
-	data, err := a.productService.ExportProductsToXLSX(includeAll, productIDs)
+	ctx, done := a.callContext(longCallTimeout)
+	defer done()
+
+	data, err := a.productService.ExportProductsToXLSX(ctx, includeAll, productIDs)
 	if err != nil {
 		return "", err
 	}
@@ -238,45 +691,145 @@ func (a *App) ExportProductsToXLSX(includeAll bool, productIDs []int) (string, e
 	return string(data), nil
 }
 
-func (a *App) ImportProductsFromCSV(csvData string) (*dto.ImportResult, error) {
+// ImportProductsFromCSV asks for a CSV file and imports its products,
+// reading the file as it goes. Columns are found by their header; options
+// may map headers the app does not know and choose whether rows create
+// products, update matching ones, or both.
+func (a *App) ImportProductsFromCSV(options dto.ImportOptions) (*dto.ImportResult, error) {
 	if err := a.checkDatabaseHealth(); err != nil {
 		runtime.LogError(a.ctx, fmt.Sprintf("ImportProductsFromCSV failed: %v", err))
 		return nil, err
 	}
 
-	return a.productService.ImportProductsFromCSV([]byte(csvData))
+	filePath, err := runtime.OpenFileDialog(a.ctx, runtime.OpenDialogOptions{
+		Title: "Import Products",
+		Filters: []runtime.FileFilter{
+			{
+				DisplayName: "CSV Files (*.csv)",
+				Pattern:     "*.csv",
+			},
+		},
+	})
+
+	if err != nil {
+		runtime.LogError(a.ctx, fmt.Sprintf("ImportProductsFromCSV dialog error: %v", err))
+		return nil, err
+	}
+
+	if filePath == "" {
+		return nil, errCancelledByUser
+	}
+
+	ctx, done := a.callContext(longCallTimeout)
+	defer done()
+	return a.productService.ImportProductsFromCSV(ctx, filePath, options)
 }
 
-func (a *App) ImportProductsFromXLSX(xlsxData string) (*dto.ImportResult, error) {
+// ImportProductsFromXLSX asks for an XLSX file and imports the products of
+// its first sheet, finding columns like ImportProductsFromCSV.
+func (a *App) ImportProductsFromXLSX(options dto.ImportOptions) (*dto.ImportResult, error) {
 	if err := a.checkDatabaseHealth(); err != nil {
 		runtime.LogError(a.ctx, fmt.Sprintf("ImportProductsFromXLSX failed: %v", err))
 		return nil, err
 	}
 
-	data, err := base64.StdEncoding.DecodeString(xlsxData)
+	filePath, err := runtime.OpenFileDialog(a.ctx, runtime.OpenDialogOptions{
+		Title: "Import Products",
+		Filters: []runtime.FileFilter{
+			{
+				DisplayName: "Excel Files (*.xlsx)",
+				Pattern:     "*.xlsx",
+			},
+		},
+	})
+
 	if err != nil {
-		runtime.LogError(a.ctx, fmt.Sprintf("Failed to decode XLSX base64 data: %v", err))
-		return nil, fmt.Errorf("invalid XLSX data format: %v", err)
+		runtime.LogError(a.ctx, fmt.Sprintf("ImportProductsFromXLSX dialog error: %v", err))
+		return nil, err
+	}
+
+	if filePath == "" {
+		return nil, errCancelledByUser
+	}
+
+	ctx, done := a.callContext(longCallTimeout)
+	defer done()
+	return a.productService.ImportProductsFromXLSX(ctx, filePath, options)
+}
+
+// CommitImport applies the plan of an import previewed with
+// options.dryRun. The preview ID comes from the preview's result.
+func (a *App) CommitImport(previewID string) (*dto.ImportResult, error) {
+	if err := a.checkDatabaseHealth(); err != nil {
+		runtime.LogError(a.ctx, fmt.Sprintf("CommitImport failed: %v", err))
+		return nil, err
+	}
+
+	ctx, done := a.callContext(longCallTimeout)
+	defer done()
+	return a.productService.CommitImport(ctx, previewID)
+}
+
+// DiscardImportPreview forgets a previewed import the user did not confirm.
+func (a *App) DiscardImportPreview(previewID string) error {
+	if err := a.checkDatabaseHealth(); err != nil {
+		return err
+	}
+	a.productService.DiscardImportPreview(previewID)
+	return nil
+}
+
+// CancelImport stops the running import with the ID its progress events
+// carry. The chunks a non-atomic import already committed are kept and can
+// be rolled back with its batch.
+func (a *App) CancelImport(id string) error {
+	if err := a.checkDatabaseHealth(); err != nil {
+		runtime.LogError(a.ctx, fmt.Sprintf("CancelImport failed: %v", err))
+		return err
+	}
+	return a.productService.CancelImport(id)
+}
+
+// GetImportBatches returns the most recent committed imports, newest first.
+func (a *App) GetImportBatches(limit int) ([]*models.ImportBatch, error) {
+	if err := a.checkDatabaseHealth(); err != nil {
+		return nil, err
+	}
+
+	ctx, done := a.callContext(queryTimeout)
+	defer done()
+	return a.productService.ListImportBatches(ctx, limit)
+}
+
+// RollbackImport undoes a committed import batch.
+func (a *App) RollbackImport(batchID int) (*models.ImportBatch, error) {
+	if err := a.checkDatabaseHealth(); err != nil {
+		runtime.LogError(a.ctx, fmt.Sprintf("RollbackImport failed: %v", err))
+		return nil, err
 	}
 
-	return a.productService.ImportProductsFromXLSX(data)
+	ctx, done := a.callContext(longCallTimeout)
+	defer done()
+	return a.productService.RollbackImport(ctx, batchID)
 }
 
 func (a *App) GetImportTemplate() string {
-	template := "Name,Price,Category,Stock,Description,Image URL\n"
-	template += "Example Product,29.99,Electronics,10,Example product description,https://example.com/image.jpg\n"
-	template += "Another Product,49.90,Home & Garden,5,Another example product,\n"
+	template := "SKU,Name,Price,Category,Stock,Description,Image URL\n"
+	template += "EX-001,Example Product,29.99,Electronics,10,Example product description,https://example.com/image.jpg\n"
+	template += "EX-002,Another Product,49.90,Home & Garden,5,Another example product,\n"
 
 	return template
 }
 
 func (a *App) SaveExportedCSV(includeAll bool, productIDs []int) error {
-	if !a.dbHealthy {
+	if healthy, _ := a.databaseStatus(); !healthy {
 		runtime.LogError(a.ctx, "SaveExportedCSV failed: database not healthy")
-		return fmt.Errorf("database connection is not healthy")
+		return apperrors.Unavailable(nil, "database connection is not healthy")
 	}
 
-	data, err := a.productService.ExportProductsToCSV(includeAll, productIDs)
+	ctx, done := a.callContext(longCallTimeout)
+	data, err := a.productService.ExportProductsToCSV(ctx, includeAll, productIDs)
+	done()
 	if err != nil {
 		runtime.LogError(a.ctx, fmt.Sprintf("SaveExportedCSV failed to generate data: %v", err))
 		return err
@@ -300,13 +853,13 @@ func (a *App) SaveExportedCSV(includeAll bool, productIDs []int) error {
 	}
 
 	if filePath == "" {
-		return fmt.Errorf("operation cancelled by user")
+		return errCancelledByUser
 	}
 
 	err = os.WriteFile(filePath, data, 0600)
 	if err != nil {
 		runtime.LogError(a.ctx, fmt.Sprintf("SaveExportedCSV write error: %v", err))
-		return fmt.Errorf("error saving file: %v", err)
+		return fmt.Errorf("error saving file: %w", err)
 	}
 
 	runtime.LogInfo(a.ctx, fmt.Sprintf("CSV exported successfully to: %s", filePath))
@@ -314,12 +867,14 @@ func (a *App) SaveExportedCSV(includeAll bool, productIDs []int) error {
 }
 
 func (a *App) SaveExportedXLSX(includeAll bool, productIDs []int) error {
-	if !a.dbHealthy {
+	if healthy, _ := a.databaseStatus(); !healthy {
 		runtime.LogError(a.ctx, "SaveExportedXLSX failed: database not healthy")
-		return fmt.Errorf("database connection is not healthy")
+		return apperrors.Unavailable(nil, "database connection is not healthy")
 	}
 
-	data, err := a.productService.ExportProductsToXLSX(includeAll, productIDs)
+	ctx, done := a.callContext(longCallTimeout)
+	data, err := a.productService.ExportProductsToXLSX(ctx, includeAll, productIDs)
+	done()
 	if err != nil {
 		runtime.LogError(a.ctx, fmt.Sprintf("SaveExportedXLSX failed to generate data: %v", err))
 		return err
@@ -343,13 +898,13 @@ func (a *App) SaveExportedXLSX(includeAll bool, productIDs []int) error {
 	}
 
 	if filePath == "" {
-		return fmt.Errorf("operation cancelled by user")
+		return errCancelledByUser
 	}
 
 	err = os.WriteFile(filePath, data, 0600)
 	if err != nil {
 		runtime.LogError(a.ctx, fmt.Sprintf("SaveExportedXLSX write error: %v", err))
-		return fmt.Errorf("error saving file: %v", err)
+		return fmt.Errorf("error saving file: %w", err)
 	}
 
 	runtime.LogInfo(a.ctx, fmt.Sprintf("XLSX exported successfully to: %s", filePath))
@@ -376,13 +931,13 @@ func (a *App) SaveImportTemplate() error {
 	}
 
 	if filePath == "" {
-		return fmt.Errorf("operation cancelled by user")
+		return errCancelledByUser
 	}
 
 	err = os.WriteFile(filePath, []byte(template), 0600)
 	if err != nil {
 		runtime.LogError(a.ctx, fmt.Sprintf("SaveImportTemplate write error: %v", err))
-		return fmt.Errorf("error saving template: %v", err)
+		return fmt.Errorf("error saving template: %w", err)
 	}
 
 	runtime.LogInfo(a.ctx, fmt.Sprintf("Template saved successfully to: %s", filePath))
@@ -391,7 +946,7 @@ func (a *App) SaveImportTemplate() error {
 
 // ConvertCurrency converts an amount from one currency to another
 func (a *App) ConvertCurrency(request dto.CurrencyConversionRequest) (*dto.CurrencyConversionResponse, error) {
-	runtime.LogInfo(a.ctx, fmt.Sprintf("ConvertCurrency called: %.2f %s to %s", request.Amount, request.FromCurrency, request.ToCurrency))
+	runtime.LogInfo(a.ctx, fmt.Sprintf("ConvertCurrency called: %s %s to %s", request.Amount, request.FromCurrency, request.ToCurrency))
 	return a.currencyService.ConvertCurrency(request)
 }
 
@@ -0,0 +1,265 @@
+// Package archive reads and writes passphrase-protected backup archives.
+//
+// An archive is a fixed header followed by the payload split into chunks,
+// each sealed with AES-256-GCM. The key is derived from the passphrase with
+// Argon2id using the parameters and salt stored in the header. The header
+// carries an HMAC so a wrong passphrase is told apart from a damaged file,
+// and every chunk authenticates the header as additional data. The chunk
+// counter and a final-chunk flag are encoded in the nonce, so reordered,
+// dropped or truncated chunks fail to decrypt.
+package archive
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+
+	"golang.org/x/crypto/argon2"
+)
+
+// Version is the format version written by Encrypt.
+const Version = 1
+
+const kdfArgon2id = 1
+
+var magic = [8]byte{'P', 'M', 'A', 'R', 'C', 'H', 'I', 'V'}
+
+// Argon2id parameters used for new archives. Archives record their own
+// parameters, so these can be raised without breaking old files.
+const (
+	defaultTime    = 3
+	defaultMemory  = 64 * 1024 // KiB
+	defaultThreads = 4
+
+	// Limits on the parameters accepted from a header, so a crafted file
+	// cannot make the KDF exhaust memory or run for minutes.
+	maxTime    = 16
+	maxMemory  = 1024 * 1024 // KiB
+	maxThreads = 64
+)
+
+const (
+	saltSize     = 16
+	keySize      = 32
+	macSize      = sha256.Size
+	chunkSize    = 64 * 1024
+	maxChunkSize = 16 * 1024 * 1024
+	// headerSize is magic, version, KDF, time, memory, threads, salt and
+	// chunk size, followed by the MAC.
+	headerSize = len(magic) + 1 + 1 + 4 + 4 + 1 + saltSize + 4
+)
+
+var (
+	// ErrNotArchive is returned for input that does not start with an
+	// archive header.
+	ErrNotArchive = errors.New("not a backup archive")
+	// ErrWrongPassphrase is returned when the header MAC does not match the
+	// key derived from the passphrase.
+	ErrWrongPassphrase = errors.New("wrong passphrase")
+	// ErrCorrupt is returned when the payload fails authentication or ends
+	// early.
+	ErrCorrupt = errors.New("archive is corrupted or was modified")
+)
+
+type header struct {
+	version   uint8
+	kdf       uint8
+	time      uint32
+	memory    uint32
+	threads   uint8
+	salt      [saltSize]byte
+	chunkSize uint32
+}
+
+func (h *header) marshal() []byte {
+	buf := make([]byte, 0, headerSize)
+	buf = append(buf, magic[:]...)
+	buf = append(buf, h.version, h.kdf)
+	buf = binary.BigEndian.AppendUint32(buf, h.time)
+	buf = binary.BigEndian.AppendUint32(buf, h.memory)
+	buf = append(buf, h.threads)
+	buf = append(buf, h.salt[:]...)
+	buf = binary.BigEndian.AppendUint32(buf, h.chunkSize)
+	return buf
+}
+
+func unmarshalHeader(buf []byte) (*header, error) {
+	if !bytes.Equal(buf[:len(magic)], magic[:]) {
+		return nil, ErrNotArchive
+	}
+	h := &header{}
+	rest := buf[len(magic):]
+	h.version, h.kdf = rest[0], rest[1]
+	if h.version != Version {
+		return nil, fmt.Errorf("unsupported archive version %d", h.version)
+	}
+	if h.kdf != kdfArgon2id {
+		return nil, fmt.Errorf("unsupported key derivation function %d", h.kdf)
+	}
+	h.time = binary.BigEndian.Uint32(rest[2:])
+	h.memory = binary.BigEndian.Uint32(rest[6:])
+	h.threads = rest[10]
+	copy(h.salt[:], rest[11:11+saltSize])
+	h.chunkSize = binary.BigEndian.Uint32(rest[11+saltSize:])
+
+	if h.time == 0 || h.time > maxTime || h.memory == 0 || h.memory > maxMemory || h.threads == 0 || h.threads > maxThreads {
+		return nil, fmt.Errorf("archive key derivation parameters are out of range")
+	}
+	if h.chunkSize == 0 || h.chunkSize > maxChunkSize {
+		return nil, fmt.Errorf("archive chunk size %d is out of range", h.chunkSize)
+	}
+	return h, nil
+}
+
+// deriveKeys returns the payload encryption key and the header MAC key.
+func (h *header) deriveKeys(passphrase []byte) (cipher.AEAD, []byte, error) {
+	keys := argon2.IDKey(passphrase, h.salt[:], h.time, h.memory, h.threads, 2*keySize)
+	block, err := aes.NewCipher(keys[:keySize])
+	if err != nil {
+		return nil, nil, err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, nil, err
+	}
+	return aead, keys[keySize:], nil
+}
+
+func headerMAC(key, header []byte) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write(header)
+	return mac.Sum(nil)
+}
+
+// chunkNonce encodes the chunk counter and whether it is the last chunk.
+func chunkNonce(size int, counter uint64, final bool) []byte {
+	nonce := make([]byte, size)
+	binary.BigEndian.PutUint64(nonce[size-9:], counter)
+	if final {
+		nonce[size-1] = 1
+	}
+	return nonce
+}
+
+// Encrypt reads the payload from r and writes it to w as an archive
+// protected by passphrase.
+func Encrypt(w io.Writer, r io.Reader, passphrase []byte) error {
+	if len(passphrase) == 0 {
+		return errors.New("passphrase is required")
+	}
+
+	h := &header{
+		version:   Version,
+		kdf:       kdfArgon2id,
+		time:      defaultTime,
+		memory:    defaultMemory,
+		threads:   defaultThreads,
+		chunkSize: chunkSize,
+	}
+	if _, err := rand.Read(h.salt[:]); err != nil {
+		return fmt.Errorf("failed to generate salt: %w", err)
+	}
+	aead, macKey, err := h.deriveKeys(passphrase)
+	if err != nil {
+		return fmt.Errorf("failed to derive key: %w", err)
+	}
+
+	headerBytes := h.marshal()
+	headerBytes = append(headerBytes, headerMAC(macKey, headerBytes)...)
+	if _, err := w.Write(headerBytes); err != nil {
+		return fmt.Errorf("failed to write archive header: %w", err)
+	}
+
+	// Read one chunk ahead so the last chunk can be flagged as final.
+	current := make([]byte, chunkSize)
+	next := make([]byte, chunkSize)
+	n, err := io.ReadFull(r, current)
+	if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+		return fmt.Errorf("failed to read payload: %w", err)
+	}
+	sealed := make([]byte, 0, chunkSize+aead.Overhead())
+	for counter := uint64(0); ; counter++ {
+		final := n < chunkSize
+		var m int
+		if !final {
+			m, err = io.ReadFull(r, next)
+			if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+				return fmt.Errorf("failed to read payload: %w", err)
+			}
+			final = m == 0
+		}
+
+		sealed = aead.Seal(sealed[:0], chunkNonce(aead.NonceSize(), counter, final), current[:n], headerBytes)
+		if _, err := w.Write(sealed); err != nil {
+			return fmt.Errorf("failed to write archive: %w", err)
+		}
+		if final {
+			return nil
+		}
+		current, next, n = next, current, m
+	}
+}
+
+// Decrypt reads an archive from r and writes the payload to w. The payload
+// is written as it is authenticated, so on error w holds a partial payload
+// that must be discarded.
+func Decrypt(w io.Writer, r io.Reader, passphrase []byte) error {
+	in := bufio.NewReader(r)
+
+	headerBytes := make([]byte, headerSize+macSize)
+	if _, err := io.ReadFull(in, headerBytes); err != nil {
+		if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+			return ErrNotArchive
+		}
+		return fmt.Errorf("failed to read archive header: %w", err)
+	}
+	h, err := unmarshalHeader(headerBytes[:headerSize])
+	if err != nil {
+		return err
+	}
+	aead, macKey, err := h.deriveKeys(passphrase)
+	if err != nil {
+		return fmt.Errorf("failed to derive key: %w", err)
+	}
+	if !hmac.Equal(headerMAC(macKey, headerBytes[:headerSize]), headerBytes[headerSize:]) {
+		return ErrWrongPassphrase
+	}
+
+	sealed := make([]byte, int(h.chunkSize)+aead.Overhead())
+	plain := make([]byte, 0, h.chunkSize)
+	for counter := uint64(0); ; counter++ {
+		n, err := io.ReadFull(in, sealed)
+		if err == io.EOF {
+			// The previous chunk was full but not flagged as final.
+			return ErrCorrupt
+		}
+		if err != nil && err != io.ErrUnexpectedEOF {
+			return fmt.Errorf("failed to read archive: %w", err)
+		}
+		final := n < len(sealed)
+		if !final {
+			if _, peekErr := in.Peek(1); peekErr == io.EOF {
+				final = true
+			}
+		}
+
+		plain, err = aead.Open(plain[:0], chunkNonce(aead.NonceSize(), counter, final), sealed[:n], headerBytes)
+		if err != nil {
+			return ErrCorrupt
+		}
+		if _, err := w.Write(plain); err != nil {
+			return fmt.Errorf("failed to write payload: %w", err)
+		}
+		if final {
+			return nil
+		}
+	}
+}
@@ -0,0 +1,354 @@
+// Package config resolves the runtime configuration of the product management
+// application from command-line flags, environment variables and a JSON
+// config file.
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"product-management-app/core/money"
+)
+
+// AppName names the per-user directory the application stores its files in.
+const AppName = "product-management-app"
+
+// Environment variables that override the config file.
+const (
+	EnvConfigFile   = "PRODUCT_MANAGEMENT_CONFIG"
+	EnvDatabasePath = "PRODUCT_MANAGEMENT_DB_PATH"
+)
+
+// Sources a setting can come from, in increasing order of precedence.
+const (
+	SourceDefault = "default"
+	SourceFile    = "config file"
+	SourceEnv     = "environment"
+	SourceFlag    = "flag"
+)
+
+// DefaultDatabaseFile is the database file name used inside the data directory.
+const DefaultDatabaseFile = "database.db"
+
+// LegacyDatabasePath is where versions before the per-user data directory
+// stored the database, relative to the working directory.
+const LegacyDatabasePath = "./database.db"
+
+// Config is the resolved application configuration.
+type Config struct {
+	// File is the config file that was read, or would be read if it existed.
+	File       string           `json:"-"`
+	Database   DatabaseConfig   `json:"database"`
+	Validation ValidationConfig `json:"validation"`
+}
+
+// DatabaseConfig controls where and how the SQLite database is opened.
+// Zero values are replaced by the defaults below when the config is loaded.
+type DatabaseConfig struct {
+	Path string `json:"path,omitempty"`
+	// PathSource records which source Path was taken from.
+	PathSource string `json:"-"`
+
+	// JournalMode is the SQLite journal_mode pragma.
+	JournalMode string `json:"journalMode,omitempty"`
+	// Synchronous is the SQLite synchronous pragma.
+	Synchronous string `json:"synchronous,omitempty"`
+	// BusyTimeoutMs is how long a connection waits for a lock held by another
+	// connection or process before failing with "database is locked".
+	BusyTimeoutMs int `json:"busyTimeoutMs,omitempty"`
+	// ForeignKeys enables foreign key enforcement.
+	ForeignKeys *bool `json:"foreignKeys,omitempty"`
+	// MaxOpenConns and MaxIdleConns bound the sql.DB connection pool.
+	MaxOpenConns int `json:"maxOpenConns,omitempty"`
+	MaxIdleConns int `json:"maxIdleConns,omitempty"`
+
+	Backup BackupConfig `json:"backup"`
+}
+
+// BackupConfig controls the scheduled backups of the database.
+type BackupConfig struct {
+	// Dir is where scheduled backups are written. It defaults to a backups
+	// directory next to the database.
+	Dir string `json:"dir,omitempty"`
+	// Scheduled enables the periodic backups.
+	Scheduled *bool `json:"scheduled,omitempty"`
+	// IntervalHours is the time between scheduled backups.
+	IntervalHours int `json:"intervalHours,omitempty"`
+	// Keep is how many scheduled backups are kept; older ones are deleted.
+	Keep int `json:"keep,omitempty"`
+	// KeepSafety is how many of the safety backups taken before a restore
+	// or an archive merge are kept; older ones are deleted.
+	KeepSafety int `json:"keepSafety,omitempty"`
+}
+
+// ValidationConfig sets the rules every product written to the database must
+// satisfy. Zero values are replaced by the defaults below when the config is
+// loaded; a zero MaxPrice or MaxStock means there is no upper bound.
+type ValidationConfig struct {
+	NameMinLength        int         `json:"nameMinLength,omitempty"`
+	NameMaxLength        int         `json:"nameMaxLength,omitempty"`
+	DescriptionMaxLength int         `json:"descriptionMaxLength,omitempty"`
+	MinPrice             money.Money `json:"minPrice,omitempty"`
+	MaxPrice             money.Money `json:"maxPrice,omitempty"`
+	MinStock             int         `json:"minStock,omitempty"`
+	MaxStock             int         `json:"maxStock,omitempty"`
+	// Categories, when not empty, lists the only categories a product may
+	// have. Matching ignores case.
+	Categories []string `json:"categories,omitempty"`
+}
+
+// Connection defaults. WAL lets the UI keep reading while an import writes,
+// and NORMAL synchronous is durable in WAL mode except on power loss. SQLite
+// allows a single writer, so a small pool is enough for concurrent readers.
+const (
+	DefaultJournalMode   = "WAL"
+	DefaultSynchronous   = "NORMAL"
+	DefaultBusyTimeoutMs = 5000
+	DefaultMaxOpenConns  = 4
+	DefaultMaxIdleConns  = 4
+)
+
+// Backup defaults: one backup a day, kept for a week, and the last 5 safety
+// backups.
+const (
+	DefaultBackupDir           = "backups"
+	DefaultBackupIntervalHours = 24
+	DefaultBackupKeep          = 7
+	DefaultBackupKeepSafety    = 5
+)
+
+// Validation defaults.
+const (
+	DefaultNameMinLength        = 1
+	DefaultNameMaxLength        = 200
+	DefaultDescriptionMaxLength = 2000
+)
+
+var (
+	journalModes = map[string]bool{"DELETE": true, "TRUNCATE": true, "PERSIST": true, "MEMORY": true, "WAL": true, "OFF": true}
+	syncModes    = map[string]bool{"OFF": true, "NORMAL": true, "FULL": true, "EXTRA": true}
+)
+
+// ApplyDefaults fills unset connection settings and replaces invalid ones
+// with their defaults, reporting what was replaced.
+func (d *DatabaseConfig) ApplyDefaults() error {
+	var errs []error
+
+	d.JournalMode = strings.ToUpper(d.JournalMode)
+	if d.JournalMode != "" && !journalModes[d.JournalMode] {
+		errs = append(errs, fmt.Errorf("invalid database journalMode %q, using %s", d.JournalMode, DefaultJournalMode))
+		d.JournalMode = ""
+	}
+	if d.JournalMode == "" {
+		d.JournalMode = DefaultJournalMode
+	}
+
+	d.Synchronous = strings.ToUpper(d.Synchronous)
+	if d.Synchronous != "" && !syncModes[d.Synchronous] {
+		errs = append(errs, fmt.Errorf("invalid database synchronous %q, using %s", d.Synchronous, DefaultSynchronous))
+		d.Synchronous = ""
+	}
+	if d.Synchronous == "" {
+		d.Synchronous = DefaultSynchronous
+	}
+
+	if d.BusyTimeoutMs <= 0 {
+		d.BusyTimeoutMs = DefaultBusyTimeoutMs
+	}
+	if d.ForeignKeys == nil {
+		enabled := true
+		d.ForeignKeys = &enabled
+	}
+	if d.MaxOpenConns <= 0 {
+		d.MaxOpenConns = DefaultMaxOpenConns
+	}
+	if d.MaxIdleConns <= 0 || d.MaxIdleConns > d.MaxOpenConns {
+		d.MaxIdleConns = min(DefaultMaxIdleConns, d.MaxOpenConns)
+	}
+
+	if d.Backup.Dir == "" && d.Path != "" {
+		d.Backup.Dir = filepath.Join(filepath.Dir(d.Path), DefaultBackupDir)
+	}
+	if d.Backup.Scheduled == nil {
+		enabled := true
+		d.Backup.Scheduled = &enabled
+	}
+	if d.Backup.IntervalHours <= 0 {
+		d.Backup.IntervalHours = DefaultBackupIntervalHours
+	}
+	if d.Backup.Keep <= 0 {
+		d.Backup.Keep = DefaultBackupKeep
+	}
+	if d.Backup.KeepSafety <= 0 {
+		d.Backup.KeepSafety = DefaultBackupKeepSafety
+	}
+
+	return errors.Join(errs...)
+}
+
+// ApplyDefaults fills unset validation rules and replaces contradictory ones
+// with their defaults, reporting what was replaced.
+func (v *ValidationConfig) ApplyDefaults() error {
+	var errs []error
+
+	if v.NameMinLength <= 0 {
+		v.NameMinLength = DefaultNameMinLength
+	}
+	if v.NameMaxLength <= 0 {
+		v.NameMaxLength = DefaultNameMaxLength
+	}
+	if v.NameMaxLength < v.NameMinLength {
+		errs = append(errs, fmt.Errorf("invalid validation nameMaxLength %d is below nameMinLength %d, using %d", v.NameMaxLength, v.NameMinLength, max(DefaultNameMaxLength, v.NameMinLength)))
+		v.NameMaxLength = max(DefaultNameMaxLength, v.NameMinLength)
+	}
+	if v.DescriptionMaxLength <= 0 {
+		v.DescriptionMaxLength = DefaultDescriptionMaxLength
+	}
+
+	if v.MinPrice < 0 {
+		errs = append(errs, fmt.Errorf("invalid validation minPrice %s, using 0", v.MinPrice))
+		v.MinPrice = 0
+	}
+	if v.MaxPrice < 0 || (v.MaxPrice > 0 && v.MaxPrice < v.MinPrice) {
+		errs = append(errs, fmt.Errorf("invalid validation maxPrice %s, prices will have no upper bound", v.MaxPrice))
+		v.MaxPrice = 0
+	}
+	if v.MinStock < 0 {
+		errs = append(errs, fmt.Errorf("invalid validation minStock %d, using 0", v.MinStock))
+		v.MinStock = 0
+	}
+	if v.MaxStock < 0 || (v.MaxStock > 0 && v.MaxStock < v.MinStock) {
+		errs = append(errs, fmt.Errorf("invalid validation maxStock %d, stock will have no upper bound", v.MaxStock))
+		v.MaxStock = 0
+	}
+
+	return errors.Join(errs...)
+}
+
+// knownFlags returns the args that set one of the flags defined in flags,
+// with their values, and drops the rest. The flag package stops at the first
+// argument it does not know, and macOS passes a -psn_… argument to apps
+// started from the Finder, which would hide the flags after it. Every flag
+// takes a value, either as -name=value or as the next argument.
+func knownFlags(flags *flag.FlagSet, args []string) []string {
+	var known []string
+	for i := 0; i < len(args); i++ {
+		name := strings.TrimLeft(args[i], "-")
+		if name == args[i] || len(args[i])-len(name) > 2 {
+			continue
+		}
+		name, _, hasValue := strings.Cut(name, "=")
+		if flags.Lookup(name) == nil {
+			continue
+		}
+		known = append(known, args[i])
+		if !hasValue && i+1 < len(args) {
+			i++
+			known = append(known, args[i])
+		}
+	}
+	return known
+}
+
+// DataDir returns the per-user directory the application stores its files in,
+// e.g. ~/Library/Application Support/product-management-app on macOS or
+// %AppData%\product-management-app on Windows.
+func DataDir() (string, error) {
+	base, err := os.UserConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve user config directory: %w", err)
+	}
+	return filepath.Join(base, AppName), nil
+}
+
+// Load resolves the configuration from args (without the program name), the
+// environment and the config file, in that order of precedence. Arguments
+// that are not its own flags are ignored. It always returns a usable Config;
+// an error reports a problem that was skipped, such as an unreadable config
+// file.
+func Load(args []string) (*Config, error) {
+	cfg := &Config{}
+	var errs []error
+
+	flags := flag.NewFlagSet(AppName, flag.ContinueOnError)
+	flags.SetOutput(io.Discard)
+	configFlag := flags.String("config", "", "path to the JSON config file")
+	dbPathFlag := flags.String("db-path", "", "path to the SQLite database file")
+	if err := flags.Parse(knownFlags(flags, args)); err != nil {
+		errs = append(errs, fmt.Errorf("failed to parse command-line flags: %w", err))
+	}
+
+	dataDir, err := DataDir()
+	if err != nil {
+		errs = append(errs, err)
+		dataDir = "."
+	}
+
+	cfg.File = filepath.Join(dataDir, "config.json")
+	if value := os.Getenv(EnvConfigFile); value != "" {
+		cfg.File = value
+	}
+	if *configFlag != "" {
+		cfg.File = *configFlag
+	}
+	if err := cfg.readFile(); err != nil {
+		errs = append(errs, err)
+	}
+	if cfg.Database.Path != "" {
+		cfg.Database.PathSource = SourceFile
+		// Relative paths in the config file are relative to the file itself.
+		if !filepath.IsAbs(cfg.Database.Path) {
+			cfg.Database.Path = filepath.Join(filepath.Dir(cfg.File), cfg.Database.Path)
+		}
+	}
+	if cfg.Database.Backup.Dir != "" && !filepath.IsAbs(cfg.Database.Backup.Dir) {
+		cfg.Database.Backup.Dir = filepath.Join(filepath.Dir(cfg.File), cfg.Database.Backup.Dir)
+	}
+
+	if value := os.Getenv(EnvDatabasePath); value != "" {
+		cfg.Database.Path = value
+		cfg.Database.PathSource = SourceEnv
+	}
+	if *dbPathFlag != "" {
+		cfg.Database.Path = *dbPathFlag
+		cfg.Database.PathSource = SourceFlag
+	}
+	if cfg.Database.Path == "" {
+		cfg.Database.Path = filepath.Join(dataDir, DefaultDatabaseFile)
+		cfg.Database.PathSource = SourceDefault
+	}
+
+	if absolute, err := filepath.Abs(cfg.Database.Path); err == nil {
+		cfg.Database.Path = absolute
+	}
+	if err := cfg.Database.ApplyDefaults(); err != nil {
+		errs = append(errs, err)
+	}
+	if err := cfg.Validation.ApplyDefaults(); err != nil {
+		errs = append(errs, err)
+	}
+
+	return cfg, errors.Join(errs...)
+}
+
+// readFile merges the settings of the config file into cfg. A missing file is
+// not an error.
+func (cfg *Config) readFile() error {
+	data, err := os.ReadFile(cfg.File)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read config file %s: %w", cfg.File, err)
+	}
+	if err := json.Unmarshal(data, cfg); err != nil {
+		return fmt.Errorf("invalid config file %s: %w", cfg.File, err)
+	}
+	return nil
+}
@@ -0,0 +1,61 @@
+package dto
+
+import apperrors "product-management-app/core/errors"
+
+// MinPassphraseLength is the shortest passphrase accepted for new archives.
+const MinPassphraseLength = 8
+
+// ArchiveImportMode selects how an imported archive is applied.
+type ArchiveImportMode string
+
+const (
+	// ArchiveReplace swaps the whole database for the archive's.
+	ArchiveReplace ArchiveImportMode = "replace"
+	// ArchiveMerge adds the archive's products that are missing and updates
+	// those the archive has a newer copy of, keeping everything else.
+	ArchiveMerge ArchiveImportMode = "merge"
+)
+
+// Validate checks that mode is a known import mode.
+func (mode ArchiveImportMode) Validate() error {
+	if mode != ArchiveReplace && mode != ArchiveMerge {
+		return apperrors.Validation("mode", "invalid archive import mode %q: must be %q or %q", mode, ArchiveReplace, ArchiveMerge)
+	}
+	return nil
+}
+
+// ValidatePassphrase checks that passphrase is long enough to protect a new
+// archive.
+func ValidatePassphrase(passphrase string) error {
+	if len([]rune(passphrase)) < MinPassphraseLength {
+		return apperrors.Validation("passphrase", "passphrase must be at least %d characters long", MinPassphraseLength)
+	}
+	return nil
+}
+
+// MergeResult counts the products changed by merging an archive. Rejected
+// lists the archived products left out because they break the validation
+// rules; they are not counted as skipped.
+type MergeResult struct {
+	Inserted int              `json:"inserted"`
+	Updated  int              `json:"updated"`
+	Skipped  int              `json:"skipped"`
+	Rejected []MergeRejection `json:"rejected,omitempty"`
+}
+
+// MergeRejection is an archived product a merge left out, with the rules it
+// breaks.
+type MergeRejection struct {
+	ProductID int                    `json:"productId"`
+	Name      string                 `json:"name"`
+	Errors    []apperrors.FieldError `json:"errors"`
+}
+
+// ArchiveImportResult reports an imported archive. Merge is only set for
+// merge imports.
+type ArchiveImportResult struct {
+	Mode         ArchiveImportMode `json:"mode"`
+	Archive      BackupDTO         `json:"archive"`
+	SafetyBackup string            `json:"safetyBackup"`
+	Merge        *MergeResult      `json:"merge,omitempty"`
+}
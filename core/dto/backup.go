@@ -0,0 +1,22 @@
+package dto
+
+// BackupDTO describes a database backup file. SchemaVersion and
+// ProductCount are only filled in once the file has been validated.
+type BackupDTO struct {
+	Path          string `json:"path"`
+	Size          int64  `json:"size"`
+	CreatedAt     string `json:"createdAt"`
+	SchemaVersion int    `json:"schemaVersion"`
+	ProductCount  int    `json:"productCount"`
+	// Scheduled is true for backups written by the backup schedule, which
+	// are rotated automatically.
+	Scheduled bool `json:"scheduled"`
+}
+
+// RestoreResultDTO reports a completed restore.
+type RestoreResultDTO struct {
+	Restored BackupDTO `json:"restored"`
+	// SafetyBackup is the copy of the replaced database, written before the
+	// restore so it can be undone.
+	SafetyBackup string `json:"safetyBackup"`
+}
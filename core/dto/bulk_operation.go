@@ -0,0 +1,142 @@
+package dto
+
+import (
+	apperrors "product-management-app/core/errors"
+	"product-management-app/core/models"
+	"product-management-app/core/money"
+)
+
+// BulkOperation names the change a bulk request applies to every selected
+// product.
+type BulkOperation string
+
+const (
+	BulkDelete      BulkOperation = "delete"
+	BulkSetCategory BulkOperation = "setCategory"
+	BulkAdjustStock BulkOperation = "adjustStock"
+	BulkSetPrice    BulkOperation = "setPrice"
+	BulkAdjustPrice BulkOperation = "adjustPrice"
+)
+
+// PriceAdjustmentType selects how PriceAdjustmentDTO.Amount is applied.
+type PriceAdjustmentType string
+
+const (
+	// AdjustByPercent changes the price by Amount percent (10 = +10%).
+	AdjustByPercent PriceAdjustmentType = "percent"
+	// AdjustByAmount adds Amount to the price; negative values lower it.
+	AdjustByAmount PriceAdjustmentType = "fixed"
+)
+
+// RoundingMode selects the direction prices are rounded in.
+type RoundingMode = money.RoundingMode
+
+const (
+	RoundNearest  = money.RoundNearest
+	RoundHalfEven = money.RoundHalfEven
+	RoundUp       = money.RoundUp
+	RoundDown     = money.RoundDown
+)
+
+// PriceAdjustmentDTO describes a relative price change and how the result is
+// rounded. Amount is a percentage for AdjustByPercent and an amount of money
+// for AdjustByAmount. Increment is the step prices are rounded to (0.01,
+// 0.05, 1, ...) and defaults to 0.01; Rounding defaults to RoundNearest.
+type PriceAdjustmentDTO struct {
+	Type      PriceAdjustmentType `json:"type"`
+	Amount    float64             `json:"amount"`
+	Rounding  RoundingMode        `json:"rounding,omitempty"`
+	Increment money.Money         `json:"increment,omitempty"`
+}
+
+// BulkOperationDTO applies one operation to a selection of products. The
+// selection is ProductIDs when it is not empty; otherwise every product
+// matching Search and Filters, which must then be non-nil (an empty filter
+// selects the whole catalog).
+type BulkOperationDTO struct {
+	Operation  BulkOperation     `json:"operation"`
+	ProductIDs []int             `json:"productIds,omitempty"`
+	Search     string            `json:"search,omitempty"`
+	Filters    *ProductFilterDTO `json:"filters,omitempty"`
+
+	// Category is the new category for BulkSetCategory; empty clears it.
+	Category string `json:"category,omitempty"`
+	// StockDelta is added to the stock for BulkAdjustStock.
+	StockDelta int `json:"stockDelta,omitempty"`
+	// Price is the new price for BulkSetPrice.
+	Price money.Money `json:"price,omitempty"`
+	// PriceAdjustment is the change applied by BulkAdjustPrice.
+	PriceAdjustment *PriceAdjustmentDTO `json:"priceAdjustment,omitempty"`
+}
+
+// BulkItemResult reports the outcome for one selected product. Product holds
+// the updated product for operations other than delete.
+type BulkItemResult struct {
+	ID      int             `json:"id"`
+	Success bool            `json:"success"`
+	Error   string          `json:"error,omitempty"`
+	Product *models.Product `json:"product,omitempty"`
+}
+
+// BulkOperationResult summarizes a bulk operation. The operation runs in a
+// single transaction: when any item fails, Committed is false and nothing
+// was written, even for items reported as successful.
+type BulkOperationResult struct {
+	Operation    BulkOperation    `json:"operation"`
+	Committed    bool             `json:"committed"`
+	SuccessCount int              `json:"successCount"`
+	ErrorCount   int              `json:"errorCount"`
+	Items        []BulkItemResult `json:"items"`
+}
+
+// Validate checks that the operation is known, has its parameters and has a
+// selection.
+func (b BulkOperationDTO) Validate() error {
+	if len(b.ProductIDs) == 0 && b.Filters == nil {
+		return apperrors.Validation("productIds", "no products selected: provide product IDs or a filter")
+	}
+	if len(b.ProductIDs) == 0 {
+		if err := b.Filters.Validate(); err != nil {
+			return err
+		}
+	}
+
+	switch b.Operation {
+	case BulkDelete, BulkSetCategory, BulkAdjustStock:
+	case BulkSetPrice:
+		if b.Price < 0 {
+			return apperrors.Validation("price", "price must not be negative")
+		}
+	case BulkAdjustPrice:
+		if b.PriceAdjustment == nil {
+			return apperrors.Validation("priceAdjustment", "price adjustment is required for %s", b.Operation)
+		}
+		return b.PriceAdjustment.Validate()
+	default:
+		return apperrors.Validation("operation", "unknown bulk operation %q", b.Operation)
+	}
+	return nil
+}
+
+// Validate checks the adjustment type, rounding mode and increment.
+func (a PriceAdjustmentDTO) Validate() error {
+	if a.Type != AdjustByPercent && a.Type != AdjustByAmount {
+		return apperrors.Validation("priceAdjustment.type", "unknown price adjustment type %q", a.Type)
+	}
+	if !a.Rounding.Valid() {
+		return apperrors.Validation("priceAdjustment.rounding", "unknown rounding mode %q", a.Rounding)
+	}
+	if a.Increment < 0 {
+		return apperrors.Validation("priceAdjustment.increment", "rounding increment must not be negative")
+	}
+	return nil
+}
+
+// Apply returns price after the adjustment and rounding. The result is
+// computed exactly and rounded once.
+func (a PriceAdjustmentDTO) Apply(price money.Money) money.Money {
+	if a.Type == AdjustByPercent {
+		return price.AddPercent(a.Amount, a.Increment, a.Rounding)
+	}
+	return price.AddAmount(a.Amount, a.Increment, a.Rounding)
+}
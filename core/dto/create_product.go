@@ -1,12 +1,15 @@
 // Package dto contains data transfer objects for the product management application.
 package dto
 
+import "product-management-app/core/money"
+
 // CreateProductDTO represents the data required to create a new product.
 type CreateProductDTO struct {
-	Name        string  `json:"name"`
-	Price       float64 `json:"price"`
-	Category    string  `json:"category,omitempty"`
-	Stock       int     `json:"stock,omitempty"`
-	Description string  `json:"description,omitempty"`
-	ImageURL    string  `json:"imageUrl,omitempty"`
+	Name        string      `json:"name"`
+	SKU         string      `json:"sku,omitempty"`
+	Price       money.Money `json:"price"`
+	Category    string      `json:"category,omitempty"`
+	Stock       int         `json:"stock,omitempty"`
+	Description string      `json:"description,omitempty"`
+	ImageURL    string      `json:"imageUrl,omitempty"`
 }
@@ -1,6 +1,10 @@
 package dto
 
-import "time"
+import (
+	"time"
+
+	"product-management-app/core/money"
+)
 
 type CurrencyRatesResponse struct {
 	Date  string             `json:"date"`
@@ -8,19 +12,23 @@ type CurrencyRatesResponse struct {
 	Rates map[string]float64 `json:"rates,omitempty"`
 }
 
+// CurrencyConversionRequest converts Amount from one currency to another.
+// The result is rounded to the target currency's minor unit with Rounding,
+// which defaults to money.RoundNearest.
 type CurrencyConversionRequest struct {
-	Amount       float64 `json:"amount"`
-	FromCurrency string  `json:"fromCurrency"`
-	ToCurrency   string  `json:"toCurrency"`
+	Amount       money.Money        `json:"amount"`
+	FromCurrency string             `json:"fromCurrency"`
+	ToCurrency   string             `json:"toCurrency"`
+	Rounding     money.RoundingMode `json:"rounding,omitempty"`
 }
 
 type CurrencyConversionResponse struct {
-	Amount          float64   `json:"amount"`
-	FromCurrency    string    `json:"fromCurrency"`
-	ToCurrency      string    `json:"toCurrency"`
-	ConvertedAmount float64   `json:"convertedAmount"`
-	ExchangeRate    float64   `json:"exchangeRate"`
-	ConversionDate  time.Time `json:"conversionDate"`
+	Amount          money.Money `json:"amount"`
+	FromCurrency    string      `json:"fromCurrency"`
+	ToCurrency      string      `json:"toCurrency"`
+	ConvertedAmount money.Money `json:"convertedAmount"`
+	ExchangeRate    float64     `json:"exchangeRate"`
+	ConversionDate  time.Time   `json:"conversionDate"`
 }
 
 type SupportedCurrenciesResponse struct {
@@ -31,4 +39,7 @@ type CurrencyInfo struct {
 	Code   string `json:"code"`
 	Symbol string `json:"symbol"`
 	Name   string `json:"name"`
+	// Decimals is the number of decimal places amounts in the currency are
+	// rounded to, e.g. 0 for the yen.
+	Decimals int `json:"decimals"`
 }
@@ -0,0 +1,16 @@
+package dto
+
+// DatabaseSettingsDTO reports the SQLite pragmas in effect and the state of
+// the connection pool.
+type DatabaseSettingsDTO struct {
+	Driver          string `json:"driver"`
+	JournalMode     string `json:"journalMode"`
+	Synchronous     string `json:"synchronous"`
+	BusyTimeoutMs   int    `json:"busyTimeoutMs"`
+	ForeignKeys     bool   `json:"foreignKeys"`
+	MaxOpenConns    int    `json:"maxOpenConns"`
+	MaxIdleConns    int    `json:"maxIdleConns"`
+	OpenConnections int    `json:"openConnections"`
+	InUse           int    `json:"inUse"`
+	WaitCount       int64  `json:"waitCount"`
+}
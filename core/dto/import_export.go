@@ -1,12 +1,115 @@
 package dto
 
-import "product-management-app/core/models"
+import (
+	"sort"
 
+	apperrors "product-management-app/core/errors"
+	"product-management-app/core/models"
+	"product-management-app/core/money"
+)
+
+// ImportResult reports what an import did with every row of the file. For
+// a dry run it reports what the import would do, and Preview holds the plan.
 type ImportResult struct {
-	SuccessCount  int               `json:"successCount"`
-	ErrorCount    int               `json:"errorCount"`
-	Errors        []ImportError     `json:"errors,omitempty"`
+	// SuccessCount is the number of products created or updated.
+	SuccessCount int `json:"successCount"`
+	CreatedCount int `json:"createdCount"`
+	UpdatedCount int `json:"updatedCount"`
+	SkippedCount int `json:"skippedCount"`
+	// ErrorCount is the number of rows that failed, or of problems with
+	// the file itself when nothing could be imported.
+	ErrorCount int           `json:"errorCount"`
+	Errors     []ImportError `json:"errors,omitempty"`
+	// Skipped explains every skipped row.
+	Skipped []ImportSkip `json:"skipped,omitempty"`
+	// ImportedItems holds the first 1000 created and updated products in
+	// file order; the counts cover them all. It is empty for a dry run.
 	ImportedItems []*models.Product `json:"importedItems,omitempty"`
+	Preview       *ImportPreview    `json:"preview,omitempty"`
+	// BatchID identifies the import batch that records the written
+	// products, for RollbackImport. It is zero when nothing was written.
+	BatchID int `json:"batchId,omitempty"`
+	// RolledBack is set when an atomic import wrote nothing because a row
+	// failed. The counts of created and updated products are then zero.
+	RolledBack bool `json:"rolledBack,omitempty"`
+}
+
+// ImportProgressDTO reports the state of an import while it runs. Status is
+// one of the job states, JobRunning until the import finishes.
+type ImportProgressDTO struct {
+	ID     string `json:"id"`
+	Source string `json:"source"`
+	Status string `json:"status"`
+	// RowsProcessed counts the rows read so far, including blank ones.
+	RowsProcessed int `json:"rowsProcessed"`
+	SuccessCount  int `json:"successCount"`
+	ErrorCount    int `json:"errorCount"`
+	// Progress is the share of the file processed, between 0 and 1.
+	Progress float64 `json:"progress"`
+	// ETASeconds estimates the time left, or is -1 while unknown.
+	ETASeconds int    `json:"etaSeconds"`
+	StartedAt  string `json:"startedAt"`
+	Error      string `json:"error,omitempty"`
+}
+
+// ImportRowStatus classifies a row of an import preview.
+type ImportRowStatus string
+
+const (
+	// ImportRowNew creates a product.
+	ImportRowNew ImportRowStatus = "new"
+	// ImportRowChanged updates the product it matches.
+	ImportRowChanged ImportRowStatus = "changed"
+	// ImportRowUnchanged matches a product the row would not change.
+	ImportRowUnchanged ImportRowStatus = "unchanged"
+	// ImportRowSkipped is left alone by the import mode: a matched row in
+	// an insert-only import or an unmatched one in an update-only import.
+	ImportRowSkipped ImportRowStatus = "skipped"
+	// ImportRowInvalid cannot be imported; Errors says why.
+	ImportRowInvalid ImportRowStatus = "invalid"
+)
+
+// ImportPreview is the plan of a dry-run import. Nothing has been written;
+// committing the preview by its ID applies exactly this plan.
+type ImportPreview struct {
+	ID string `json:"id"`
+	// ExpiresAt is when the preview can no longer be committed, in RFC 3339.
+	ExpiresAt string `json:"expiresAt"`
+	// Rows holds the plan of the first rows, up to a limit; TotalRows is
+	// the number of rows in the whole plan.
+	Rows      []ImportPreviewRow `json:"rows"`
+	TotalRows int                `json:"totalRows"`
+	// Counts is the number of rows in the whole plan with each status.
+	Counts map[ImportRowStatus]int `json:"counts"`
+}
+
+// ImportPreviewRow is what the import would do with one non-blank row.
+type ImportPreviewRow struct {
+	Row    int             `json:"row"`
+	Status ImportRowStatus `json:"status"`
+	// ProductID is the product the row matches, if any.
+	ProductID int    `json:"productId,omitempty"`
+	Name      string `json:"name"`
+	// Changes lists the fields a changed row updates, or the fields a new
+	// row sets with empty Before values.
+	Changes []ImportFieldChange `json:"changes,omitempty"`
+	Errors  []ImportError       `json:"errors,omitempty"`
+	Reason  string              `json:"reason,omitempty"`
+}
+
+// ImportFieldChange is the before and after value of one field.
+type ImportFieldChange struct {
+	Field  string `json:"field"`
+	Before string `json:"before"`
+	After  string `json:"after"`
+}
+
+// ImportSkip is a row the import left alone on purpose.
+type ImportSkip struct {
+	Row int `json:"row"`
+	// ProductID is the product the row matched, if any.
+	ProductID int    `json:"productId,omitempty"`
+	Reason    string `json:"reason"`
 }
 
 type ImportError struct {
@@ -16,6 +119,176 @@ type ImportError struct {
 	Value   string `json:"value"`
 }
 
+// Product fields an import column can hold. The ID is only used to match
+// rows to existing products.
+const (
+	ImportFieldID          = "id"
+	ImportFieldSKU         = "sku"
+	ImportFieldName        = "name"
+	ImportFieldPrice       = "price"
+	ImportFieldCategory    = "category"
+	ImportFieldStock       = "stock"
+	ImportFieldDescription = "description"
+	ImportFieldImageURL    = "imageUrl"
+)
+
+// ImportFields lists every field an import column can be mapped to.
+var ImportFields = []string{
+	ImportFieldID, ImportFieldSKU, ImportFieldName, ImportFieldPrice, ImportFieldCategory,
+	ImportFieldStock, ImportFieldDescription, ImportFieldImageURL,
+}
+
+// ImportMode selects which rows of an import file are written, depending
+// on whether they match an existing product.
+type ImportMode string
+
+const (
+	// ImportInsertOnly creates a product for every row that matches none
+	// and skips the rows that match one.
+	ImportInsertOnly ImportMode = "insert"
+	// ImportUpdateOnly updates the product every row matches and skips the
+	// rows that match none.
+	ImportUpdateOnly ImportMode = "update"
+	// ImportUpsert updates the products rows match and creates the rest.
+	ImportUpsert ImportMode = "upsert"
+)
+
+// ImportMatchKey selects the field that matches import rows to existing
+// products.
+type ImportMatchKey string
+
+const (
+	// MatchByID matches the product with the row's ID.
+	MatchByID ImportMatchKey = ImportFieldID
+	// MatchBySKU matches the product with the row's SKU.
+	MatchBySKU ImportMatchKey = ImportFieldSKU
+	// MatchByName matches the only product with exactly the row's name. A
+	// name shared by several products fails the row.
+	MatchByName ImportMatchKey = ImportFieldName
+)
+
+// MergeRule decides how a field of a matched product is updated from a
+// non-blank cell. Blank cells never change a product.
+type MergeRule string
+
+const (
+	// MergeOverwrite replaces the stored value with the cell's.
+	MergeOverwrite MergeRule = "overwrite"
+	// MergeKeepExisting never changes the stored value.
+	MergeKeepExisting MergeRule = "keepExisting"
+	// MergeFillBlanks sets the field only while it is blank: empty text or
+	// a zero price or stock.
+	MergeFillBlanks MergeRule = "fillBlanks"
+)
+
+// ImportOptions tunes how an import file is read and applied.
+type ImportOptions struct {
+	// ColumnMapping maps a header of the file to the field its column
+	// holds, taking precedence over the built-in English and Portuguese
+	// aliases. Headers are matched ignoring case and accents; mapping a
+	// header to "" ignores its column.
+	ColumnMapping map[string]string `json:"columnMapping,omitempty"`
+	// Mode defaults to ImportInsertOnly.
+	Mode ImportMode `json:"mode,omitempty"`
+	// MatchBy defaults to MatchBySKU. Rows with a blank key match nothing.
+	MatchBy ImportMatchKey `json:"matchBy,omitempty"`
+	// DefaultMerge is the rule for fields Merge has no rule for, and
+	// defaults to MergeOverwrite.
+	DefaultMerge MergeRule `json:"defaultMerge,omitempty"`
+	// Merge holds the rule for individual fields, keyed by field name.
+	Merge map[string]MergeRule `json:"merge,omitempty"`
+	// DryRun validates the whole file and returns a preview of what the
+	// import would do without writing anything.
+	DryRun bool `json:"dryRun,omitempty"`
+	// Atomic applies the whole file in one transaction that writes nothing
+	// if any row fails.
+	Atomic bool `json:"atomic,omitempty"`
+}
+
+// WithDefaults returns o with the unset mode, match key and default merge
+// rule filled in.
+func (o ImportOptions) WithDefaults() ImportOptions {
+	if o.Mode == "" {
+		o.Mode = ImportInsertOnly
+	}
+	if o.MatchBy == "" {
+		o.MatchBy = MatchBySKU
+	}
+	if o.DefaultMerge == "" {
+		o.DefaultMerge = MergeOverwrite
+	}
+	return o
+}
+
+// MergeRuleFor returns the rule that applies to field.
+func (o ImportOptions) MergeRuleFor(field string) MergeRule {
+	if rule, ok := o.Merge[field]; ok && rule != "" {
+		return rule
+	}
+	if o.DefaultMerge == "" {
+		return MergeOverwrite
+	}
+	return o.DefaultMerge
+}
+
+// Validate checks the mode, the match key and the merge rules, and that the
+// column mapping only names known fields.
+func (o ImportOptions) Validate() error {
+	errs := new(apperrors.ValidationError)
+	switch o.Mode {
+	case "", ImportInsertOnly, ImportUpdateOnly, ImportUpsert:
+	default:
+		errs.Add("mode", "invalid import mode %q: must be %q, %q or %q", o.Mode, ImportInsertOnly, ImportUpdateOnly, ImportUpsert)
+	}
+	switch o.MatchBy {
+	case "", MatchByID, MatchBySKU, MatchByName:
+	default:
+		errs.Add("matchBy", "invalid match key %q: must be %q, %q or %q", o.MatchBy, MatchByID, MatchBySKU, MatchByName)
+	}
+	if o.DefaultMerge != "" && !o.DefaultMerge.valid() {
+		errs.Add("defaultMerge", "invalid merge rule %q", o.DefaultMerge)
+	}
+	for _, field := range sortedKeys(o.Merge) {
+		switch rule := o.Merge[field]; {
+		case !isImportField(field) || field == ImportFieldID:
+			errs.Add("merge", "field %q cannot have a merge rule", field)
+		case rule != "" && !rule.valid():
+			errs.Add("merge", "invalid merge rule %q for field %q", rule, field)
+		}
+	}
+
+	for _, header := range sortedKeys(o.ColumnMapping) {
+		if field := o.ColumnMapping[header]; field != "" && !isImportField(field) {
+			errs.Add("columnMapping", "column %q is mapped to unknown field %q", header, field)
+		}
+	}
+	return errs.OrNil()
+}
+
+func (r MergeRule) valid() bool {
+	return r == MergeOverwrite || r == MergeKeepExisting || r == MergeFillBlanks
+}
+
+// sortedKeys returns the keys of m in order, so validation errors come out
+// in a stable order.
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func isImportField(field string) bool {
+	for _, known := range ImportFields {
+		if field == known {
+			return true
+		}
+	}
+	return false
+}
+
 type ExportFormat string
 
 const (
@@ -37,7 +310,7 @@ func (p *ProductImportDTO) ToCreateProductDTO() CreateProductDTO {
 	return p.CreateProductDTO
 }
 
-func NewProductImportDTO(name string, price float64, category string, stock int, description string, imageURL string) *ProductImportDTO {
+func NewProductImportDTO(name string, price money.Money, category string, stock int, description string, imageURL string) *ProductImportDTO {
 	return &ProductImportDTO{
 		CreateProductDTO: CreateProductDTO{
 			Name:        name,
@@ -51,15 +324,16 @@ func NewProductImportDTO(name string, price float64, category string, stock int,
 }
 
 type ProductExportDTO struct {
-	ID          int     `json:"id" csv:"id"`
-	Name        string  `json:"name" csv:"name"`
-	Price       float64 `json:"price" csv:"price"`
-	Category    string  `json:"category" csv:"category"`
-	Stock       int     `json:"stock" csv:"stock"`
-	Description string  `json:"description" csv:"description"`
-	ImageURL    string  `json:"imageUrl" csv:"image_url"`
-	CreatedAt   string  `json:"createdAt" csv:"created_at"`
-	UpdatedAt   string  `json:"updatedAt" csv:"updated_at"`
+	ID          int         `json:"id" csv:"id"`
+	SKU         string      `json:"sku" csv:"sku"`
+	Name        string      `json:"name" csv:"name"`
+	Price       money.Money `json:"price" csv:"price"`
+	Category    string      `json:"category" csv:"category"`
+	Stock       int         `json:"stock" csv:"stock"`
+	Description string      `json:"description" csv:"description"`
+	ImageURL    string      `json:"imageUrl" csv:"image_url"`
+	CreatedAt   string      `json:"createdAt" csv:"created_at"`
+	UpdatedAt   string      `json:"updatedAt" csv:"updated_at"`
 }
 
 func NewProductExportDTO(product *models.Product) *ProductExportDTO {
@@ -71,6 +345,10 @@ func NewProductExportDTO(product *models.Product) *ProductExportDTO {
 		CreatedAt: product.CreatedAt,
 	}
 
+	if product.SKU != nil {
+		dto.SKU = *product.SKU
+	}
+
 	if product.Category != nil {
 		dto.Category = *product.Category
 	}
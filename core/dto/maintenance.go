@@ -0,0 +1,71 @@
+package dto
+
+import apperrors "product-management-app/core/errors"
+
+// MaintenanceTask names a database maintenance job.
+type MaintenanceTask string
+
+const (
+	MaintenanceIntegrityCheck MaintenanceTask = "integrityCheck"
+	MaintenanceQuickCheck     MaintenanceTask = "quickCheck"
+	MaintenanceVacuum         MaintenanceTask = "vacuum"
+	MaintenanceAnalyze        MaintenanceTask = "analyze"
+	MaintenanceStats          MaintenanceTask = "stats"
+)
+
+// Validate checks that task is a known maintenance task.
+func (task MaintenanceTask) Validate() error {
+	switch task {
+	case MaintenanceIntegrityCheck, MaintenanceQuickCheck, MaintenanceVacuum, MaintenanceAnalyze, MaintenanceStats:
+		return nil
+	}
+	return apperrors.Validation("task", "unknown maintenance task %q", task)
+}
+
+// Job states.
+const (
+	JobRunning   = "running"
+	JobCompleted = "completed"
+	JobFailed    = "failed"
+	JobCancelled = "cancelled"
+)
+
+// MaintenanceJobDTO reports the state of a background maintenance job.
+// Result holds an IntegrityCheckResult for checks, DatabaseStatsDTO for
+// stats, and is empty for VACUUM and ANALYZE.
+type MaintenanceJobDTO struct {
+	ID         string          `json:"id"`
+	Task       MaintenanceTask `json:"task"`
+	Status     string          `json:"status"`
+	Progress   float64         `json:"progress"`
+	Message    string          `json:"message"`
+	StartedAt  string          `json:"startedAt"`
+	FinishedAt string          `json:"finishedAt,omitempty"`
+	Error      string          `json:"error,omitempty"`
+	Result     interface{}     `json:"result,omitempty"`
+}
+
+// IntegrityCheckResult lists the problems found by integrity_check or
+// quick_check. Problems is capped, so Truncated reports whether more exist.
+type IntegrityCheckResult struct {
+	OK        bool     `json:"ok"`
+	Problems  []string `json:"problems"`
+	Truncated bool     `json:"truncated"`
+}
+
+// TableStatsDTO is the row count of one table.
+type TableStatsDTO struct {
+	Name string `json:"name"`
+	Rows int64  `json:"rows"`
+}
+
+// DatabaseStatsDTO describes the database file and its tables.
+type DatabaseStatsDTO struct {
+	Path          string          `json:"path"`
+	FileSize      int64           `json:"fileSize"`
+	WALSize       int64           `json:"walSize"`
+	PageSize      int64           `json:"pageSize"`
+	PageCount     int64           `json:"pageCount"`
+	FreelistCount int64           `json:"freelistCount"`
+	Tables        []TableStatsDTO `json:"tables"`
+}
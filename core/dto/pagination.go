@@ -1,15 +1,20 @@
 package dto
 
 import (
+	apperrors "product-management-app/core/errors"
 	"product-management-app/core/models"
 )
 
+// MaxPageSize is the largest page size accepted by the product listing.
+const MaxPageSize = 10000
+
 type PaginationDTO struct {
-	Page     int    `json:"page"`
-	PageSize int    `json:"pageSize"`
-	Search   string `json:"search,omitempty"`
-	SortBy   string `json:"sortBy,omitempty"`
-	Order    string `json:"order,omitempty"`
+	Page     int               `json:"page"`
+	PageSize int               `json:"pageSize"`
+	Search   string            `json:"search,omitempty"`
+	SortBy   string            `json:"sortBy,omitempty"`
+	Order    string            `json:"order,omitempty"`
+	Filters  *ProductFilterDTO `json:"filters,omitempty"`
 }
 
 type PaginationResponse struct {
@@ -19,3 +24,18 @@ type PaginationResponse struct {
 	Page       int               `json:"page"`
 	PageSize   int               `json:"pageSize"`
 }
+
+// Validate checks that the page and page size are usable and that the
+// optional filter is consistent.
+func (p PaginationDTO) Validate() error {
+	if p.Page < 1 {
+		return apperrors.Validation("page", "invalid page %d: page must be at least 1", p.Page)
+	}
+	if p.PageSize < 1 || p.PageSize > MaxPageSize {
+		return apperrors.Validation("pageSize", "invalid page size %d: must be between 1 and %d", p.PageSize, MaxPageSize)
+	}
+	if p.Filters != nil {
+		return p.Filters.Validate()
+	}
+	return nil
+}
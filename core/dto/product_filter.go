@@ -0,0 +1,81 @@
+package dto
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	apperrors "product-management-app/core/errors"
+	"product-management-app/core/money"
+)
+
+// ProductFilterDTO narrows the product listing. Nil or empty fields are ignored
+// and all supplied fields are combined with AND.
+type ProductFilterDTO struct {
+	MinPrice       *money.Money `json:"minPrice,omitempty"`
+	MaxPrice       *money.Money `json:"maxPrice,omitempty"`
+	MinStock       *int         `json:"minStock,omitempty"`
+	MaxStock       *int         `json:"maxStock,omitempty"`
+	Categories     []string     `json:"categories,omitempty"`
+	HasImage       *bool        `json:"hasImage,omitempty"`
+	HasDescription *bool        `json:"hasDescription,omitempty"`
+	CreatedFrom    string       `json:"createdFrom,omitempty"`
+	CreatedTo      string       `json:"createdTo,omitempty"`
+	UpdatedFrom    string       `json:"updatedFrom,omitempty"`
+	UpdatedTo      string       `json:"updatedTo,omitempty"`
+}
+
+// Validate checks that every range in the filter is well formed.
+func (f *ProductFilterDTO) Validate() error {
+	if f.MinPrice != nil && *f.MinPrice < 0 {
+		return apperrors.Validation("filters.minPrice", "invalid filter: minPrice must not be negative")
+	}
+	if f.MinPrice != nil && f.MaxPrice != nil && *f.MinPrice > *f.MaxPrice {
+		return apperrors.Validation("filters.minPrice", "invalid filter: minPrice is greater than maxPrice")
+	}
+	if f.MinStock != nil && f.MaxStock != nil && *f.MinStock > *f.MaxStock {
+		return apperrors.Validation("filters.minStock", "invalid filter: minStock is greater than maxStock")
+	}
+
+	dates := []struct {
+		field string
+		value string
+	}{
+		{"createdFrom", f.CreatedFrom},
+		{"createdTo", f.CreatedTo},
+		{"updatedFrom", f.UpdatedFrom},
+		{"updatedTo", f.UpdatedTo},
+	}
+	for _, date := range dates {
+		if _, err := ParseFilterDate(date.value, false); err != nil {
+			return apperrors.Validation("filters."+date.field, "invalid filter: %s: %v", date.field, err)
+		}
+	}
+	return nil
+}
+
+// ParseFilterDate converts a filter date into the "YYYY-MM-DD HH:MM:SS" UTC
+// format SQLite uses for CURRENT_TIMESTAMP. Both RFC 3339 timestamps and plain
+// dates are accepted; a plain date used as an upper bound is moved to the last
+// second of that day so the range stays inclusive. An empty value yields "".
+func ParseFilterDate(value string, upperBound bool) (string, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return "", nil
+	}
+
+	const sqliteLayout = "2006-01-02 15:04:05"
+
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t.UTC().Format(sqliteLayout), nil
+	}
+
+	t, err := time.Parse("2006-01-02", value)
+	if err != nil {
+		return "", fmt.Errorf("%q is not a date (expected YYYY-MM-DD or RFC 3339)", value)
+	}
+	if upperBound {
+		t = t.Add(24*time.Hour - time.Second)
+	}
+	return t.Format(sqliteLayout), nil
+}
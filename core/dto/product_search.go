@@ -0,0 +1,51 @@
+package dto
+
+import (
+	"strings"
+
+	apperrors "product-management-app/core/errors"
+	"product-management-app/core/models"
+)
+
+// ProductSearchDTO holds the parameters for a full-text product search.
+type ProductSearchDTO struct {
+	Query    string `json:"query"`
+	Page     int    `json:"page"`
+	PageSize int    `json:"pageSize"`
+}
+
+// ProductSearchResult is a product matched by a full-text search. Rank is the
+// relevance score, higher meaning a better match. The highlight fields are
+// HTML: the product text is escaped and matched terms are wrapped in
+// <mark></mark>, so they can be rendered as is.
+type ProductSearchResult struct {
+	Product            *models.Product `json:"product"`
+	Rank               float64         `json:"rank"`
+	NameHighlight      string          `json:"nameHighlight"`
+	CategoryHighlight  string          `json:"categoryHighlight,omitempty"`
+	DescriptionSnippet string          `json:"descriptionSnippet,omitempty"`
+}
+
+// ProductSearchResponse is a page of full-text search results ordered by
+// relevance.
+type ProductSearchResponse struct {
+	Results    []*ProductSearchResult `json:"results"`
+	TotalCount int                    `json:"totalCount"`
+	TotalPages int                    `json:"totalPages"`
+	Page       int                    `json:"page"`
+	PageSize   int                    `json:"pageSize"`
+}
+
+// Validate checks that the search has a query and a usable page.
+func (s ProductSearchDTO) Validate() error {
+	if strings.TrimSpace(s.Query) == "" {
+		return apperrors.Validation("query", "search query is required")
+	}
+	if s.Page < 1 {
+		return apperrors.Validation("page", "invalid page %d: page must be at least 1", s.Page)
+	}
+	if s.PageSize < 1 || s.PageSize > MaxPageSize {
+		return apperrors.Validation("pageSize", "invalid page size %d: must be between 1 and %d", s.PageSize, MaxPageSize)
+	}
+	return nil
+}
@@ -0,0 +1,19 @@
+package dto
+
+// SchemaMigrationDTO describes one schema migration. AppliedAt is empty for
+// pending migrations.
+type SchemaMigrationDTO struct {
+	Version   int    `json:"version"`
+	Name      string `json:"name"`
+	Checksum  string `json:"checksum"`
+	AppliedAt string `json:"appliedAt,omitempty"`
+}
+
+// SchemaStatusDTO reports the database schema version and migration history.
+type SchemaStatusDTO struct {
+	CurrentVersion  int                  `json:"currentVersion"`
+	LatestVersion   int                  `json:"latestVersion"`
+	Applied         []SchemaMigrationDTO `json:"applied"`
+	Pending         []SchemaMigrationDTO `json:"pending"`
+	MigrationBackup string               `json:"migrationBackup,omitempty"`
+}
@@ -0,0 +1,66 @@
+package dto
+
+import (
+	apperrors "product-management-app/core/errors"
+	"product-management-app/core/money"
+)
+
+// Nullable product fields that UpdateProductDTO.Clear may reset to NULL.
+const (
+	FieldSKU         = "sku"
+	FieldCategory    = "category"
+	FieldDescription = "description"
+	FieldImageURL    = "imageUrl"
+)
+
+// UpdateProductDTO describes a partial product update. Only non-nil fields are
+// written; fields named in Clear are set back to NULL. Version must be the
+// version of the product the caller read.
+type UpdateProductDTO struct {
+	Version     int          `json:"version"`
+	Name        *string      `json:"name,omitempty"`
+	SKU         *string      `json:"sku,omitempty"`
+	Price       *money.Money `json:"price,omitempty"`
+	Category    *string      `json:"category,omitempty"`
+	Stock       *int         `json:"stock,omitempty"`
+	Description *string      `json:"description,omitempty"`
+	ImageURL    *string      `json:"imageUrl,omitempty"`
+	Clear       []string     `json:"clear,omitempty"`
+}
+
+// IsEmpty reports whether the update would not change any column.
+func (u UpdateProductDTO) IsEmpty() bool {
+	return u.Name == nil && u.SKU == nil && u.Price == nil && u.Category == nil && u.Stock == nil &&
+		u.Description == nil && u.ImageURL == nil && len(u.Clear) == 0
+}
+
+// Validate checks that the update carries a version, is not empty and that
+// Clear only names nullable fields that are not also being set.
+func (u UpdateProductDTO) Validate() error {
+	if u.Version < 1 {
+		return apperrors.Validation("version", "version is required")
+	}
+	if u.IsEmpty() {
+		return apperrors.Validation("fields", "no fields to update")
+	}
+
+	for _, field := range u.Clear {
+		var set bool
+		switch field {
+		case FieldSKU:
+			set = u.SKU != nil
+		case FieldCategory:
+			set = u.Category != nil
+		case FieldDescription:
+			set = u.Description != nil
+		case FieldImageURL:
+			set = u.ImageURL != nil
+		default:
+			return apperrors.Validation("clear", "field %q cannot be cleared", field)
+		}
+		if set {
+			return apperrors.Validation(string(field), "field %q cannot be both set and cleared", field)
+		}
+	}
+	return nil
+}
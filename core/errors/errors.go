@@ -0,0 +1,178 @@
+// Package apperrors defines the typed errors the core packages return, each
+// with a stable machine-readable code the frontend can react to and
+// localize instead of matching on message text.
+package apperrors
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Code identifies a kind of error. Codes are part of the API with the
+// frontend and must not change.
+type Code string
+
+const (
+	CodeNotFound    Code = "NOT_FOUND"
+	CodeValidation  Code = "VALIDATION"
+	CodeConflict    Code = "CONFLICT"
+	CodeUnavailable Code = "UNAVAILABLE"
+	CodeCancelled   Code = "CANCELLED"
+	// CodeInternal is reported for every error without a more specific code.
+	CodeInternal Code = "INTERNAL"
+)
+
+// Coded is implemented by the typed errors of this package.
+type Coded interface {
+	error
+	Code() Code
+}
+
+// NotFoundError is returned when the named resource does not exist.
+type NotFoundError struct {
+	// Resource names the kind of thing looked up, e.g. "product".
+	Resource string
+	ID       string
+}
+
+// NotFound returns a NotFoundError for the resource with the given ID.
+func NotFound(resource string, id interface{}) *NotFoundError {
+	return &NotFoundError{Resource: resource, ID: fmt.Sprint(id)}
+}
+
+func (e *NotFoundError) Error() string {
+	return fmt.Sprintf("%s with ID %s not found", e.Resource, e.ID)
+}
+
+func (e *NotFoundError) Code() Code { return CodeNotFound }
+
+// FieldError describes one invalid field of a request.
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// ValidationError is returned when a request is malformed. It lists every
+// problem found, each tied to the field it concerns.
+type ValidationError struct {
+	Fields []FieldError
+}
+
+// Validation returns a ValidationError for a single field.
+func Validation(field, format string, args ...interface{}) *ValidationError {
+	return new(ValidationError).Add(field, format, args...)
+}
+
+// Add records another invalid field and returns e.
+func (e *ValidationError) Add(field, format string, args ...interface{}) *ValidationError {
+	e.Fields = append(e.Fields, FieldError{Field: field, Message: fmt.Sprintf(format, args...)})
+	return e
+}
+
+// OrNil returns e if it has any fields and nil otherwise, so a validator can
+// collect problems and return the result directly.
+func (e *ValidationError) OrNil() error {
+	if e == nil || len(e.Fields) == 0 {
+		return nil
+	}
+	return e
+}
+
+func (e *ValidationError) Error() string {
+	messages := make([]string, len(e.Fields))
+	for i, field := range e.Fields {
+		messages[i] = field.Message
+	}
+	return strings.Join(messages, "; ")
+}
+
+func (e *ValidationError) Code() Code { return CodeValidation }
+
+// ConflictError is returned when a write clashes with the current state,
+// most often because the product was changed since it was read. For those
+// version conflicts ID, ExpectedVersion and CurrentVersion are set.
+type ConflictError struct {
+	Message         string
+	ID              int
+	ExpectedVersion int
+	CurrentVersion  int
+}
+
+// Conflict returns a ConflictError with the given message.
+func Conflict(format string, args ...interface{}) *ConflictError {
+	return &ConflictError{Message: fmt.Sprintf(format, args...)}
+}
+
+// VersionConflict returns the ConflictError for a write that named a product
+// version that is no longer current.
+func VersionConflict(id, expectedVersion, currentVersion int) *ConflictError {
+	return &ConflictError{
+		Message: fmt.Sprintf("product with ID %d was modified by another user (expected version %d, current version %d)",
+			id, expectedVersion, currentVersion),
+		ID:              id,
+		ExpectedVersion: expectedVersion,
+		CurrentVersion:  currentVersion,
+	}
+}
+
+func (e *ConflictError) Error() string { return e.Message }
+
+func (e *ConflictError) Code() Code { return CodeConflict }
+
+// UnavailableError is returned when a dependency the request needs, such as
+// the database or the exchange rate API, cannot be used right now.
+type UnavailableError struct {
+	Message string
+	Err     error
+}
+
+// Unavailable returns an UnavailableError caused by err, which may be nil.
+func Unavailable(err error, format string, args ...interface{}) *UnavailableError {
+	return &UnavailableError{Message: fmt.Sprintf(format, args...), Err: err}
+}
+
+func (e *UnavailableError) Error() string {
+	if e.Err == nil {
+		return e.Message
+	}
+	return e.Message + ": " + e.Err.Error()
+}
+
+func (e *UnavailableError) Unwrap() error { return e.Err }
+
+func (e *UnavailableError) Code() Code { return CodeUnavailable }
+
+// CancelledError is returned when an operation was stopped before it
+// finished, by the user or by a deadline. Errors wrapping context.Canceled
+// or context.DeadlineExceeded are reported as cancelled too, so code that
+// only returns ctx.Err() does not need to wrap it.
+type CancelledError struct {
+	Message string
+}
+
+// Cancelled returns a CancelledError with the given message.
+func Cancelled(format string, args ...interface{}) *CancelledError {
+	return &CancelledError{Message: fmt.Sprintf(format, args...)}
+}
+
+func (e *CancelledError) Error() string { return e.Message }
+
+func (e *CancelledError) Code() Code { return CodeCancelled }
+
+// CodeOf returns the code of the first typed error in err's chain,
+// CodeCancelled for context errors and CodeInternal for anything else.
+func CodeOf(err error) Code {
+	var coded Coded
+	switch {
+	case err == nil:
+		return ""
+	case errors.As(err, &coded):
+		return coded.Code()
+	case errors.Is(err, context.Canceled), errors.Is(err, context.DeadlineExceeded):
+		return CodeCancelled
+	default:
+		return CodeInternal
+	}
+}
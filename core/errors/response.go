@@ -0,0 +1,44 @@
+package apperrors
+
+import (
+	"context"
+	"errors"
+)
+
+// Response is how an error is sent to the frontend. Code selects the
+// localized text, Message is the English description for logs and as a
+// fallback, Fields lists the invalid fields of a ValidationError and Details
+// carries the values the localized text may need.
+type Response struct {
+	Code    Code                   `json:"code"`
+	Message string                 `json:"message"`
+	Fields  []FieldError           `json:"fields,omitempty"`
+	Details map[string]interface{} `json:"details,omitempty"`
+}
+
+// ToResponse converts err into a Response. It returns nil for a nil error.
+func ToResponse(err error) *Response {
+	if err == nil {
+		return nil
+	}
+	response := &Response{Code: CodeOf(err), Message: err.Error()}
+
+	var notFound *NotFoundError
+	var validation *ValidationError
+	var conflict *ConflictError
+	switch {
+	case errors.As(err, &notFound):
+		response.Details = map[string]interface{}{"resource": notFound.Resource, "id": notFound.ID}
+	case errors.As(err, &validation):
+		response.Fields = validation.Fields
+	case errors.As(err, &conflict) && conflict.ID != 0:
+		response.Details = map[string]interface{}{
+			"id":              conflict.ID,
+			"expectedVersion": conflict.ExpectedVersion,
+			"currentVersion":  conflict.CurrentVersion,
+		}
+	case errors.Is(err, context.DeadlineExceeded):
+		response.Details = map[string]interface{}{"timedOut": true}
+	}
+	return response
+}
@@ -0,0 +1,251 @@
+package migrations
+
+import (
+	"database/sql"
+	"fmt"
+)
+
+const createSchemaMigrationsSQL = `
+CREATE TABLE IF NOT EXISTS schema_migrations (
+	version INTEGER PRIMARY KEY,
+	name TEXT NOT NULL,
+	checksum TEXT NOT NULL,
+	applied_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);`
+
+// Migrator applies and reverts the embedded migrations, recording each one in
+// the schema_migrations table.
+type Migrator struct {
+	db         *sql.DB
+	migrations []Migration
+}
+
+// NewMigrator creates a Migrator for db using the embedded migrations.
+func NewMigrator(db *sql.DB) (*Migrator, error) {
+	migrations, err := Load()
+	if err != nil {
+		return nil, err
+	}
+	return &Migrator{db: db, migrations: migrations}, nil
+}
+
+// LatestVersion returns the version of the newest embedded migration.
+func (m *Migrator) LatestVersion() int {
+	if len(m.migrations) == 0 {
+		return 0
+	}
+	return m.migrations[len(m.migrations)-1].Version
+}
+
+// Prepare creates the schema_migrations table. On a database created before
+// migrations were tracked, it records the migrations whose changes are
+// already present so they are not applied twice.
+func (m *Migrator) Prepare() error {
+	if _, err := m.db.Exec(createSchemaMigrationsSQL); err != nil {
+		return fmt.Errorf("failed to create schema_migrations table: %w", err)
+	}
+
+	applied, err := m.Applied()
+	if err != nil {
+		return err
+	}
+	if len(applied) > 0 {
+		return nil
+	}
+
+	tx, err := m.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	for _, migration := range m.migrations {
+		check, ok := legacyChecks[migration.Version]
+		if !ok {
+			break
+		}
+		present, err := check(tx)
+		if err != nil {
+			return fmt.Errorf("failed to inspect legacy schema for migration %d: %w", migration.Version, err)
+		}
+		if !present {
+			break
+		}
+		if err := recordMigration(tx, migration); err != nil {
+			return err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to record legacy schema: %w", err)
+	}
+	return nil
+}
+
+// Applied returns the recorded migrations ordered by version.
+func (m *Migrator) Applied() ([]AppliedMigration, error) {
+	rows, err := m.db.Query("SELECT version, name, checksum, applied_at FROM schema_migrations ORDER BY version")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read schema_migrations: %w", err)
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	var applied []AppliedMigration
+	for rows.Next() {
+		var migration AppliedMigration
+		if err := rows.Scan(&migration.Version, &migration.Name, &migration.Checksum, &migration.AppliedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan schema_migrations: %w", err)
+		}
+		applied = append(applied, migration)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read schema_migrations: %w", err)
+	}
+	return applied, nil
+}
+
+// CurrentVersion returns the highest applied migration version, or 0 for an
+// empty database.
+func (m *Migrator) CurrentVersion() (int, error) {
+	var version int
+	if err := m.db.QueryRow("SELECT COALESCE(MAX(version), 0) FROM schema_migrations").Scan(&version); err != nil {
+		return 0, fmt.Errorf("failed to read schema version: %w", err)
+	}
+	return version, nil
+}
+
+// Pending verifies the applied migrations against the embedded ones and
+// returns those still to be applied.
+func (m *Migrator) Pending() ([]Migration, error) {
+	applied, err := m.Applied()
+	if err != nil {
+		return nil, err
+	}
+
+	byVersion := make(map[int]Migration, len(m.migrations))
+	for _, migration := range m.migrations {
+		byVersion[migration.Version] = migration
+	}
+
+	done := make(map[int]bool, len(applied))
+	for _, record := range applied {
+		migration, ok := byVersion[record.Version]
+		if !ok {
+			return nil, fmt.Errorf("database schema version %d is newer than this application supports (%d)", record.Version, m.LatestVersion())
+		}
+		if record.Checksum != migration.Checksum {
+			return nil, fmt.Errorf("migration %d_%s was modified after it was applied (checksum mismatch)", migration.Version, migration.Name)
+		}
+		done[record.Version] = true
+	}
+
+	var pending []Migration
+	for _, migration := range m.migrations {
+		if !done[migration.Version] {
+			pending = append(pending, migration)
+		}
+	}
+	return pending, nil
+}
+
+// Up applies every pending migration in order, each in its own transaction,
+// and returns the migrations that were applied.
+func (m *Migrator) Up() ([]Migration, error) {
+	return m.MigrateTo(m.LatestVersion())
+}
+
+// MigrateTo moves the schema to target, applying pending migrations up to it
+// or reverting applied migrations above it. It returns the migrations that
+// were applied or reverted, in execution order.
+func (m *Migrator) MigrateTo(target int) ([]Migration, error) {
+	if target < 0 || target > m.LatestVersion() {
+		return nil, fmt.Errorf("invalid schema version %d: must be between 0 and %d", target, m.LatestVersion())
+	}
+
+	pending, err := m.Pending()
+	if err != nil {
+		return nil, err
+	}
+
+	var executed []Migration
+	for _, migration := range pending {
+		if migration.Version > target {
+			break
+		}
+		if err := m.apply(migration); err != nil {
+			return executed, err
+		}
+		executed = append(executed, migration)
+	}
+
+	current, err := m.CurrentVersion()
+	if err != nil {
+		return executed, err
+	}
+	for version := current; version > target; version-- {
+		migration := m.migrations[version-1]
+		if err := m.revert(migration); err != nil {
+			return executed, err
+		}
+		executed = append(executed, migration)
+	}
+	return executed, nil
+}
+
+func (m *Migrator) apply(migration Migration) error {
+	tx, err := m.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin migration %d: %w", migration.Version, err)
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	if _, err := tx.Exec(migration.Up); err != nil {
+		return fmt.Errorf("migration %d_%s failed: %w", migration.Version, migration.Name, err)
+	}
+	if err := recordMigration(tx, migration); err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit migration %d: %w", migration.Version, err)
+	}
+	return nil
+}
+
+func (m *Migrator) revert(migration Migration) error {
+	if migration.Down == "" {
+		return fmt.Errorf("migration %d_%s cannot be reverted", migration.Version, migration.Name)
+	}
+
+	tx, err := m.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin revert of migration %d: %w", migration.Version, err)
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	if _, err := tx.Exec(migration.Down); err != nil {
+		return fmt.Errorf("revert of migration %d_%s failed: %w", migration.Version, migration.Name, err)
+	}
+	if _, err := tx.Exec("DELETE FROM schema_migrations WHERE version = ?", migration.Version); err != nil {
+		return fmt.Errorf("failed to unrecord migration %d: %w", migration.Version, err)
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit revert of migration %d: %w", migration.Version, err)
+	}
+	return nil
+}
+
+func recordMigration(tx *sql.Tx, migration Migration) error {
+	_, err := tx.Exec("INSERT INTO schema_migrations(version, name, checksum) VALUES(?, ?, ?)", migration.Version, migration.Name, migration.Checksum)
+	if err != nil {
+		return fmt.Errorf("failed to record migration %d: %w", migration.Version, err)
+	}
+	return nil
+}
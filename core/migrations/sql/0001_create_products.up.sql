@@ -0,0 +1,11 @@
+CREATE TABLE IF NOT EXISTS products (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	name TEXT NOT NULL,
+	price REAL NOT NULL,
+	category TEXT,
+	stock INTEGER DEFAULT 0,
+	description TEXT,
+	image_url TEXT,
+	created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+	updated_at TIMESTAMP
+);
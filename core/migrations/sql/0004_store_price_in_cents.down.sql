@@ -0,0 +1,5 @@
+ALTER TABLE products ADD COLUMN price REAL NOT NULL DEFAULT 0;
+
+UPDATE products SET price = price_cents / 100.0;
+
+ALTER TABLE products DROP COLUMN price_cents;
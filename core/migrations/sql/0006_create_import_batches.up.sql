@@ -0,0 +1,17 @@
+CREATE TABLE IF NOT EXISTS import_batches (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	source TEXT NOT NULL,
+	mode TEXT NOT NULL,
+	created_count INTEGER NOT NULL DEFAULT 0,
+	updated_count INTEGER NOT NULL DEFAULT 0,
+	created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+	rolled_back_at TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS import_batch_items (
+	batch_id INTEGER NOT NULL,
+	product_id INTEGER NOT NULL,
+	before_json TEXT,
+	version INTEGER NOT NULL,
+	PRIMARY KEY (batch_id, product_id)
+);
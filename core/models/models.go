@@ -1,15 +1,53 @@
 // Package models contains the data models for the product management application.
 package models
 
+import "product-management-app/core/money"
+
 // Product represents a product in the inventory management system.
 type Product struct {
-	ID          int     `json:"id"`
-	Name        string  `json:"name"`
-	Price       float64 `json:"price"`
-	Category    *string `json:"category,omitempty"`
-	Stock       int     `json:"stock"`
-	Description *string `json:"description,omitempty"`
-	ImageURL    *string `json:"imageUrl,omitempty"`
-	CreatedAt   string  `json:"createdAt"`
-	UpdatedAt   *string `json:"updatedAt,omitempty"`
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+	// SKU is the optional stock-keeping unit. It is unique across all
+	// products, including those in the trash.
+	SKU         *string     `json:"sku,omitempty"`
+	Price       money.Money `json:"price"`
+	Category    *string     `json:"category,omitempty"`
+	Stock       int         `json:"stock"`
+	Description *string     `json:"description,omitempty"`
+	ImageURL    *string     `json:"imageUrl,omitempty"`
+	CreatedAt   string      `json:"createdAt"`
+	UpdatedAt   *string     `json:"updatedAt,omitempty"`
+	// Version is incremented on every write and must be echoed back on
+	// updates and deletes so concurrent changes are detected.
+	Version int `json:"version"`
+	// DeletedAt is set while the product is in the trash.
+	DeletedAt *string `json:"deletedAt,omitempty"`
+}
+
+// ImportBatch records the products one committed import created or updated,
+// so the whole import can be rolled back later.
+type ImportBatch struct {
+	ID int `json:"id"`
+	// Source is the format of the imported file, "csv" or "xlsx".
+	Source       string `json:"source"`
+	Mode         string `json:"mode"`
+	CreatedCount int    `json:"createdCount"`
+	UpdatedCount int    `json:"updatedCount"`
+	CreatedAt    string `json:"createdAt"`
+	// RolledBackAt is set once the batch has been rolled back.
+	RolledBackAt *string `json:"rolledBackAt,omitempty"`
+	// Items lists the products the import wrote. Lists of batches leave it
+	// empty.
+	Items []ImportBatchItem `json:"items,omitempty"`
+}
+
+// ImportBatchItem is a product an import wrote, once per product even when
+// several rows wrote it.
+type ImportBatchItem struct {
+	ProductID int `json:"productId"`
+	// Before is the product as it was before the import, or nil if the
+	// import created it.
+	Before *Product `json:"before,omitempty"`
+	// Version is the product's version after the import.
+	Version int `json:"version"`
 }
@@ -0,0 +1,268 @@
+// Package money represents prices and amounts as integer minor units, so
+// sums and conversions do not pick up floating-point drift.
+package money
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"math"
+	"math/big"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Decimals is the number of decimal places a Money value holds.
+const Decimals = 2
+
+// MinorUnitsPerUnit is the number of minor units (cents) in one unit.
+const MinorUnitsPerUnit = 100
+
+// Money is an amount in minor units: Money(1999) is 19.99. It is stored as
+// an INTEGER column and sent to the frontend as a JSON number with two
+// decimals.
+type Money int64
+
+// Cent is the smallest representable amount.
+const Cent Money = 1
+
+// RoundingMode selects how a result that falls between two representable
+// amounts is rounded.
+type RoundingMode string
+
+const (
+	// RoundNearest rounds to the nearest amount, halves away from zero.
+	RoundNearest RoundingMode = "nearest"
+	// RoundHalfEven rounds to the nearest amount, halves to the even one.
+	RoundHalfEven RoundingMode = "halfEven"
+	// RoundUp rounds toward positive infinity.
+	RoundUp RoundingMode = "up"
+	// RoundDown rounds toward negative infinity.
+	RoundDown RoundingMode = "down"
+)
+
+// Valid reports whether r is a known rounding mode. The empty mode is valid
+// and means RoundNearest.
+func (r RoundingMode) Valid() bool {
+	switch r {
+	case "", RoundNearest, RoundHalfEven, RoundUp, RoundDown:
+		return true
+	}
+	return false
+}
+
+// decimalPattern matches the plain decimal numbers Parse accepts.
+var decimalPattern = regexp.MustCompile(`^[+-]?(\d+\.?\d*|\.\d+)$`)
+
+// FromMinorUnits returns the amount of n minor units.
+func FromMinorUnits(n int64) Money {
+	return Money(n)
+}
+
+// FromUnits returns the amount of n whole units, e.g. FromUnits(5) is 5.00.
+func FromUnits(n int64) Money {
+	return Money(n * MinorUnitsPerUnit)
+}
+
+// Parse converts a decimal string such as "19.99" or "-5" to Money. Digits
+// beyond the second decimal are rounded with mode.
+func Parse(s string, mode RoundingMode) (Money, error) {
+	s = strings.TrimSpace(s)
+	if !decimalPattern.MatchString(s) {
+		return 0, fmt.Errorf("invalid amount %q", s)
+	}
+	value, ok := new(big.Rat).SetString(s)
+	if !ok {
+		return 0, fmt.Errorf("invalid amount %q", s)
+	}
+	minor := roundRat(value.Mul(value, big.NewRat(MinorUnitsPerUnit, 1)), mode)
+	if !minor.IsInt64() {
+		return 0, fmt.Errorf("amount %q is out of range", s)
+	}
+	return Money(minor.Int64()), nil
+}
+
+// FromFloat converts f to Money, rounding with mode. f is read as the
+// shortest decimal that represents it, so 0.285 rounds to 0.29 with
+// RoundNearest even though the nearest float64 is slightly below it.
+func FromFloat(f float64, mode RoundingMode) Money {
+	value := ratOf(f)
+	return Money(roundRat(value.Mul(value, big.NewRat(MinorUnitsPerUnit, 1)), mode).Int64())
+}
+
+// MinorUnits returns m in minor units.
+func (m Money) MinorUnits() int64 {
+	return int64(m)
+}
+
+// Float64 returns m in units, for display and spreadsheets. Do not do
+// arithmetic on the result.
+func (m Money) Float64() float64 {
+	return float64(m) / MinorUnitsPerUnit
+}
+
+// String formats m with two decimals and no grouping, e.g. "-1234.50".
+func (m Money) String() string {
+	sign := ""
+	minor := int64(m)
+	if minor < 0 {
+		sign = "-"
+		minor = -minor
+	}
+	return fmt.Sprintf("%s%d.%02d", sign, minor/MinorUnitsPerUnit, minor%MinorUnitsPerUnit)
+}
+
+// Times returns m multiplied by n, e.g. a price times the units in stock.
+func (m Money) Times(n int) Money {
+	return m * Money(n)
+}
+
+// Mul returns m multiplied by factor and rounded with mode to a multiple of
+// increment, which defaults to Cent when it is not positive. The product is
+// computed exactly, reading factor as the shortest decimal that represents
+// it; a factor that is not finite yields zero.
+func (m Money) Mul(factor float64, increment Money, mode RoundingMode) Money {
+	return m.scale(ratOf(factor), increment, mode)
+}
+
+// AddPercent returns m raised by percent percent (-25 lowers it by a
+// quarter), rounded like Mul.
+func (m Money) AddPercent(percent float64, increment Money, mode RoundingMode) Money {
+	factor := ratOf(percent)
+	factor.Quo(factor, big.NewRat(100, 1))
+	factor.Add(factor, big.NewRat(1, 1))
+	return m.scale(factor, increment, mode)
+}
+
+// AddAmount returns m plus amount units (a negative amount lowers it),
+// rounded like Mul. amount is read as its shortest decimal, so adding 0.1
+// adds exactly ten cents.
+func (m Money) AddAmount(amount float64, increment Money, mode RoundingMode) Money {
+	value := ratOf(amount)
+	value.Mul(value, big.NewRat(MinorUnitsPerUnit, 1))
+	value.Add(value, new(big.Rat).SetInt64(int64(m)))
+	return Cent.scale(value, increment, mode)
+}
+
+// RoundTo rounds m with mode to a multiple of increment.
+func (m Money) RoundTo(increment Money, mode RoundingMode) Money {
+	return m.scale(big.NewRat(1, 1), increment, mode)
+}
+
+// scale returns m times factor rounded to a multiple of increment.
+func (m Money) scale(factor *big.Rat, increment Money, mode RoundingMode) Money {
+	if increment <= 0 {
+		increment = Cent
+	}
+	value := new(big.Rat).SetInt64(int64(m))
+	value.Mul(value, factor)
+	value.Quo(value, new(big.Rat).SetInt64(int64(increment)))
+	return Money(roundRat(value, mode).Int64()) * increment
+}
+
+// MarshalJSON encodes m as a JSON number with two decimals.
+func (m Money) MarshalJSON() ([]byte, error) {
+	return []byte(m.String()), nil
+}
+
+// UnmarshalJSON accepts a JSON number or a quoted decimal string. Digits
+// beyond the second decimal are rounded to the nearest cent.
+func (m *Money) UnmarshalJSON(data []byte) error {
+	text := string(data)
+	if text == "null" {
+		return nil
+	}
+	if unquoted, err := strconv.Unquote(text); err == nil {
+		text = unquoted
+	} else if strings.ContainsAny(text, "eE") {
+		// JSON allows exponents, which Parse does not.
+		f, err := strconv.ParseFloat(text, 64)
+		if err != nil {
+			return fmt.Errorf("invalid amount %s", text)
+		}
+		*m = FromFloat(f, RoundNearest)
+		return nil
+	}
+	parsed, err := Parse(text, RoundNearest)
+	if err != nil {
+		return err
+	}
+	*m = parsed
+	return nil
+}
+
+// Value stores m as its minor units.
+func (m Money) Value() (driver.Value, error) {
+	return int64(m), nil
+}
+
+// Scan reads an amount in minor units.
+func (m *Money) Scan(src interface{}) error {
+	switch v := src.(type) {
+	case nil:
+		*m = 0
+	case int64:
+		*m = Money(v)
+	case float64:
+		*m = Money(math.Round(v))
+	case []byte:
+		return m.Scan(string(v))
+	case string:
+		n, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid amount in minor units %q", v)
+		}
+		*m = Money(n)
+	default:
+		return fmt.Errorf("cannot scan %T into Money", src)
+	}
+	return nil
+}
+
+// ratOf returns f as the exact value of its shortest decimal representation.
+func ratOf(f float64) *big.Rat {
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		return new(big.Rat)
+	}
+	r, ok := new(big.Rat).SetString(strconv.FormatFloat(f, 'g', -1, 64))
+	if !ok {
+		return new(big.Rat)
+	}
+	return r
+}
+
+// roundRat rounds r to an integer with mode.
+func roundRat(r *big.Rat, mode RoundingMode) *big.Int {
+	// Euclidean division with a positive denominator floors the quotient
+	// and leaves 0 <= rem < den.
+	den := r.Denom()
+	quo, rem := new(big.Int).DivMod(r.Num(), den, new(big.Int))
+	if rem.Sign() == 0 {
+		return quo
+	}
+
+	one := big.NewInt(1)
+	switch mode {
+	case RoundUp:
+		return quo.Add(quo, one)
+	case RoundDown:
+		return quo
+	}
+
+	half := new(big.Int).Lsh(rem, 1).Cmp(den)
+	switch {
+	case half > 0:
+		return quo.Add(quo, one)
+	case half < 0:
+		return quo
+	case mode == RoundHalfEven:
+		if quo.Bit(0) == 1 {
+			quo.Add(quo, one)
+		}
+		return quo
+	case r.Sign() < 0:
+		return quo
+	default:
+		return quo.Add(quo, one)
+	}
+}
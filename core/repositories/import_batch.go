@@ -0,0 +1,193 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+
+	apperrors "product-management-app/core/errors"
+	"product-management-app/core/models"
+)
+
+// importBatchColumns lists the import_batches columns in the order
+// scanImportBatch expects.
+const importBatchColumns = "id, source, mode, created_count, updated_count, created_at, rolled_back_at"
+
+func scanImportBatch(row rowScanner) (*models.ImportBatch, error) {
+	var rolledBackAt sql.NullString
+	batch := &models.ImportBatch{}
+	if err := row.Scan(&batch.ID, &batch.Source, &batch.Mode, &batch.CreatedCount, &batch.UpdatedCount, &batch.CreatedAt, &rolledBackAt); err != nil {
+		return nil, err
+	}
+	if rolledBackAt.Valid {
+		batch.RolledBackAt = &rolledBackAt.String
+	}
+	return batch, nil
+}
+
+// CreateImportBatch records batch and its items in one transaction.
+func (r *ProductRepository) CreateImportBatch(ctx context.Context, batch models.ImportBatch) (*models.ImportBatch, error) {
+	var created *models.ImportBatch
+	err := r.inTx(ctx, func(tx *ProductRepository) error {
+		res, err := tx.q.ExecContext(ctx, "INSERT INTO import_batches(source, mode, created_count, updated_count) VALUES(?, ?, ?, ?)",
+			batch.Source, batch.Mode, batch.CreatedCount, batch.UpdatedCount)
+		if err != nil {
+			return fmt.Errorf("failed to create import batch: %w", err)
+		}
+		id, _ := res.LastInsertId()
+		if err := tx.insertImportBatchItems(ctx, int(id), batch.Items); err != nil {
+			return err
+		}
+
+		created, err = scanImportBatch(tx.q.QueryRowContext(ctx, "SELECT "+importBatchColumns+" FROM import_batches WHERE id = ?", id))
+		if err != nil {
+			return fmt.Errorf("failed to fetch import batch: %w", err)
+		}
+		created.Items = batch.Items
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return created, nil
+}
+
+// AddImportBatchItems adds items to a batch and recounts the products it
+// created and updated.
+func (r *ProductRepository) AddImportBatchItems(ctx context.Context, id int, items []models.ImportBatchItem) error {
+	return r.inTx(ctx, func(tx *ProductRepository) error {
+		if err := tx.insertImportBatchItems(ctx, id, items); err != nil {
+			return err
+		}
+		res, err := tx.q.ExecContext(ctx, `UPDATE import_batches SET
+	created_count = (SELECT COUNT(*) FROM import_batch_items WHERE batch_id = ? AND before_json IS NULL),
+	updated_count = (SELECT COUNT(*) FROM import_batch_items WHERE batch_id = ? AND before_json IS NOT NULL)
+WHERE id = ?`, id, id, id)
+		if err != nil {
+			return fmt.Errorf("failed to update import batch: %w", err)
+		}
+		rowsAffected, err := res.RowsAffected()
+		if err != nil {
+			return fmt.Errorf("failed to get rows affected: %w", err)
+		}
+		if rowsAffected == 0 {
+			return apperrors.NotFound("import batch", id)
+		}
+		return nil
+	})
+}
+
+// insertImportBatchItems stores items in the batch id. The product each
+// item was before the import is stored as JSON, and a product already in
+// the batch only has its version updated.
+func (r *ProductRepository) insertImportBatchItems(ctx context.Context, id int, items []models.ImportBatchItem) error {
+	for _, item := range items {
+		var before interface{}
+		if item.Before != nil {
+			data, err := json.Marshal(item.Before)
+			if err != nil {
+				return fmt.Errorf("failed to encode product %d: %w", item.ProductID, err)
+			}
+			before = string(data)
+		}
+		if _, err := r.q.ExecContext(ctx, `INSERT INTO import_batch_items(batch_id, product_id, before_json, version) VALUES(?, ?, ?, ?)
+ON CONFLICT(batch_id, product_id) DO UPDATE SET version = excluded.version`, id, item.ProductID, before, item.Version); err != nil {
+			return fmt.Errorf("failed to record product %d in import batch: %w", item.ProductID, err)
+		}
+	}
+	return nil
+}
+
+// GetImportBatch retrieves a batch and its items.
+func (r *ProductRepository) GetImportBatch(ctx context.Context, id int) (*models.ImportBatch, error) {
+	batch, err := scanImportBatch(r.q.QueryRowContext(ctx, "SELECT "+importBatchColumns+" FROM import_batches WHERE id = ?", id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, apperrors.NotFound("import batch", id)
+		}
+		return nil, fmt.Errorf("failed to fetch import batch: %w", err)
+	}
+
+	rows, err := r.q.QueryContext(ctx, "SELECT product_id, before_json, version FROM import_batch_items WHERE batch_id = ? ORDER BY rowid", id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch import batch items: %w", err)
+	}
+	defer func() {
+		if err := rows.Close(); err != nil {
+			r.logger.ErrorContext(ctx, "Failed to close rows", "error", err)
+		}
+	}()
+
+	batch.Items = []models.ImportBatchItem{}
+	for rows.Next() {
+		var item models.ImportBatchItem
+		var before sql.NullString
+		if err := rows.Scan(&item.ProductID, &before, &item.Version); err != nil {
+			return nil, fmt.Errorf("failed to scan import batch item: %w", err)
+		}
+		if before.Valid {
+			item.Before = &models.Product{}
+			if err := json.Unmarshal([]byte(before.String), item.Before); err != nil {
+				return nil, fmt.Errorf("failed to decode product %d: %w", item.ProductID, err)
+			}
+		}
+		batch.Items = append(batch.Items, item)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate import batch items: %w", err)
+	}
+	return batch, nil
+}
+
+// ListImportBatches returns up to limit batches, newest first.
+func (r *ProductRepository) ListImportBatches(ctx context.Context, limit int) ([]*models.ImportBatch, error) {
+	rows, err := r.q.QueryContext(ctx, "SELECT "+importBatchColumns+" FROM import_batches ORDER BY id DESC LIMIT ?", limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch import batches: %w", err)
+	}
+	defer func() {
+		if err := rows.Close(); err != nil {
+			r.logger.ErrorContext(ctx, "Failed to close rows", "error", err)
+		}
+	}()
+
+	batches := []*models.ImportBatch{}
+	for rows.Next() {
+		batch, err := scanImportBatch(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan import batch: %w", err)
+		}
+		batches = append(batches, batch)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate import batches: %w", err)
+	}
+	return batches, nil
+}
+
+// MarkImportBatchRolledBack sets the rollback time of a batch that has not
+// been rolled back yet.
+func (r *ProductRepository) MarkImportBatchRolledBack(ctx context.Context, id int) error {
+	res, err := r.q.ExecContext(ctx, "UPDATE import_batches SET rolled_back_at = CURRENT_TIMESTAMP WHERE id = ? AND rolled_back_at IS NULL", id)
+	if err != nil {
+		return fmt.Errorf("failed to mark import batch as rolled back: %w", err)
+	}
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected > 0 {
+		return nil
+	}
+
+	var exists int
+	err = r.q.QueryRowContext(ctx, "SELECT 1 FROM import_batches WHERE id = ?", id).Scan(&exists)
+	if err == sql.ErrNoRows {
+		return apperrors.NotFound("import batch", id)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to fetch import batch: %w", err)
+	}
+	return apperrors.Conflict("import batch %d was already rolled back", id)
+}
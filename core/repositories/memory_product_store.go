@@ -0,0 +1,949 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+
+	"product-management-app/core/dto"
+	apperrors "product-management-app/core/errors"
+	"product-management-app/core/models"
+)
+
+// sqliteTimeLayout is the layout of SQLite's datetime(), which the filters
+// and the trash retention compare against.
+const sqliteTimeLayout = "2006-01-02 15:04:05"
+
+// MemoryProductStore is a ProductStore that keeps products in memory. It
+// follows the same rules as ProductRepository for versions, the trash,
+// filters, sorting and search, so it can stand in for the database in tests
+// and in work that must not touch the database file.
+type MemoryProductStore struct {
+	mu    sync.Mutex
+	state *memoryState
+}
+
+var _ ProductStore = (*MemoryProductStore)(nil)
+
+// NewMemoryProductStore creates an empty MemoryProductStore.
+func NewMemoryProductStore() *MemoryProductStore {
+	return &MemoryProductStore{state: &memoryState{products: map[int]*models.Product{}}}
+}
+
+// WithTx runs fn against a copy of the store and keeps the copy if fn returns
+// nil and ctx is not done. Other callers wait until fn returns, and fn must
+// only use the store it is given.
+func (s *MemoryProductStore) WithTx(ctx context.Context, fn func(tx ProductStore) error) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	tx := s.state.clone()
+	if err := fn(tx); err != nil {
+		return err
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	s.state = tx
+	return nil
+}
+
+func (s *MemoryProductStore) Create(ctx context.Context, createProductDTO dto.CreateProductDTO) (*models.Product, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.state.Create(ctx, createProductDTO)
+}
+
+func (s *MemoryProductStore) GetByID(ctx context.Context, id int) (*models.Product, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.state.GetByID(ctx, id)
+}
+
+func (s *MemoryProductStore) GetBySKU(ctx context.Context, sku string) (*models.Product, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.state.GetBySKU(ctx, sku)
+}
+
+func (s *MemoryProductStore) FindByName(ctx context.Context, name string) ([]*models.Product, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.state.FindByName(ctx, name)
+}
+
+func (s *MemoryProductStore) GetAll(ctx context.Context, params dto.PaginationDTO) (*dto.PaginationResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.state.GetAll(ctx, params)
+}
+
+func (s *MemoryProductStore) Update(ctx context.Context, id int, update dto.UpdateProductDTO) (*models.Product, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.state.Update(ctx, id, update)
+}
+
+func (s *MemoryProductStore) Delete(ctx context.Context, id, version int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.state.Delete(ctx, id, version)
+}
+
+func (s *MemoryProductStore) Search(ctx context.Context, params dto.ProductSearchDTO) (*dto.ProductSearchResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.state.Search(ctx, params)
+}
+
+func (s *MemoryProductStore) GetTrash(ctx context.Context, params dto.PaginationDTO) (*dto.PaginationResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.state.GetTrash(ctx, params)
+}
+
+func (s *MemoryProductStore) Restore(ctx context.Context, id, version int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.state.Restore(ctx, id, version)
+}
+
+func (s *MemoryProductStore) Purge(ctx context.Context, id int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.state.Purge(ctx, id)
+}
+
+func (s *MemoryProductStore) PurgeOlderThan(ctx context.Context, days int) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.state.PurgeOlderThan(ctx, days)
+}
+
+func (s *MemoryProductStore) CreateImportBatch(ctx context.Context, batch models.ImportBatch) (*models.ImportBatch, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.state.CreateImportBatch(ctx, batch)
+}
+
+func (s *MemoryProductStore) AddImportBatchItems(ctx context.Context, id int, items []models.ImportBatchItem) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.state.AddImportBatchItems(ctx, id, items)
+}
+
+func (s *MemoryProductStore) GetImportBatch(ctx context.Context, id int) (*models.ImportBatch, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.state.GetImportBatch(ctx, id)
+}
+
+func (s *MemoryProductStore) ListImportBatches(ctx context.Context, limit int) ([]*models.ImportBatch, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.state.ListImportBatches(ctx, limit)
+}
+
+func (s *MemoryProductStore) MarkImportBatchRolledBack(ctx context.Context, id int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.state.MarkImportBatchRolledBack(ctx, id)
+}
+
+// BulkApply applies op to every selected product in one transaction.
+func (s *MemoryProductStore) BulkApply(ctx context.Context, op dto.BulkOperationDTO) (*dto.BulkOperationResult, error) {
+	return ApplyBulkOperation(ctx, s, op)
+}
+
+// memoryState holds the products of a MemoryProductStore. It does no locking
+// of its own; the store hands it to WithTx callers as the transaction.
+type memoryState struct {
+	products map[int]*models.Product
+	// lastID is the highest ID ever assigned. Like AUTOINCREMENT, IDs of
+	// purged products are not reused.
+	lastID int
+	// batches holds the import batches in ID order. Stored batches are
+	// never changed in place, so clones can share them.
+	batches []*models.ImportBatch
+}
+
+func (m *memoryState) clone() *memoryState {
+	products := make(map[int]*models.Product, len(m.products))
+	for id, product := range m.products {
+		products[id] = cloneProduct(product)
+	}
+	return &memoryState{products: products, lastID: m.lastID, batches: slices.Clone(m.batches)}
+}
+
+// cloneProduct copies a product, including the strings its fields point to,
+// so callers cannot change the stored product through the returned one.
+func cloneProduct(product *models.Product) *models.Product {
+	copied := *product
+	for _, field := range []**string{&copied.SKU, &copied.Category, &copied.Description, &copied.ImageURL, &copied.UpdatedAt, &copied.DeletedAt} {
+		if *field != nil {
+			value := **field
+			*field = &value
+		}
+	}
+	return &copied
+}
+
+// memoryTimestamp returns the current time in the form ProductRepository
+// reads its TIMESTAMP columns back in: both SQLite drivers parse the stored
+// CURRENT_TIMESTAMP text and hand it out as RFC 3339 in UTC.
+func memoryTimestamp() string {
+	return time.Now().UTC().Format(time.RFC3339)
+}
+
+// WithTx runs fn in the transaction the state already belongs to.
+func (m *memoryState) WithTx(ctx context.Context, fn func(tx ProductStore) error) error {
+	return fn(m)
+}
+
+func (m *memoryState) BulkApply(ctx context.Context, op dto.BulkOperationDTO) (*dto.BulkOperationResult, error) {
+	return ApplyBulkOperation(ctx, m, op)
+}
+
+func (m *memoryState) Create(ctx context.Context, createProductDTO dto.CreateProductDTO) (*models.Product, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if err := m.checkSKUFree(createProductDTO.SKU, 0); err != nil {
+		return nil, err
+	}
+	m.lastID++
+	product := &models.Product{
+		ID:          m.lastID,
+		Name:        createProductDTO.Name,
+		SKU:         optionalString(createProductDTO.SKU),
+		Price:       createProductDTO.Price,
+		Category:    optionalString(createProductDTO.Category),
+		Stock:       createProductDTO.Stock,
+		Description: optionalString(createProductDTO.Description),
+		ImageURL:    optionalString(createProductDTO.ImageURL),
+		CreatedAt:   memoryTimestamp(),
+		Version:     1,
+	}
+	m.products[product.ID] = product
+	return cloneProduct(product), nil
+}
+
+func (m *memoryState) GetByID(ctx context.Context, id int) (*models.Product, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	product, ok := m.products[id]
+	if !ok || product.DeletedAt != nil {
+		return nil, apperrors.NotFound("product", id)
+	}
+	return cloneProduct(product), nil
+}
+
+func (m *memoryState) GetBySKU(ctx context.Context, sku string) (*models.Product, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	for _, product := range m.products {
+		if product.DeletedAt == nil && product.SKU != nil && *product.SKU == sku {
+			return cloneProduct(product), nil
+		}
+	}
+	return nil, apperrors.NotFound("product with SKU", sku)
+}
+
+func (m *memoryState) FindByName(ctx context.Context, name string) ([]*models.Product, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	products := []*models.Product{}
+	for _, product := range m.products {
+		if product.DeletedAt == nil && product.Name == name {
+			products = append(products, cloneProduct(product))
+		}
+	}
+	sortProducts(products, "id", "asc")
+	return products, nil
+}
+
+// checkSKUFree reports the same conflict as ProductRepository.checkSKUFree.
+func (m *memoryState) checkSKUFree(sku string, exceptID int) error {
+	if sku == "" {
+		return nil
+	}
+	for id, product := range m.products {
+		if id != exceptID && product.SKU != nil && *product.SKU == sku {
+			return apperrors.Conflict("SKU %q is already used by product %d", sku, id)
+		}
+	}
+	return nil
+}
+
+// optionalString returns nil for an empty value, the way the repository
+// stores an empty SKU as NULL.
+func optionalString(value string) *string {
+	if value == "" {
+		return nil
+	}
+	return &value
+}
+
+func (m *memoryState) GetAll(ctx context.Context, params dto.PaginationDTO) (*dto.PaginationResponse, error) {
+	return m.list(ctx, params, false)
+}
+
+func (m *memoryState) GetTrash(ctx context.Context, params dto.PaginationDTO) (*dto.PaginationResponse, error) {
+	if params.SortBy == "" {
+		params.SortBy = "deleted_at"
+		params.Order = "desc"
+	}
+	return m.list(ctx, params, true)
+}
+
+// list returns a page of live or trashed products matching params.
+func (m *memoryState) list(ctx context.Context, params dto.PaginationDTO, trashed bool) (*dto.PaginationResponse, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if err := params.Validate(); err != nil {
+		return nil, err
+	}
+
+	search := asciiLower(strings.TrimSpace(params.Search))
+	matches := []*models.Product{}
+	for _, product := range m.products {
+		if (product.DeletedAt != nil) != trashed {
+			continue
+		}
+		if search != "" && !containsFold(search, &product.Name, product.Category, product.Description) {
+			continue
+		}
+		if params.Filters != nil && !matchesFilter(product, params.Filters) {
+			continue
+		}
+		matches = append(matches, product)
+	}
+	sortProducts(matches, params.SortBy, params.Order)
+
+	offset := (params.Page - 1) * params.PageSize
+	products := []*models.Product{}
+	for i := offset; i < len(matches) && i < offset+params.PageSize; i++ {
+		products = append(products, cloneProduct(matches[i]))
+	}
+
+	return &dto.PaginationResponse{
+		Products:   products,
+		TotalCount: len(matches),
+		TotalPages: (len(matches) + params.PageSize - 1) / params.PageSize,
+		Page:       params.Page,
+		PageSize:   params.PageSize,
+	}, nil
+}
+
+// containsFold reports whether any of fields contains the search term, as
+// lowered by asciiLower, ignoring case like SQLite's LIKE.
+func containsFold(search string, fields ...*string) bool {
+	for _, field := range fields {
+		if field != nil && strings.Contains(asciiLower(*field), search) {
+			return true
+		}
+	}
+	return false
+}
+
+// asciiLower lowers the ASCII letters of s and leaves every other character
+// alone, since SQLite's LIKE only ignores the case of ASCII letters.
+func asciiLower(s string) string {
+	return strings.Map(func(r rune) rune {
+		if 'A' <= r && r <= 'Z' {
+			return r + 'a' - 'A'
+		}
+		return r
+	}, s)
+}
+
+// matchesFilter applies a validated filter the way buildFilterConditions
+// does in SQL.
+func matchesFilter(product *models.Product, filter *dto.ProductFilterDTO) bool {
+	if filter.MinPrice != nil && product.Price < *filter.MinPrice {
+		return false
+	}
+	if filter.MaxPrice != nil && product.Price > *filter.MaxPrice {
+		return false
+	}
+	if filter.MinStock != nil && product.Stock < *filter.MinStock {
+		return false
+	}
+	if filter.MaxStock != nil && product.Stock > *filter.MaxStock {
+		return false
+	}
+
+	if len(filter.Categories) > 0 {
+		found := false
+		for _, category := range filter.Categories {
+			if product.Category != nil && *product.Category == category {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+
+	if filter.HasImage != nil && isPresent(product.ImageURL) != *filter.HasImage {
+		return false
+	}
+	if filter.HasDescription != nil && isPresent(product.Description) != *filter.HasDescription {
+		return false
+	}
+
+	createdAt := &product.CreatedAt
+	dateBounds := []struct {
+		value      *string
+		bound      string
+		upperBound bool
+	}{
+		{createdAt, filter.CreatedFrom, false},
+		{createdAt, filter.CreatedTo, true},
+		{product.UpdatedAt, filter.UpdatedFrom, false},
+		{product.UpdatedAt, filter.UpdatedTo, true},
+	}
+	for _, date := range dateBounds {
+		// Validate has already rejected malformed dates.
+		bound, _ := dto.ParseFilterDate(date.bound, date.upperBound)
+		if bound == "" {
+			continue
+		}
+		value, ok := sqliteTime(date.value)
+		if !ok || (date.upperBound && value > bound) || (!date.upperBound && value < bound) {
+			return false
+		}
+	}
+	return true
+}
+
+func isPresent(value *string) bool {
+	return value != nil && *value != ""
+}
+
+// sqliteTime converts a stored timestamp to the layout of SQLite's
+// datetime(), reporting false for NULL or unparsable values.
+func sqliteTime(value *string) (string, bool) {
+	if value == nil {
+		return "", false
+	}
+	t, err := time.Parse(time.RFC3339, *value)
+	if err != nil {
+		return "", false
+	}
+	return t.UTC().Format(sqliteTimeLayout), true
+}
+
+// sortProducts orders products like buildOrderClause: by the whitelisted
+// column with ties broken by ID, NULLs first, falling back to ID.
+func sortProducts(products []*models.Product, sortBy, order string) {
+	column, ok := productSortColumns[sortBy]
+	if !ok {
+		column = "id"
+	}
+	desc := strings.EqualFold(order, "desc")
+
+	sort.Slice(products, func(i, j int) bool {
+		c := compareColumn(products[i], products[j], column)
+		if c == 0 {
+			c = compareInts(products[i].ID, products[j].ID)
+		}
+		if desc {
+			return c > 0
+		}
+		return c < 0
+	})
+}
+
+func compareColumn(a, b *models.Product, column string) int {
+	switch column {
+	case "name":
+		return strings.Compare(a.Name, b.Name)
+	case "price_cents":
+		switch {
+		case a.Price < b.Price:
+			return -1
+		case a.Price > b.Price:
+			return 1
+		}
+		return 0
+	case "stock":
+		return compareInts(a.Stock, b.Stock)
+	case "category":
+		return compareNullable(a.Category, b.Category)
+	case "created_at":
+		return strings.Compare(a.CreatedAt, b.CreatedAt)
+	case "updated_at":
+		return compareNullable(a.UpdatedAt, b.UpdatedAt)
+	case "deleted_at":
+		return compareNullable(a.DeletedAt, b.DeletedAt)
+	}
+	return compareInts(a.ID, b.ID)
+}
+
+func compareInts(a, b int) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+// compareNullable orders NULL before any value, as SQLite does.
+func compareNullable(a, b *string) int {
+	switch {
+	case a == nil && b == nil:
+		return 0
+	case a == nil:
+		return -1
+	case b == nil:
+		return 1
+	}
+	return strings.Compare(*a, *b)
+}
+
+func (m *memoryState) Update(ctx context.Context, id int, update dto.UpdateProductDTO) (*models.Product, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if err := update.Validate(); err != nil {
+		return nil, err
+	}
+	product, ok := m.products[id]
+	if !ok || product.DeletedAt != nil || product.Version != update.Version {
+		return nil, m.missedWriteError(id, update.Version, false)
+	}
+
+	if update.SKU != nil {
+		if err := m.checkSKUFree(*update.SKU, id); err != nil {
+			return nil, err
+		}
+	}
+
+	if update.Name != nil {
+		product.Name = *update.Name
+	}
+	if update.SKU != nil {
+		product.SKU = optionalString(*update.SKU)
+	}
+	if update.Price != nil {
+		product.Price = *update.Price
+	}
+	if update.Category != nil {
+		product.Category = optionalString(*update.Category)
+	}
+	if update.Stock != nil {
+		product.Stock = *update.Stock
+	}
+	if update.Description != nil {
+		product.Description = optionalString(*update.Description)
+	}
+	if update.ImageURL != nil {
+		product.ImageURL = optionalString(*update.ImageURL)
+	}
+	for _, field := range update.Clear {
+		switch field {
+		case dto.FieldSKU:
+			product.SKU = nil
+		case dto.FieldCategory:
+			product.Category = nil
+		case dto.FieldDescription:
+			product.Description = nil
+		case dto.FieldImageURL:
+			product.ImageURL = nil
+		}
+	}
+	updatedAt := memoryTimestamp()
+	product.UpdatedAt = &updatedAt
+	product.Version++
+
+	return cloneProduct(product), nil
+}
+
+// missedWriteError explains why a versioned write was refused, with the same
+// errors as ProductRepository.missedWriteError.
+func (m *memoryState) missedWriteError(id, expectedVersion int, expectTrashed bool) error {
+	product, ok := m.products[id]
+	trashed := ok && product.DeletedAt != nil
+	if !ok || (trashed && !expectTrashed) {
+		return apperrors.NotFound("product", id)
+	}
+	if !trashed && expectTrashed {
+		return apperrors.NotFound("trashed product", id)
+	}
+	return apperrors.VersionConflict(id, expectedVersion, product.Version)
+}
+
+func (m *memoryState) Delete(ctx context.Context, id, version int) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	product, ok := m.products[id]
+	if !ok || product.DeletedAt != nil || product.Version != version {
+		return m.missedWriteError(id, version, false)
+	}
+	deletedAt := memoryTimestamp()
+	product.DeletedAt = &deletedAt
+	product.Version++
+	return nil
+}
+
+func (m *memoryState) Restore(ctx context.Context, id, version int) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	product, ok := m.products[id]
+	if !ok || product.DeletedAt == nil || product.Version != version {
+		return m.missedWriteError(id, version, true)
+	}
+	updatedAt := memoryTimestamp()
+	product.DeletedAt = nil
+	product.UpdatedAt = &updatedAt
+	product.Version++
+	return nil
+}
+
+func (m *memoryState) Purge(ctx context.Context, id int) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	product, ok := m.products[id]
+	if !ok || product.DeletedAt == nil {
+		return apperrors.NotFound("trashed product", id)
+	}
+	delete(m.products, id)
+	return nil
+}
+
+func (m *memoryState) PurgeOlderThan(ctx context.Context, days int) (int, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+	if days < 0 {
+		return 0, fmt.Errorf("invalid retention of %d days: must not be negative", days)
+	}
+
+	cutoff := time.Now().UTC().AddDate(0, 0, -days).Format(sqliteTimeLayout)
+	purged := 0
+	for id, product := range m.products {
+		if deletedAt, ok := sqliteTime(product.DeletedAt); ok && deletedAt <= cutoff {
+			delete(m.products, id)
+			purged++
+		}
+	}
+	return purged, nil
+}
+
+func (m *memoryState) CreateImportBatch(ctx context.Context, batch models.ImportBatch) (*models.ImportBatch, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	batch.ID = len(m.batches) + 1
+	batch.CreatedAt = memoryTimestamp()
+	batch.RolledBackAt = nil
+	batch.Items = cloneBatchItems(batch.Items)
+	m.batches = append(m.batches, &batch)
+	return cloneImportBatch(&batch, true), nil
+}
+
+func (m *memoryState) AddImportBatchItems(ctx context.Context, id int, items []models.ImportBatchItem) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if id < 1 || id > len(m.batches) {
+		return apperrors.NotFound("import batch", id)
+	}
+	batch := cloneImportBatch(m.batches[id-1], true)
+	positions := make(map[int]int, len(batch.Items))
+	for i, item := range batch.Items {
+		positions[item.ProductID] = i
+	}
+	for _, item := range cloneBatchItems(items) {
+		if i, ok := positions[item.ProductID]; ok {
+			batch.Items[i].Version = item.Version
+			continue
+		}
+		positions[item.ProductID] = len(batch.Items)
+		batch.Items = append(batch.Items, item)
+		if item.Before == nil {
+			batch.CreatedCount++
+		} else {
+			batch.UpdatedCount++
+		}
+	}
+	m.batches[id-1] = batch
+	return nil
+}
+
+func (m *memoryState) GetImportBatch(ctx context.Context, id int) (*models.ImportBatch, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if id < 1 || id > len(m.batches) {
+		return nil, apperrors.NotFound("import batch", id)
+	}
+	return cloneImportBatch(m.batches[id-1], true), nil
+}
+
+func (m *memoryState) ListImportBatches(ctx context.Context, limit int) ([]*models.ImportBatch, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	batches := []*models.ImportBatch{}
+	for i := len(m.batches) - 1; i >= 0 && len(batches) < limit; i-- {
+		batches = append(batches, cloneImportBatch(m.batches[i], false))
+	}
+	return batches, nil
+}
+
+func (m *memoryState) MarkImportBatchRolledBack(ctx context.Context, id int) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if id < 1 || id > len(m.batches) {
+		return apperrors.NotFound("import batch", id)
+	}
+	if m.batches[id-1].RolledBackAt != nil {
+		return apperrors.Conflict("import batch %d was already rolled back", id)
+	}
+	rolledBack := cloneImportBatch(m.batches[id-1], true)
+	rolledBackAt := memoryTimestamp()
+	rolledBack.RolledBackAt = &rolledBackAt
+	m.batches[id-1] = rolledBack
+	return nil
+}
+
+// cloneImportBatch copies a batch, with its items if withItems is set.
+func cloneImportBatch(batch *models.ImportBatch, withItems bool) *models.ImportBatch {
+	copied := *batch
+	if batch.RolledBackAt != nil {
+		rolledBackAt := *batch.RolledBackAt
+		copied.RolledBackAt = &rolledBackAt
+	}
+	copied.Items = nil
+	if withItems {
+		copied.Items = cloneBatchItems(batch.Items)
+	}
+	return &copied
+}
+
+func cloneBatchItems(items []models.ImportBatchItem) []models.ImportBatchItem {
+	copied := make([]models.ImportBatchItem, len(items))
+	for i, item := range items {
+		copied[i] = item
+		if item.Before != nil {
+			copied[i].Before = cloneProduct(item.Before)
+		}
+	}
+	return copied
+}
+
+// Search approximates the FTS5 search: every query word must be a prefix of
+// a word in the name, description or category, ignoring case. The score adds
+// up the weights ProductRepository gives bm25 for the fields that match.
+func (m *memoryState) Search(ctx context.Context, params dto.ProductSearchDTO) (*dto.ProductSearchResponse, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if err := params.Validate(); err != nil {
+		return nil, err
+	}
+
+	response := &dto.ProductSearchResponse{
+		Results:  []*dto.ProductSearchResult{},
+		Page:     params.Page,
+		PageSize: params.PageSize,
+	}
+	terms := searchTerms(params.Query)
+	if len(terms) == 0 {
+		return response, nil
+	}
+
+	var results []*dto.ProductSearchResult
+	for _, product := range m.products {
+		if product.DeletedAt != nil {
+			continue
+		}
+		fields := []struct {
+			value  *string
+			weight float64
+		}{
+			{&product.Name, 10},
+			{product.Description, 1},
+			{product.Category, 5},
+		}
+
+		score := 0.0
+		matchedAll := true
+		for _, term := range terms {
+			matched := false
+			for _, field := range fields {
+				if field.value != nil && matchesTerm(*field.value, term) {
+					score += field.weight
+					matched = true
+				}
+			}
+			if !matched {
+				matchedAll = false
+				break
+			}
+		}
+		if !matchedAll {
+			continue
+		}
+
+		result := &dto.ProductSearchResult{
+			Product:       cloneProduct(product),
+			Rank:          score,
+			NameHighlight: renderHighlight(highlightTerms(product.Name, terms)),
+		}
+		if product.Category != nil {
+			result.CategoryHighlight = renderHighlight(highlightTerms(*product.Category, terms))
+		}
+		if product.Description != nil {
+			result.DescriptionSnippet = renderHighlight(snippetTerms(*product.Description, terms, 16))
+		}
+		results = append(results, result)
+	}
+
+	sort.Slice(results, func(i, j int) bool {
+		if results[i].Rank != results[j].Rank {
+			return results[i].Rank > results[j].Rank
+		}
+		return results[i].Product.ID < results[j].Product.ID
+	})
+
+	response.TotalCount = len(results)
+	response.TotalPages = (response.TotalCount + params.PageSize - 1) / params.PageSize
+	offset := (params.Page - 1) * params.PageSize
+	for i := offset; i < len(results) && i < offset+params.PageSize; i++ {
+		response.Results = append(response.Results, results[i])
+	}
+	return response, nil
+}
+
+// searchTerms splits a query into lowercase words the way
+// buildMatchExpression does.
+func searchTerms(query string) []string {
+	words := strings.FieldsFunc(query, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	})
+	for i, word := range words {
+		words[i] = strings.ToLower(word)
+	}
+	return words
+}
+
+// wordSpans returns the byte ranges of the words in text.
+func wordSpans(text string) [][2]int {
+	var spans [][2]int
+	start := -1
+	for i, r := range text {
+		isWord := unicode.IsLetter(r) || unicode.IsNumber(r)
+		if isWord && start < 0 {
+			start = i
+		} else if !isWord && start >= 0 {
+			spans = append(spans, [2]int{start, i})
+			start = -1
+		}
+	}
+	if start >= 0 {
+		spans = append(spans, [2]int{start, len(text)})
+	}
+	return spans
+}
+
+func matchesTerm(text, term string) bool {
+	for _, span := range wordSpans(text) {
+		if strings.HasPrefix(strings.ToLower(text[span[0]:span[1]]), term) {
+			return true
+		}
+	}
+	return false
+}
+
+func wordMatches(word string, terms []string) bool {
+	word = strings.ToLower(word)
+	for _, term := range terms {
+		if strings.HasPrefix(word, term) {
+			return true
+		}
+	}
+	return false
+}
+
+// highlightTerms wraps the words of text that match a term in the highlight
+// sentinels.
+func highlightTerms(text string, terms []string) string {
+	return markSpans(text, wordSpans(text), terms)
+}
+
+func markSpans(text string, spans [][2]int, terms []string) string {
+	var b strings.Builder
+	last := 0
+	for _, span := range spans {
+		word := text[span[0]:span[1]]
+		if !wordMatches(word, terms) {
+			continue
+		}
+		b.WriteString(text[last:span[0]])
+		b.WriteString(highlightOpen + word + highlightClose)
+		last = span[1]
+	}
+	b.WriteString(text[last:])
+	return b.String()
+}
+
+// snippetTerms returns at most size words of text starting at the first
+// match, highlighted, with an ellipsis where text was cut.
+func snippetTerms(text string, terms []string, size int) string {
+	spans := wordSpans(text)
+	if len(spans) <= size {
+		return highlightTerms(text, terms)
+	}
+
+	first := 0
+	for i, span := range spans {
+		if wordMatches(text[span[0]:span[1]], terms) {
+			first = i
+			break
+		}
+	}
+	if first+size > len(spans) {
+		first = len(spans) - size
+	}
+	window := spans[first : first+size]
+
+	start, end := window[0][0], window[size-1][1]
+	if first+size == len(spans) {
+		end = len(text)
+	}
+	shifted := make([][2]int, len(window))
+	for i, span := range window {
+		shifted[i] = [2]int{span[0] - start, span[1] - start}
+	}
+
+	snippet := markSpans(text[start:end], shifted, terms)
+	if first > 0 {
+		snippet = "…" + snippet
+	}
+	if first+size < len(spans) {
+		snippet += "…"
+	}
+	return snippet
+}
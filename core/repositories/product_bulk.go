@@ -0,0 +1,137 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"product-management-app/core/dto"
+)
+
+// errBulkItemsFailed rolls back a bulk operation in which an item failed.
+var errBulkItemsFailed = errors.New("bulk operation has failed items")
+
+// BulkApply applies one operation to every selected product inside a single
+// transaction. Each product gets an item in the result; if any item fails the
+// transaction is rolled back and the result is returned with Committed false.
+func (r *ProductRepository) BulkApply(ctx context.Context, op dto.BulkOperationDTO) (*dto.BulkOperationResult, error) {
+	return ApplyBulkOperation(ctx, r, op)
+}
+
+// ApplyBulkOperation implements BulkApply on top of the other ProductStore
+// methods, so every store, and every wrapper around one, applies bulk
+// operations the same way.
+func ApplyBulkOperation(ctx context.Context, store ProductStore, op dto.BulkOperationDTO) (*dto.BulkOperationResult, error) {
+	if err := op.Validate(); err != nil {
+		return nil, err
+	}
+
+	var result *dto.BulkOperationResult
+	err := store.WithTx(ctx, func(tx ProductStore) error {
+		ids := op.ProductIDs
+		if len(ids) == 0 {
+			var err error
+			ids, err = selectProductIDs(ctx, tx, dto.PaginationDTO{Search: op.Search, Filters: op.Filters})
+			if err != nil {
+				return err
+			}
+		}
+
+		result = &dto.BulkOperationResult{
+			Operation: op.Operation,
+			Items:     make([]dto.BulkItemResult, 0, len(ids)),
+		}
+		seen := make(map[int]bool, len(ids))
+		for _, id := range ids {
+			if seen[id] {
+				continue
+			}
+			seen[id] = true
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+
+			item := dto.BulkItemResult{ID: id}
+			if err := applyBulkItem(ctx, tx, op, &item); err != nil {
+				item.Error = err.Error()
+				result.ErrorCount++
+			} else {
+				item.Success = true
+				result.SuccessCount++
+			}
+			result.Items = append(result.Items, item)
+		}
+
+		if result.ErrorCount > 0 {
+			return errBulkItemsFailed
+		}
+		return nil
+	})
+	if errors.Is(err, errBulkItemsFailed) {
+		return result, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	result.Committed = true
+	return result, nil
+}
+
+// selectProductIDs returns the IDs of every live product matching the search
+// term and filters in params, in ID order.
+func selectProductIDs(ctx context.Context, store ProductStore, params dto.PaginationDTO) ([]int, error) {
+	params.Page, params.PageSize = 1, dto.MaxPageSize
+	var ids []int
+	for {
+		page, err := store.GetAll(ctx, params)
+		if err != nil {
+			return nil, fmt.Errorf("failed to select products: %w", err)
+		}
+		for _, product := range page.Products {
+			ids = append(ids, product.ID)
+		}
+		if params.Page >= page.TotalPages {
+			return ids, nil
+		}
+		params.Page++
+	}
+}
+
+// applyBulkItem applies op to a single product within the transaction and
+// records the updated product on item.
+func applyBulkItem(ctx context.Context, tx ProductStore, op dto.BulkOperationDTO, item *dto.BulkItemResult) error {
+	product, err := tx.GetByID(ctx, item.ID)
+	if err != nil {
+		return err
+	}
+
+	update := dto.UpdateProductDTO{Version: product.Version}
+	switch op.Operation {
+	case dto.BulkDelete:
+		return tx.Delete(ctx, item.ID, product.Version)
+	case dto.BulkSetCategory:
+		if op.Category == "" {
+			update.Clear = []string{dto.FieldCategory}
+		} else {
+			update.Category = &op.Category
+		}
+	case dto.BulkAdjustStock:
+		stock := product.Stock + op.StockDelta
+		if stock < 0 {
+			return fmt.Errorf("stock would become negative (%d)", stock)
+		}
+		update.Stock = &stock
+	case dto.BulkSetPrice, dto.BulkAdjustPrice:
+		price := op.Price
+		if op.Operation == dto.BulkAdjustPrice {
+			price = op.PriceAdjustment.Apply(product.Price)
+		}
+		if price < 0 {
+			return fmt.Errorf("price would become negative (%s)", price)
+		}
+		update.Price = &price
+	}
+
+	item.Product, err = tx.Update(ctx, item.ID, update)
+	return err
+}
@@ -0,0 +1,162 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"product-management-app/core/dto"
+	apperrors "product-management-app/core/errors"
+	"product-management-app/core/money"
+)
+
+// mergeCandidatesSQL selects the archived products the merge would insert or
+// update, so they can be validated first.
+const mergeCandidatesSQL = `
+SELECT a.id, a.name, COALESCE(a.sku, ''), a.price_cents, COALESCE(a.category, ''), a.stock,
+	COALESCE(a.description, ''), COALESCE(a.image_url, '')
+FROM archive.products AS a
+LEFT JOIN products AS p ON p.id = a.id
+WHERE a.deleted_at IS NULL AND (p.id IS NULL OR (p.deleted_at IS NULL
+	AND datetime(COALESCE(a.updated_at, a.created_at)) > datetime(COALESCE(p.updated_at, p.created_at))))`
+
+// mergeUpdateSQL copies products the archive has a newer copy of. Products
+// trashed on either side are left alone, and so are products whose archived
+// SKU belongs to another product here or that failed validation.
+const mergeUpdateSQL = `
+UPDATE OR IGNORE products SET
+	name = a.name, sku = a.sku, price_cents = a.price_cents, category = a.category, stock = a.stock,
+	description = a.description, image_url = a.image_url,
+	updated_at = a.updated_at, version = products.version + 1
+FROM archive.products AS a
+WHERE a.id = products.id
+	AND a.deleted_at IS NULL AND products.deleted_at IS NULL
+	AND datetime(COALESCE(a.updated_at, a.created_at)) > datetime(COALESCE(products.updated_at, products.created_at))
+	AND a.id NOT IN (SELECT id FROM temp.merge_rejected)`
+
+// mergeInsertSQL adds the archive's valid products whose ID and SKU are not
+// in use, keeping their IDs and timestamps.
+const mergeInsertSQL = `
+INSERT OR IGNORE INTO products (id, name, sku, price_cents, category, stock, description, image_url, created_at, updated_at, version)
+SELECT id, name, sku, price_cents, category, stock, description, image_url, created_at, updated_at, 1
+FROM archive.products AS a
+WHERE a.deleted_at IS NULL AND NOT EXISTS (SELECT 1 FROM products WHERE products.id = a.id)
+	AND a.id NOT IN (SELECT id FROM temp.merge_rejected)`
+
+// MergeFrom merges the products of the database file at path, which must be
+// at the current schema version, in a single transaction. Products missing
+// here are inserted with their IDs, products the file has a newer copy of
+// are updated, and the rest are skipped. Every product it would insert or
+// update must pass validate; those failing validation are left out and
+// listed in the result.
+func (r *ProductRepository) MergeFrom(ctx context.Context, path string, validate func(dto.CreateProductDTO) error) (*dto.MergeResult, error) {
+	// ATTACH is per connection and not allowed inside a transaction, so pin
+	// one connection for the whole merge.
+	conn, err := r.db.Conn(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get database connection: %w", err)
+	}
+	defer func() {
+		_ = conn.Close()
+	}()
+
+	if _, err := conn.ExecContext(ctx, "ATTACH DATABASE ? AS archive", path); err != nil {
+		return nil, fmt.Errorf("failed to attach archive: %w", err)
+	}
+	defer func() {
+		// Detach even when ctx is done, or the pooled connection would keep
+		// the archive attached.
+		_, _ = conn.ExecContext(context.WithoutCancel(ctx), "DETACH DATABASE archive")
+	}()
+	if _, err := conn.ExecContext(ctx, "CREATE TEMP TABLE merge_rejected (id INTEGER PRIMARY KEY)"); err != nil {
+		return nil, fmt.Errorf("failed to prepare merge: %w", err)
+	}
+	defer func() {
+		_, _ = conn.ExecContext(context.WithoutCancel(ctx), "DROP TABLE IF EXISTS temp.merge_rejected")
+	}()
+
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	var total int
+	if err := tx.QueryRowContext(ctx, "SELECT COUNT(*) FROM archive.products WHERE deleted_at IS NULL").Scan(&total); err != nil {
+		return nil, fmt.Errorf("failed to count archive products: %w", err)
+	}
+
+	result := &dto.MergeResult{}
+	if result.Rejected, err = rejectInvalidArchived(ctx, tx, validate); err != nil {
+		return nil, err
+	}
+	res, err := tx.ExecContext(ctx, mergeUpdateSQL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update products from archive: %w", err)
+	}
+	updated, err := res.RowsAffected()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	res, err = tx.ExecContext(ctx, mergeInsertSQL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to insert products from archive: %w", err)
+	}
+	inserted, err := res.RowsAffected()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit merge: %w", err)
+	}
+	result.Updated = int(updated)
+	result.Inserted = int(inserted)
+	result.Skipped = total - result.Updated - result.Inserted - len(result.Rejected)
+	return result, nil
+}
+
+// rejectInvalidArchived validates the archived products the merge would
+// write and records the IDs of the invalid ones in temp.merge_rejected.
+func rejectInvalidArchived(ctx context.Context, tx querier, validate func(dto.CreateProductDTO) error) ([]dto.MergeRejection, error) {
+	rows, err := tx.QueryContext(ctx, mergeCandidatesSQL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read archive products: %w", err)
+	}
+	var rejected []dto.MergeRejection
+	for rows.Next() {
+		var id int
+		var cents int64
+		var product dto.CreateProductDTO
+		if err := rows.Scan(&id, &product.Name, &product.SKU, &cents, &product.Category, &product.Stock, &product.Description, &product.ImageURL); err != nil {
+			_ = rows.Close()
+			return nil, fmt.Errorf("failed to scan archive product: %w", err)
+		}
+		product.Price = money.FromMinorUnits(cents)
+
+		var validation *apperrors.ValidationError
+		switch err := validate(product); {
+		case err == nil:
+		case errors.As(err, &validation):
+			rejected = append(rejected, dto.MergeRejection{ProductID: id, Name: product.Name, Errors: validation.Fields})
+		default:
+			_ = rows.Close()
+			return nil, fmt.Errorf("failed to validate archive product %d: %w", id, err)
+		}
+	}
+	if err := rows.Close(); err != nil {
+		return nil, fmt.Errorf("failed to read archive products: %w", err)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate archive products: %w", err)
+	}
+
+	for _, rejection := range rejected {
+		if _, err := tx.ExecContext(ctx, "INSERT INTO temp.merge_rejected (id) VALUES (?)", rejection.ProductID); err != nil {
+			return nil, fmt.Errorf("failed to record rejected product %d: %w", rejection.ProductID, err)
+		}
+	}
+	return rejected, nil
+}
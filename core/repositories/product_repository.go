@@ -5,35 +5,90 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"log/slog"
+	"strings"
 
 	"product-management-app/core/dto"
+	apperrors "product-management-app/core/errors"
 	"product-management-app/core/models"
-
-	_ "github.com/mattn/go-sqlite3" // SQLite driver
-	"github.com/wailsapp/wails/v2/pkg/runtime"
 )
 
-// ProductRepository handles database operations for products.
+// ProductRepository handles database operations for products. It is the
+// SQLite implementation of ProductStore. Every query runs under the context
+// passed to the method, so callers can cancel it or give it a deadline.
 type ProductRepository struct {
-	db  *sql.DB
-	ctx context.Context
+	db *sql.DB
+	// q runs the queries: db itself, or the transaction of a repository
+	// handed out by WithTx.
+	q querier
+	// tx is set on repositories handed out by WithTx.
+	tx     *sql.Tx
+	logger *slog.Logger
+}
+
+// querier is satisfied by both *sql.DB and *sql.Tx.
+type querier interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
+var _ ProductStore = (*ProductRepository)(nil)
+
+// NewProductRepository creates a new ProductRepository instance. logger
+// receives the cleanup failures that cannot be returned to the caller.
+func NewProductRepository(db *sql.DB, logger *slog.Logger) *ProductRepository {
+	return &ProductRepository{db: db, q: db, logger: logger}
+}
+
+// WithTx runs fn with a repository bound to a new transaction, committing it
+// if fn returns nil and rolling it back otherwise. The transaction is also
+// rolled back if ctx is done before it commits. On a repository that is
+// already bound to a transaction, fn joins it.
+func (r *ProductRepository) WithTx(ctx context.Context, fn func(tx ProductStore) error) error {
+	return r.inTx(ctx, func(tx *ProductRepository) error { return fn(tx) })
 }
 
-// NewProductRepository creates a new ProductRepository instance.
-func NewProductRepository(ctx context.Context, db *sql.DB) *ProductRepository {
-	return &ProductRepository{db: db, ctx: ctx}
+// inTx is WithTx for callers in this package that need the repository type.
+func (r *ProductRepository) inTx(ctx context.Context, fn func(tx *ProductRepository) error) error {
+	if r.tx != nil {
+		return fn(r)
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		if err := tx.Rollback(); err != nil && err != sql.ErrTxDone {
+			r.logger.ErrorContext(ctx, "Failed to roll back transaction", "error", err)
+		}
+	}()
+
+	if err := fn(&ProductRepository{db: r.db, q: tx, tx: tx, logger: r.logger}); err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
 }
 
 // Create creates a new product in the database.
-func (r *ProductRepository) Create(createProductDTO dto.CreateProductDTO) (*models.Product, error) {
-	res, err := r.db.Exec("INSERT INTO products(name, price, category, stock, description, image_url) VALUES(?, ?, ?, ?, ?, ?)", createProductDTO.Name, createProductDTO.Price, createProductDTO.Category, createProductDTO.Stock, createProductDTO.Description, createProductDTO.ImageURL)
+func (r *ProductRepository) Create(ctx context.Context, createProductDTO dto.CreateProductDTO) (*models.Product, error) {
+	if err := r.checkSKUFree(ctx, createProductDTO.SKU, 0); err != nil {
+		return nil, err
+	}
+	res, err := r.q.ExecContext(ctx, "INSERT INTO products(name, sku, price_cents, category, stock, description, image_url) VALUES(?, ?, ?, ?, ?, ?, ?)", createProductDTO.Name, nullIfEmpty(createProductDTO.SKU), createProductDTO.Price, nullIfEmpty(createProductDTO.Category), createProductDTO.Stock, nullIfEmpty(createProductDTO.Description), nullIfEmpty(createProductDTO.ImageURL))
 	if err != nil {
-		runtime.LogError(r.ctx, fmt.Sprintf("Failed to create product: %v", err))
 		return nil, fmt.Errorf("failed to create product: %w", err)
 	}
 	id, _ := res.LastInsertId()
 
-	var category, description, imageURL *string
+	var sku, category, description, imageURL *string
+	if createProductDTO.SKU != "" {
+		sku = &createProductDTO.SKU
+	}
 	if createProductDTO.Category != "" {
 		category = &createProductDTO.Category
 	}
@@ -47,44 +102,52 @@ func (r *ProductRepository) Create(createProductDTO dto.CreateProductDTO) (*mode
 	product := &models.Product{
 		ID:          int(id),
 		Name:        createProductDTO.Name,
+		SKU:         sku,
 		Price:       createProductDTO.Price,
 		Category:    category,
 		Stock:       createProductDTO.Stock,
 		Description: description,
 		ImageURL:    imageURL,
+		Version:     1,
 	}
-	runtime.LogInfo(r.ctx, fmt.Sprintf("Product created: %+v", product))
 	return product, nil
 }
 
-// GetByID retrieves a product by its ID.
-func (r *ProductRepository) GetByID(id int) (*models.Product, error) {
-	var category, description, imageURL, updatedAt sql.NullString
-	var createdAt string
+// productColumns lists the products columns in the order scanProduct expects.
+const productColumns = "id, name, sku, price_cents, category, stock, description, image_url, created_at, updated_at, version, deleted_at"
 
-	row := r.db.QueryRow("SELECT id, name, price, category, stock, description, image_url, created_at, updated_at FROM products WHERE id = ?", id)
+// rowScanner is satisfied by both *sql.Row and *sql.Rows.
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
 
+// scanProduct reads the productColumns of the current row into a Product.
+// Any extra destinations are scanned from the columns that follow.
+func scanProduct(row rowScanner, extra ...interface{}) (*models.Product, error) {
+	var sku, category, description, imageURL, updatedAt, deletedAt sql.NullString
 	product := &models.Product{}
-	err := row.Scan(
+
+	dest := []interface{}{
 		&product.ID,
 		&product.Name,
+		&sku,
 		&product.Price,
 		&category,
 		&product.Stock,
 		&description,
 		&imageURL,
-		&createdAt,
+		&product.CreatedAt,
 		&updatedAt,
-	)
-
-	if err != nil {
-		if err == sql.ErrNoRows {
-			return nil, fmt.Errorf("product with ID %d not found", id)
-		}
-		return nil, fmt.Errorf("failed to fetch product: %w", err)
+		&product.Version,
+		&deletedAt,
+	}
+	if err := row.Scan(append(dest, extra...)...); err != nil {
+		return nil, err
 	}
 
-	// Convertemos os NullString para ponteiros de string
+	if sku.Valid {
+		product.SKU = &sku.String
+	}
 	if category.Valid {
 		product.Category = &category.String
 	}
@@ -94,67 +157,209 @@ func (r *ProductRepository) GetByID(id int) (*models.Product, error) {
 	if imageURL.Valid {
 		product.ImageURL = &imageURL.String
 	}
-
-	product.CreatedAt = createdAt
-
 	if updatedAt.Valid {
 		product.UpdatedAt = &updatedAt.String
 	}
+	if deletedAt.Valid {
+		product.DeletedAt = &deletedAt.String
+	}
+
+	return product, nil
+}
+
+// GetByID retrieves a product by its ID. Products in the trash are reported
+// as not found.
+func (r *ProductRepository) GetByID(ctx context.Context, id int) (*models.Product, error) {
+	row := r.q.QueryRowContext(ctx, "SELECT "+productColumns+" FROM products WHERE id = ? AND deleted_at IS NULL", id)
+
+	product, err := scanProduct(row)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, apperrors.NotFound("product", id)
+		}
+		return nil, fmt.Errorf("failed to fetch product: %w", err)
+	}
 
 	return product, nil
 }
 
-// GetAll retrieves all products with pagination.
-func (r *ProductRepository) GetAll(params dto.PaginationDTO) (*dto.PaginationResponse, error) {
+// productSortColumns whitelists the columns GetAll may order by, keyed by the
+// value accepted in PaginationDTO.SortBy.
+var productSortColumns = map[string]string{
+	"id":         "id",
+	"name":       "name",
+	"price":      "price_cents",
+	"stock":      "stock",
+	"category":   "category",
+	"created_at": "created_at",
+	"createdAt":  "created_at",
+	"updated_at": "updated_at",
+	"updatedAt":  "updated_at",
+	"deleted_at": "deleted_at",
+	"deletedAt":  "deleted_at",
+}
+
+// buildOrderClause returns the ORDER BY clause for the requested sort, falling
+// back to id ascending when the column is unknown.
+func buildOrderClause(sortBy, order string) string {
+	column, ok := productSortColumns[sortBy]
+	if !ok {
+		column = "id"
+	}
+	direction := "ASC"
+	if strings.EqualFold(order, "desc") {
+		direction = "DESC"
+	}
+	if column == "id" {
+		return fmt.Sprintf(" ORDER BY id %s", direction)
+	}
+	return fmt.Sprintf(" ORDER BY %s %s, id %s", column, direction, direction)
+}
+
+// buildWhereClause returns the WHERE clause and its arguments for the
+// search term and filters in params, restricted to either live or trashed
+// products.
+func buildWhereClause(params dto.PaginationDTO, trashed bool) (string, []interface{}) {
+	conditions := []string{"deleted_at IS NULL"}
+	if trashed {
+		conditions[0] = "deleted_at IS NOT NULL"
+	}
+	var args []interface{}
+
+	if search := strings.TrimSpace(params.Search); search != "" {
+		pattern := "%" + escapeLike(search) + "%"
+		conditions = append(conditions, `(name LIKE ? ESCAPE '\' OR category LIKE ? ESCAPE '\' OR description LIKE ? ESCAPE '\')`)
+		args = append(args, pattern, pattern, pattern)
+	}
+
+	if params.Filters != nil {
+		filterConditions, filterArgs := buildFilterConditions(params.Filters)
+		conditions = append(conditions, filterConditions...)
+		args = append(args, filterArgs...)
+	}
+
+	return " WHERE " + strings.Join(conditions, " AND "), args
+}
+
+// buildFilterConditions translates a validated filter into parameterized SQL
+// conditions.
+func buildFilterConditions(filter *dto.ProductFilterDTO) ([]string, []interface{}) {
+	var conditions []string
+	var args []interface{}
+
+	if filter.MinPrice != nil {
+		conditions = append(conditions, "price_cents >= ?")
+		args = append(args, *filter.MinPrice)
+	}
+	if filter.MaxPrice != nil {
+		conditions = append(conditions, "price_cents <= ?")
+		args = append(args, *filter.MaxPrice)
+	}
+	if filter.MinStock != nil {
+		conditions = append(conditions, "stock >= ?")
+		args = append(args, *filter.MinStock)
+	}
+	if filter.MaxStock != nil {
+		conditions = append(conditions, "stock <= ?")
+		args = append(args, *filter.MaxStock)
+	}
+
+	if len(filter.Categories) > 0 {
+		placeholders := make([]string, len(filter.Categories))
+		for i, category := range filter.Categories {
+			placeholders[i] = "?"
+			args = append(args, category)
+		}
+		conditions = append(conditions, "category IN ("+strings.Join(placeholders, ", ")+")")
+	}
+
+	if filter.HasImage != nil {
+		conditions = append(conditions, presenceCondition("image_url", *filter.HasImage))
+	}
+	if filter.HasDescription != nil {
+		conditions = append(conditions, presenceCondition("description", *filter.HasDescription))
+	}
+
+	dateBounds := []struct {
+		column     string
+		operator   string
+		value      string
+		upperBound bool
+	}{
+		{"created_at", ">=", filter.CreatedFrom, false},
+		{"created_at", "<=", filter.CreatedTo, true},
+		{"updated_at", ">=", filter.UpdatedFrom, false},
+		{"updated_at", "<=", filter.UpdatedTo, true},
+	}
+	for _, bound := range dateBounds {
+		// Validate has already rejected malformed dates.
+		value, _ := dto.ParseFilterDate(bound.value, bound.upperBound)
+		if value == "" {
+			continue
+		}
+		conditions = append(conditions, fmt.Sprintf("datetime(%s) %s datetime(?)", bound.column, bound.operator))
+		args = append(args, value)
+	}
+
+	return conditions, args
+}
+
+// presenceCondition matches rows where column holds a non-empty value, or the
+// opposite when present is false.
+func presenceCondition(column string, present bool) string {
+	if present {
+		return fmt.Sprintf("(%s IS NOT NULL AND %s <> '')", column, column)
+	}
+	return fmt.Sprintf("(%s IS NULL OR %s = '')", column, column)
+}
+
+// escapeLike escapes the LIKE wildcards in value so it is matched literally.
+func escapeLike(value string) string {
+	replacer := strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
+	return replacer.Replace(value)
+}
+
+// GetAll retrieves products with pagination, applying the search term,
+// filters and sort order from params. Products in the trash are excluded.
+func (r *ProductRepository) GetAll(ctx context.Context, params dto.PaginationDTO) (*dto.PaginationResponse, error) {
+	return r.list(ctx, params, false)
+}
+
+// list returns a page of live or trashed products matching params.
+func (r *ProductRepository) list(ctx context.Context, params dto.PaginationDTO, trashed bool) (*dto.PaginationResponse, error) {
+	if err := params.Validate(); err != nil {
+		return nil, err
+	}
+
+	whereClause, args := buildWhereClause(params, trashed)
 	offset := (params.Page - 1) * params.PageSize
-	rows, err := r.db.Query("SELECT id, name, price, category, stock, description, image_url, created_at, updated_at FROM products LIMIT ? OFFSET ?", params.PageSize, offset)
+
+	query := "SELECT " + productColumns + " FROM products" + whereClause +
+		buildOrderClause(params.SortBy, params.Order) + " LIMIT ? OFFSET ?"
+	rows, err := r.q.QueryContext(ctx, query, append(args, params.PageSize, offset)...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch products: %w", err)
 	}
 	defer func() {
 		if err := rows.Close(); err != nil {
-			runtime.LogError(r.ctx, fmt.Sprintf("Failed to close rows: %v", err))
+			r.logger.ErrorContext(ctx, "Failed to close rows", "error", err)
 		}
 	}()
 
 	products := []*models.Product{}
 	for rows.Next() {
-		var category, description, imageURL, updatedAt sql.NullString
-		var createdAt string
-		var id, stock int
-		var name string
-		var price float64
-
-		if err := rows.Scan(&id, &name, &price, &category, &stock, &description, &imageURL, &createdAt, &updatedAt); err != nil {
+		product, err := scanProduct(rows)
+		if err != nil {
 			return nil, fmt.Errorf("failed to scan product: %w", err)
 		}
-
-		product := &models.Product{
-			ID:        id,
-			Name:      name,
-			Price:     price,
-			Stock:     stock,
-			CreatedAt: createdAt,
-		}
-
-		if category.Valid {
-			product.Category = &category.String
-		}
-		if description.Valid {
-			product.Description = &description.String
-		}
-		if imageURL.Valid {
-			product.ImageURL = &imageURL.String
-		}
-		if updatedAt.Valid {
-			product.UpdatedAt = &updatedAt.String
-		}
-
 		products = append(products, product)
 	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate products: %w", err)
+	}
 
 	totalCount := 0
-	err = r.db.QueryRow("SELECT COUNT(*) FROM products").Scan(&totalCount)
+	err = r.q.QueryRowContext(ctx, "SELECT COUNT(*) FROM products"+whereClause, args...).Scan(&totalCount)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get total count: %w", err)
 	}
@@ -169,14 +374,58 @@ func (r *ProductRepository) GetAll(params dto.PaginationDTO) (*dto.PaginationRes
 	}, nil
 }
 
-// Update updates an existing product.
-func (r *ProductRepository) Update(id int, name string, price float64) (*models.Product, error) {
-	currentProduct, err := r.GetByID(id)
-	if err != nil {
+// Update applies a partial update to an existing product and returns the
+// product as stored afterwards.
+func (r *ProductRepository) Update(ctx context.Context, id int, update dto.UpdateProductDTO) (*models.Product, error) {
+	if err := update.Validate(); err != nil {
 		return nil, err
 	}
 
-	res, err := r.db.Exec("UPDATE products SET name = ?, price = ?, updated_at = CURRENT_TIMESTAMP WHERE id = ?", name, price, id)
+	var assignments []string
+	var args []interface{}
+
+	set := func(column string, value interface{}) {
+		assignments = append(assignments, column+" = ?")
+		args = append(args, value)
+	}
+	if update.Name != nil {
+		set("name", *update.Name)
+	}
+	if update.SKU != nil {
+		if err := r.checkSKUFree(ctx, *update.SKU, id); err != nil {
+			return nil, err
+		}
+		set("sku", nullIfEmpty(*update.SKU))
+	}
+	if update.Price != nil {
+		set("price_cents", *update.Price)
+	}
+	if update.Category != nil {
+		set("category", nullIfEmpty(*update.Category))
+	}
+	if update.Stock != nil {
+		set("stock", *update.Stock)
+	}
+	if update.Description != nil {
+		set("description", nullIfEmpty(*update.Description))
+	}
+	if update.ImageURL != nil {
+		set("image_url", nullIfEmpty(*update.ImageURL))
+	}
+
+	clearColumns := map[string]string{
+		dto.FieldSKU:         "sku",
+		dto.FieldCategory:    "category",
+		dto.FieldDescription: "description",
+		dto.FieldImageURL:    "image_url",
+	}
+	for _, field := range update.Clear {
+		assignments = append(assignments, clearColumns[field]+" = NULL")
+	}
+	assignments = append(assignments, "updated_at = CURRENT_TIMESTAMP", "version = version + 1")
+
+	query := "UPDATE products SET " + strings.Join(assignments, ", ") + " WHERE id = ? AND version = ? AND deleted_at IS NULL"
+	res, err := r.q.ExecContext(ctx, query, append(args, id, update.Version)...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to update product: %w", err)
 	}
@@ -187,24 +436,102 @@ func (r *ProductRepository) Update(id int, name string, price float64) (*models.
 	}
 
 	if rowsAffected == 0 {
-		return nil, fmt.Errorf("product with ID %d not found", id)
+		return nil, r.missedWriteError(ctx, id, update.Version, false)
 	}
 
-	currentProduct.Name = name
-	currentProduct.Price = price
+	return r.GetByID(ctx, id)
+}
+
+// GetBySKU retrieves the live product with the given SKU.
+func (r *ProductRepository) GetBySKU(ctx context.Context, sku string) (*models.Product, error) {
+	row := r.q.QueryRowContext(ctx, "SELECT "+productColumns+" FROM products WHERE sku = ? AND deleted_at IS NULL", sku)
+
+	product, err := scanProduct(row)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, apperrors.NotFound("product with SKU", sku)
+		}
+		return nil, fmt.Errorf("failed to fetch product: %w", err)
+	}
+	return product, nil
+}
+
+// FindByName returns the live products named exactly name, oldest first.
+func (r *ProductRepository) FindByName(ctx context.Context, name string) ([]*models.Product, error) {
+	rows, err := r.q.QueryContext(ctx, "SELECT "+productColumns+" FROM products WHERE name = ? AND deleted_at IS NULL ORDER BY id", name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch products: %w", err)
+	}
+	defer func() {
+		if err := rows.Close(); err != nil {
+			r.logger.ErrorContext(ctx, "Failed to close rows", "error", err)
+		}
+	}()
+
+	products := []*models.Product{}
+	for rows.Next() {
+		product, err := scanProduct(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan product: %w", err)
+		}
+		products = append(products, product)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate products: %w", err)
+	}
+	return products, nil
+}
 
-	var updatedAt string
-	err = r.db.QueryRow("SELECT updated_at FROM products WHERE id = ?", id).Scan(&updatedAt)
-	if err == nil {
-		currentProduct.UpdatedAt = &updatedAt
+// checkSKUFree reports a conflict if a product other than exceptID, live or
+// in the trash, already has sku. An empty SKU is never taken.
+func (r *ProductRepository) checkSKUFree(ctx context.Context, sku string, exceptID int) error {
+	if sku == "" {
+		return nil
 	}
+	var ownerID int
+	err := r.q.QueryRowContext(ctx, "SELECT id FROM products WHERE sku = ? AND id != ?", sku, exceptID).Scan(&ownerID)
+	if err == sql.ErrNoRows {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to check SKU: %w", err)
+	}
+	return apperrors.Conflict("SKU %q is already used by product %d", sku, ownerID)
+}
+
+// nullIfEmpty stores an empty string as NULL, so optional unique columns
+// can be left blank by any number of products.
+func nullIfEmpty(value string) interface{} {
+	if value == "" {
+		return nil
+	}
+	return value
+}
 
-	return currentProduct, nil
+// missedWriteError explains why a versioned write matched no rows: the
+// product does not exist, is on the wrong side of the trash, or its version
+// has moved on.
+func (r *ProductRepository) missedWriteError(ctx context.Context, id, expectedVersion int, expectTrashed bool) error {
+	var currentVersion int
+	var trashed bool
+	err := r.q.QueryRowContext(ctx, "SELECT version, deleted_at IS NOT NULL FROM products WHERE id = ?", id).Scan(&currentVersion, &trashed)
+	if err == sql.ErrNoRows || (err == nil && trashed && !expectTrashed) {
+		return apperrors.NotFound("product", id)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to check product version: %w", err)
+	}
+	if !trashed && expectTrashed {
+		return apperrors.NotFound("trashed product", id)
+	}
+	return apperrors.VersionConflict(id, expectedVersion, currentVersion)
 }
 
-// Delete removes a product from the database.
-func (r *ProductRepository) Delete(id int) error {
-	res, err := r.db.Exec("DELETE FROM products WHERE id = ?", id)
+// Delete moves a product to the trash. The delete only succeeds when version
+// matches the stored version; the product can be brought back with Restore
+// until it is purged.
+func (r *ProductRepository) Delete(ctx context.Context, id, version int) error {
+	res, err := r.q.ExecContext(ctx, "UPDATE products SET deleted_at = CURRENT_TIMESTAMP, version = version + 1 WHERE id = ? AND version = ? AND deleted_at IS NULL", id, version)
 	if err != nil {
 		return fmt.Errorf("failed to delete product: %w", err)
 	}
@@ -213,7 +540,7 @@ func (r *ProductRepository) Delete(id int) error {
 		return fmt.Errorf("failed to get rows affected: %w", err)
 	}
 	if rowsAffected == 0 {
-		return fmt.Errorf("product with ID %d not found", id)
+		return r.missedWriteError(ctx, id, version, false)
 	}
 	return nil
 }
@@ -0,0 +1,148 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"strings"
+	"unicode"
+
+	"product-management-app/core/dto"
+)
+
+// FullTextSearchSchema creates the products_fts index Search queries. It
+// mirrors products.name, description and category into an external-content
+// FTS5 table kept in sync by triggers.
+var FullTextSearchSchema = []string{
+	`CREATE VIRTUAL TABLE IF NOT EXISTS products_fts USING fts5(
+		name, description, category,
+		content='products', content_rowid='id',
+		tokenize='unicode61 remove_diacritics 2'
+	)`,
+	`CREATE TRIGGER IF NOT EXISTS products_fts_ai AFTER INSERT ON products BEGIN
+		INSERT INTO products_fts(rowid, name, description, category)
+		VALUES (new.id, new.name, new.description, new.category);
+	END`,
+	`CREATE TRIGGER IF NOT EXISTS products_fts_ad AFTER DELETE ON products BEGIN
+		INSERT INTO products_fts(products_fts, rowid, name, description, category)
+		VALUES ('delete', old.id, old.name, old.description, old.category);
+	END`,
+	`CREATE TRIGGER IF NOT EXISTS products_fts_au AFTER UPDATE OF name, description, category ON products BEGIN
+		INSERT INTO products_fts(products_fts, rowid, name, description, category)
+		VALUES ('delete', old.id, old.name, old.description, old.category);
+		INSERT INTO products_fts(rowid, name, description, category)
+		VALUES (new.id, new.name, new.description, new.category);
+	END`,
+}
+
+// highlightOpen and highlightClose mark matched terms while highlights are
+// built. Product text could contain <mark> itself, so matches are marked
+// with control characters instead, the text is escaped as HTML, and only
+// then are the sentinels turned into tags.
+const (
+	highlightOpen  = "\x02"
+	highlightClose = "\x03"
+)
+
+var highlightTags = strings.NewReplacer(highlightOpen, "<mark>", highlightClose, "</mark>")
+
+// renderHighlight escapes text marked with the highlight sentinels as HTML
+// and turns the sentinels into <mark> tags.
+func renderHighlight(text string) string {
+	return highlightTags.Replace(html.EscapeString(text))
+}
+
+// buildMatchExpression turns free text into an FTS5 MATCH expression. Each
+// word becomes a quoted prefix term, so "blue sho" matches "Blue Shoes", and
+// the terms are combined with an implicit AND. FTS5 operators typed by the
+// user are treated as plain words.
+func buildMatchExpression(query string) string {
+	words := strings.FieldsFunc(query, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	})
+
+	terms := make([]string, 0, len(words))
+	for _, word := range words {
+		terms = append(terms, `"`+word+`"*`)
+	}
+	return strings.Join(terms, " ")
+}
+
+// Search runs a ranked full-text search over product names, descriptions and
+// categories. Results are ordered by bm25 relevance, with name matches
+// weighted above category and description matches.
+func (r *ProductRepository) Search(ctx context.Context, params dto.ProductSearchDTO) (*dto.ProductSearchResponse, error) {
+	if err := params.Validate(); err != nil {
+		return nil, err
+	}
+
+	match := buildMatchExpression(params.Query)
+	response := &dto.ProductSearchResponse{
+		Results:  []*dto.ProductSearchResult{},
+		Page:     params.Page,
+		PageSize: params.PageSize,
+	}
+	if match == "" {
+		return response, nil
+	}
+
+	offset := (params.Page - 1) * params.PageSize
+	query := `
+	SELECT ` + qualifiedProductColumns("p") + `,
+		-bm25(products_fts, 10.0, 1.0, 5.0) AS score,
+		highlight(products_fts, 0, char(2), char(3)),
+		COALESCE(highlight(products_fts, 2, char(2), char(3)), ''),
+		COALESCE(snippet(products_fts, 1, char(2), char(3), '…', 16), '')
+	FROM products_fts
+	JOIN products p ON p.id = products_fts.rowid
+	WHERE products_fts MATCH ? AND p.deleted_at IS NULL
+	ORDER BY score DESC, p.id
+	LIMIT ? OFFSET ?`
+
+	rows, err := r.q.QueryContext(ctx, query, match, params.PageSize, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search products: %w", err)
+	}
+	defer func() {
+		if err := rows.Close(); err != nil {
+			r.logger.ErrorContext(ctx, "Failed to close rows", "error", err)
+		}
+	}()
+
+	for rows.Next() {
+		result := &dto.ProductSearchResult{}
+		product, err := scanProduct(rows, &result.Rank, &result.NameHighlight, &result.CategoryHighlight, &result.DescriptionSnippet)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan search result: %w", err)
+		}
+		result.Product = product
+		result.NameHighlight = renderHighlight(result.NameHighlight)
+		result.CategoryHighlight = renderHighlight(result.CategoryHighlight)
+		result.DescriptionSnippet = renderHighlight(result.DescriptionSnippet)
+		response.Results = append(response.Results, result)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate search results: %w", err)
+	}
+
+	err = r.q.QueryRowContext(ctx, `
+	SELECT COUNT(*)
+	FROM products_fts
+	JOIN products p ON p.id = products_fts.rowid
+	WHERE products_fts MATCH ? AND p.deleted_at IS NULL`, match).Scan(&response.TotalCount)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count search results: %w", err)
+	}
+	response.TotalPages = (response.TotalCount + params.PageSize - 1) / params.PageSize
+
+	return response, nil
+}
+
+// qualifiedProductColumns prefixes each of productColumns with alias.
+func qualifiedProductColumns(alias string) string {
+	columns := strings.Split(productColumns, ", ")
+	for i, column := range columns {
+		columns[i] = alias + "." + column
+	}
+	return strings.Join(columns, ", ")
+}
@@ -0,0 +1,72 @@
+package repositories
+
+import (
+	"context"
+
+	"product-management-app/core/dto"
+	"product-management-app/core/models"
+)
+
+// ProductStore is the product storage used by the services. ProductRepository
+// keeps products in SQLite and MemoryProductStore keeps them in memory; both
+// pass the same conformance suite in core/tests. Every method stops and
+// returns the context's error once ctx is done.
+type ProductStore interface {
+	// Create adds a product at version 1.
+	Create(ctx context.Context, createProductDTO dto.CreateProductDTO) (*models.Product, error)
+	// GetByID returns a live product. Products in the trash are not found.
+	GetByID(ctx context.Context, id int) (*models.Product, error)
+	// GetBySKU returns the live product with the given SKU.
+	GetBySKU(ctx context.Context, sku string) (*models.Product, error)
+	// FindByName returns the live products named exactly name, oldest
+	// first.
+	FindByName(ctx context.Context, name string) ([]*models.Product, error)
+	// GetAll returns a page of live products matching params.
+	GetAll(ctx context.Context, params dto.PaginationDTO) (*dto.PaginationResponse, error)
+	// Update applies a partial update if update.Version is current and
+	// returns the stored product. Create and Update refuse a SKU another
+	// product, live or trashed, already has.
+	Update(ctx context.Context, id int, update dto.UpdateProductDTO) (*models.Product, error)
+	// Delete moves a product to the trash if version is current.
+	Delete(ctx context.Context, id, version int) error
+
+	// Search returns live products matching every word of the query as a
+	// prefix, best matches first.
+	Search(ctx context.Context, params dto.ProductSearchDTO) (*dto.ProductSearchResponse, error)
+
+	// GetTrash returns a page of trashed products, most recently deleted
+	// first unless params sorts otherwise.
+	GetTrash(ctx context.Context, params dto.PaginationDTO) (*dto.PaginationResponse, error)
+	// Restore moves a trashed product back if version is current.
+	Restore(ctx context.Context, id, version int) error
+	// Purge permanently removes a trashed product.
+	Purge(ctx context.Context, id int) error
+	// PurgeOlderThan permanently removes the products trashed at least days
+	// days ago and returns how many were removed.
+	PurgeOlderThan(ctx context.Context, days int) (int, error)
+
+	// BulkApply applies op to every selected product in one transaction.
+	BulkApply(ctx context.Context, op dto.BulkOperationDTO) (*dto.BulkOperationResult, error)
+
+	// CreateImportBatch records batch and its items and returns it with its
+	// ID and creation time.
+	CreateImportBatch(ctx context.Context, batch models.ImportBatch) (*models.ImportBatch, error)
+	// AddImportBatchItems adds items to a batch. A product already in the
+	// batch keeps the state it had before the import and only takes the
+	// item's version.
+	AddImportBatchItems(ctx context.Context, id int, items []models.ImportBatchItem) error
+	// GetImportBatch returns a batch with its items.
+	GetImportBatch(ctx context.Context, id int) (*models.ImportBatch, error)
+	// ListImportBatches returns up to limit batches without their items,
+	// newest first.
+	ListImportBatches(ctx context.Context, limit int) ([]*models.ImportBatch, error)
+	// MarkImportBatchRolledBack records that a batch was rolled back. A batch
+	// can only be rolled back once.
+	MarkImportBatchRolledBack(ctx context.Context, id int) error
+
+	// WithTx runs fn against a store whose writes are committed together if
+	// fn returns nil and discarded otherwise, including when ctx is done
+	// first. Calling WithTx on the store passed to fn runs in the same
+	// transaction.
+	WithTx(ctx context.Context, fn func(tx ProductStore) error) error
+}
@@ -0,0 +1,70 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+
+	"product-management-app/core/dto"
+	apperrors "product-management-app/core/errors"
+)
+
+// GetTrash retrieves trashed products with pagination. Unless params asks
+// for another order, the most recently deleted products come first.
+func (r *ProductRepository) GetTrash(ctx context.Context, params dto.PaginationDTO) (*dto.PaginationResponse, error) {
+	if params.SortBy == "" {
+		params.SortBy = "deleted_at"
+		params.Order = "desc"
+	}
+	return r.list(ctx, params, true)
+}
+
+// Restore moves a trashed product back into the catalog. The version must
+// match the version of the trashed product.
+func (r *ProductRepository) Restore(ctx context.Context, id, version int) error {
+	res, err := r.q.ExecContext(ctx, "UPDATE products SET deleted_at = NULL, updated_at = CURRENT_TIMESTAMP, version = version + 1 WHERE id = ? AND version = ? AND deleted_at IS NOT NULL", id, version)
+	if err != nil {
+		return fmt.Errorf("failed to restore product: %w", err)
+	}
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return r.missedWriteError(ctx, id, version, true)
+	}
+	return nil
+}
+
+// Purge permanently removes a single product from the trash.
+func (r *ProductRepository) Purge(ctx context.Context, id int) error {
+	res, err := r.q.ExecContext(ctx, "DELETE FROM products WHERE id = ? AND deleted_at IS NOT NULL", id)
+	if err != nil {
+		return fmt.Errorf("failed to purge product: %w", err)
+	}
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return apperrors.NotFound("trashed product", id)
+	}
+	return nil
+}
+
+// PurgeOlderThan permanently removes every trashed product deleted at least
+// days days ago and returns how many were removed. Zero empties the trash.
+func (r *ProductRepository) PurgeOlderThan(ctx context.Context, days int) (int, error) {
+	if days < 0 {
+		return 0, fmt.Errorf("invalid retention of %d days: must not be negative", days)
+	}
+
+	res, err := r.q.ExecContext(ctx, "DELETE FROM products WHERE deleted_at IS NOT NULL AND datetime(deleted_at) <= datetime('now', ?)", fmt.Sprintf("-%d days", days))
+	if err != nil {
+		return 0, fmt.Errorf("failed to purge trash: %w", err)
+	}
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	return int(rowsAffected), nil
+}
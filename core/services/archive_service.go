@@ -0,0 +1,210 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+
+	"product-management-app/core/archive"
+	"product-management-app/core/dto"
+	apperrors "product-management-app/core/errors"
+	"product-management-app/core/migrations"
+	"product-management-app/core/sqlite"
+)
+
+// archiveTempPatterns match the plain databases ExportArchive and
+// OpenArchive write to the backup directory.
+var archiveTempPatterns = []string{"export-*.db", "import-*.db"}
+
+// ExportArchive writes a backup of the database to dest as an encrypted
+// archive protected by passphrase. The plain copy it encrypts is written to
+// a temporary file in the backup directory and removed afterwards.
+func (b *BackupService) ExportArchive(ctx context.Context, dest, passphrase string) (*dto.BackupDTO, error) {
+	if err := dto.ValidatePassphrase(passphrase); err != nil {
+		return nil, err
+	}
+
+	plain, err := b.createArchiveTemp(archiveTempPatterns[0])
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = os.Remove(plain)
+	}()
+	backup, err := b.Backup(ctx, plain)
+	if err != nil {
+		return nil, err
+	}
+
+	in, err := os.Open(plain)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read backup: %w", err)
+	}
+	defer func() {
+		_ = in.Close()
+	}()
+
+	tmp := dest + ".tmp"
+	out, err := os.OpenFile(tmp, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create archive: %w", err)
+	}
+	err = archive.Encrypt(out, &contextReader{ctx: ctx, r: in}, []byte(passphrase))
+	if err == nil {
+		err = out.Sync()
+	}
+	if closeErr := out.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(tmp, dest)
+	}
+	if err != nil {
+		_ = os.Remove(tmp)
+		return nil, fmt.Errorf("failed to write archive: %w", err)
+	}
+
+	info, err := os.Stat(dest)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read archive: %w", err)
+	}
+	backup.Path = dest
+	backup.Size = info.Size()
+	b.logger.Info("Encrypted archive written", "path", dest, "products", backup.ProductCount)
+	return backup, nil
+}
+
+// OpenArchive decrypts the archive at path into a temporary database in the
+// backup directory, migrates it to the current schema and validates it. The
+// caller must remove the returned file. Nothing is decrypted to disk unless
+// the passphrase is right, and a damaged or modified archive is rejected
+// before the data is used.
+func (b *BackupService) OpenArchive(ctx context.Context, path, passphrase string) (string, *dto.BackupDTO, error) {
+	in, err := os.Open(path)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to read archive: %w", err)
+	}
+	defer func() {
+		_ = in.Close()
+	}()
+
+	plain, err := b.createArchiveTemp(archiveTempPatterns[1])
+	if err != nil {
+		return "", nil, err
+	}
+	opened := false
+	defer func() {
+		if !opened {
+			_ = os.Remove(plain)
+		}
+	}()
+
+	out, err := os.OpenFile(plain, os.O_WRONLY|os.O_TRUNC, 0o600)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to create temporary database: %w", err)
+	}
+	err = archive.Decrypt(out, &contextReader{ctx: ctx, r: in}, []byte(passphrase))
+	if closeErr := out.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		switch {
+		case errors.Is(err, archive.ErrWrongPassphrase):
+			return "", nil, apperrors.Validation("passphrase", "failed to decrypt archive: %v", err)
+		case errors.Is(err, archive.ErrNotArchive), errors.Is(err, archive.ErrCorrupt):
+			return "", nil, apperrors.Validation("path", "failed to decrypt archive: %v", err)
+		}
+		return "", nil, fmt.Errorf("failed to decrypt archive: %w", err)
+	}
+
+	if _, err := b.Validate(ctx, plain); err != nil {
+		return "", nil, err
+	}
+	if err := migrateFile(plain); err != nil {
+		return "", nil, fmt.Errorf("failed to upgrade archive schema: %w", err)
+	}
+	backup, err := b.Validate(ctx, plain)
+	if err != nil {
+		return "", nil, err
+	}
+	backup.Path = path
+	if info, err := in.Stat(); err == nil {
+		backup.Size = info.Size()
+	}
+	opened = true
+	return plain, backup, nil
+}
+
+// createArchiveTemp creates an empty file named after pattern in the backup
+// directory, readable only by the user, and returns its path.
+func (b *BackupService) createArchiveTemp(pattern string) (string, error) {
+	if err := os.MkdirAll(b.config.Dir, 0o700); err != nil {
+		return "", fmt.Errorf("failed to create backup directory: %w", err)
+	}
+	file, err := os.CreateTemp(b.config.Dir, pattern)
+	if err != nil {
+		return "", fmt.Errorf("failed to create temporary database: %w", err)
+	}
+	if err := file.Close(); err != nil {
+		_ = os.Remove(file.Name())
+		return "", fmt.Errorf("failed to create temporary database: %w", err)
+	}
+	return file.Name(), nil
+}
+
+// removeArchiveTemps removes the plain databases that an archive export or
+// import left in dir when the app stopped before it could remove them.
+func removeArchiveTemps(logger *slog.Logger, dir string) {
+	for _, pattern := range archiveTempPatterns {
+		paths, err := filepath.Glob(filepath.Join(dir, pattern))
+		if err != nil {
+			continue
+		}
+		for _, path := range paths {
+			if err := os.Remove(path); err != nil {
+				logger.Warn("Failed to remove temporary archive database", "path", path, "error", err)
+				continue
+			}
+			logger.Info("Removed temporary archive database", "path", path)
+		}
+	}
+}
+
+// contextReader fails reads once ctx is done, so encrypting or decrypting a
+// large archive stops when the call is cancelled.
+type contextReader struct {
+	ctx context.Context
+	r   io.Reader
+}
+
+func (c *contextReader) Read(p []byte) (int, error) {
+	if err := c.ctx.Err(); err != nil {
+		return 0, err
+	}
+	return c.r.Read(p)
+}
+
+// migrateFile brings the database file at path to the latest schema.
+func migrateFile(path string) error {
+	db, err := sqlite.Open(path, sqlite.Options{})
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = db.Close()
+	}()
+
+	migrator, err := migrations.NewMigrator(db)
+	if err != nil {
+		return err
+	}
+	if err := migrator.Prepare(); err != nil {
+		return err
+	}
+	_, err = migrator.Up()
+	return err
+}
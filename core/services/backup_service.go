@@ -0,0 +1,440 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"product-management-app/core/config"
+	"product-management-app/core/dto"
+	apperrors "product-management-app/core/errors"
+	"product-management-app/core/sqlite"
+)
+
+const (
+	// scheduledBackupPrefix and scheduledBackupLayout name scheduled backups,
+	// e.g. backup-20240131-020000.db. Only files named this way are rotated.
+	scheduledBackupPrefix = "backup-"
+	scheduledBackupLayout = "20060102-150405"
+	scheduledBackupExt    = ".db"
+
+	// safetyBackupPrefix names the safety backups taken before a restore,
+	// e.g. pre-restore-20240131-020000.db, or pre-restore-20240131-020000-2.db
+	// for a second one within the same second.
+	safetyBackupPrefix = "pre-restore-"
+
+	// backupCheckInterval is how often the schedule checks whether a backup
+	// is due. Checking often rather than sleeping until the due time keeps
+	// the schedule on track across system sleep.
+	backupCheckInterval = 15 * time.Minute
+)
+
+// sqliteHeader starts every SQLite database file.
+var sqliteHeader = []byte("SQLite format 3\x00")
+
+// BackupService writes consistent copies of the open database with VACUUM
+// INTO, runs them on a schedule with rotation, and validates backup files
+// before they are restored.
+type BackupService struct {
+	logger *slog.Logger
+	db     *DatabaseService
+	config config.BackupConfig
+
+	// mu serializes backups, so the schedule and on-demand backups never
+	// write at the same time.
+	mu   sync.Mutex
+	stop chan struct{}
+	done chan struct{}
+}
+
+func NewBackupService(logger *slog.Logger, db *DatabaseService) *BackupService {
+	return &BackupService{logger: logger, db: db, config: db.Config.Backup}
+}
+
+// Backup writes a copy of the database to dest, replacing any existing file,
+// and returns a description of the copy. The copy is written to a temporary
+// file first so a failed backup never leaves a partial file at dest.
+func (b *BackupService) Backup(ctx context.Context, dest string) (*dto.BackupDTO, error) {
+	if b.db.DB == nil {
+		return nil, errNotConnected
+	}
+	dest, err := filepath.Abs(dest)
+	if err != nil {
+		return nil, apperrors.Validation("path", "invalid backup path: %v", err)
+	}
+	if dest == b.db.Config.Path {
+		return nil, apperrors.Validation("path", "cannot back up the database onto itself")
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if err := os.MkdirAll(filepath.Dir(dest), 0o700); err != nil {
+		return nil, fmt.Errorf("failed to create backup directory: %w", err)
+	}
+	tmp := dest + ".tmp"
+	_ = os.Remove(tmp)
+	if _, err := b.db.DB.ExecContext(ctx, "VACUUM INTO ?", tmp); err != nil {
+		_ = os.Remove(tmp)
+		return nil, fmt.Errorf("failed to back up database: %w", err)
+	}
+	if err := os.Rename(tmp, dest); err != nil {
+		_ = os.Remove(tmp)
+		return nil, fmt.Errorf("failed to move backup into place: %w", err)
+	}
+
+	backup, err := b.Validate(ctx, dest)
+	if err != nil {
+		return nil, fmt.Errorf("backup written to %s failed validation: %w", dest, err)
+	}
+	b.logger.Info("Database backed up", "path", dest, "products", backup.ProductCount)
+	return backup, nil
+}
+
+// Validate checks that path is an intact product database this version of
+// the application can open, and describes it. The file is opened read-only.
+func (b *BackupService) Validate(ctx context.Context, path string) (*dto.BackupDTO, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read backup: %w", err)
+	}
+	if info.IsDir() {
+		return nil, apperrors.Validation("path", "%s is a directory", path)
+	}
+	if err := checkSQLiteHeader(path); err != nil {
+		return nil, err
+	}
+
+	db, err := sqlite.Open(path, sqlite.Options{ReadOnly: true})
+	if err != nil {
+		return nil, fmt.Errorf("failed to open backup: %w", err)
+	}
+	defer func() {
+		_ = db.Close()
+	}()
+
+	var integrity string
+	if err := db.QueryRowContext(ctx, "PRAGMA integrity_check(1)").Scan(&integrity); err != nil {
+		return nil, fmt.Errorf("failed to check backup integrity: %w", err)
+	}
+	if integrity != "ok" {
+		return nil, apperrors.Validation("path", "backup is corrupt: %s", integrity)
+	}
+
+	backup := &dto.BackupDTO{
+		Path:      path,
+		Size:      info.Size(),
+		CreatedAt: info.ModTime().Format(time.RFC3339),
+	}
+	if err := db.QueryRowContext(ctx, "SELECT COUNT(*) FROM products").Scan(&backup.ProductCount); err != nil {
+		return nil, apperrors.Validation("path", "backup is not a product database: %v", err)
+	}
+
+	var hasMigrations int
+	if err := db.QueryRowContext(ctx, "SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name = 'schema_migrations'").Scan(&hasMigrations); err != nil {
+		return nil, fmt.Errorf("failed to inspect backup schema: %w", err)
+	}
+	if hasMigrations > 0 {
+		if err := db.QueryRowContext(ctx, "SELECT COALESCE(MAX(version), 0) FROM schema_migrations").Scan(&backup.SchemaVersion); err != nil {
+			return nil, fmt.Errorf("failed to read backup schema version: %w", err)
+		}
+	}
+	if b.db.migrator != nil && backup.SchemaVersion > b.db.migrator.LatestVersion() {
+		return nil, apperrors.Validation("path", "backup schema version %d is newer than this application supports (%d)", backup.SchemaVersion, b.db.migrator.LatestVersion())
+	}
+
+	if created, ok := parseScheduledBackupName(filepath.Base(path)); ok && filepath.Dir(path) == b.config.Dir {
+		backup.CreatedAt = created.Format(time.RFC3339)
+		backup.Scheduled = true
+	}
+	return backup, nil
+}
+
+func checkSQLiteHeader(path string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("failed to read backup: %w", err)
+	}
+	defer func() {
+		_ = file.Close()
+	}()
+
+	header := make([]byte, len(sqliteHeader))
+	if _, err := io.ReadFull(file, header); err != nil || !bytes.Equal(header, sqliteHeader) {
+		return apperrors.Validation("path", "%s is not a SQLite database", path)
+	}
+	return nil
+}
+
+// List returns the scheduled backups, newest first.
+func (b *BackupService) List() ([]dto.BackupDTO, error) {
+	paths, err := b.scheduledBackups()
+	if err != nil {
+		return nil, err
+	}
+
+	backups := make([]dto.BackupDTO, 0, len(paths))
+	for _, path := range paths {
+		info, err := os.Stat(path)
+		if err != nil {
+			continue
+		}
+		created, _ := parseScheduledBackupName(filepath.Base(path))
+		backups = append(backups, dto.BackupDTO{
+			Path:      path,
+			Size:      info.Size(),
+			CreatedAt: created.Format(time.RFC3339),
+			Scheduled: true,
+		})
+	}
+	return backups, nil
+}
+
+// scheduledBackups returns the paths of the scheduled backups, newest first.
+func (b *BackupService) scheduledBackups() ([]string, error) {
+	entries, err := os.ReadDir(b.config.Dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to list backups: %w", err)
+	}
+
+	var names []string
+	for _, entry := range entries {
+		if _, ok := parseScheduledBackupName(entry.Name()); ok && !entry.IsDir() {
+			names = append(names, entry.Name())
+		}
+	}
+	// The timestamp layout sorts chronologically as a string.
+	sort.Sort(sort.Reverse(sort.StringSlice(names)))
+
+	paths := make([]string, len(names))
+	for i, name := range names {
+		paths[i] = filepath.Join(b.config.Dir, name)
+	}
+	return paths, nil
+}
+
+func parseScheduledBackupName(name string) (time.Time, bool) {
+	if !strings.HasPrefix(name, scheduledBackupPrefix) || !strings.HasSuffix(name, scheduledBackupExt) {
+		return time.Time{}, false
+	}
+	stamp := strings.TrimSuffix(strings.TrimPrefix(name, scheduledBackupPrefix), scheduledBackupExt)
+	created, err := time.ParseInLocation(scheduledBackupLayout, stamp, time.Local)
+	return created, err == nil
+}
+
+// Start runs scheduled backups in the background until Stop is called. A
+// backup is taken right away if the last one is older than the interval.
+func (b *BackupService) Start() {
+	if b.config.Scheduled == nil || !*b.config.Scheduled || b.stop != nil {
+		return
+	}
+	b.stop = make(chan struct{})
+	b.done = make(chan struct{})
+
+	go func() {
+		defer close(b.done)
+		ticker := time.NewTicker(backupCheckInterval)
+		defer ticker.Stop()
+
+		for {
+			if err := b.runScheduled(); err != nil {
+				b.logger.Error("Scheduled backup failed", "error", err)
+			}
+			select {
+			case <-ticker.C:
+			case <-b.stop:
+				return
+			}
+		}
+	}()
+	b.logger.Info("Scheduled backups started", "intervalHours", b.config.IntervalHours, "dir", b.config.Dir, "keep", b.config.Keep)
+}
+
+// Stop ends the backup schedule and waits for a running backup to finish.
+func (b *BackupService) Stop() {
+	if b.stop == nil {
+		return
+	}
+	close(b.stop)
+	<-b.done
+	b.stop = nil
+}
+
+// runScheduled takes a backup if the newest scheduled backup is older than
+// the interval, then deletes the backups beyond the configured count.
+func (b *BackupService) runScheduled() error {
+	paths, err := b.scheduledBackups()
+	if err != nil {
+		return err
+	}
+	if len(paths) > 0 {
+		last, _ := parseScheduledBackupName(filepath.Base(paths[0]))
+		if time.Since(last) < time.Duration(b.config.IntervalHours)*time.Hour {
+			return nil
+		}
+	}
+
+	name := scheduledBackupPrefix + time.Now().Format(scheduledBackupLayout) + scheduledBackupExt
+	if _, err := b.Backup(context.Background(), filepath.Join(b.config.Dir, name)); err != nil {
+		return err
+	}
+	return b.rotate()
+}
+
+// rotate deletes the oldest scheduled backups beyond the configured count.
+func (b *BackupService) rotate() error {
+	paths, err := b.scheduledBackups()
+	if err != nil {
+		return err
+	}
+	for i := b.config.Keep; i < len(paths); i++ {
+		if err := os.Remove(paths[i]); err != nil {
+			return fmt.Errorf("failed to delete old backup: %w", err)
+		}
+		b.logger.Info("Deleted old backup", "path", paths[i])
+	}
+	return nil
+}
+
+// SafetyBackup copies the database into the backup directory before it is
+// replaced by a restore, then deletes the oldest safety backups beyond the
+// configured count. A second restore within the same second gets a numbered
+// name rather than overwriting the first safety backup. restoring is the
+// file about to be restored, if any, which is never deleted even when it is
+// an old safety backup.
+func (b *BackupService) SafetyBackup(ctx context.Context, restoring string) (string, error) {
+	base := safetyBackupPrefix + time.Now().Format(scheduledBackupLayout)
+	path := filepath.Join(b.config.Dir, base+scheduledBackupExt)
+	for n := 2; ; n++ {
+		if _, err := os.Stat(path); os.IsNotExist(err) {
+			break
+		}
+		path = filepath.Join(b.config.Dir, fmt.Sprintf("%s-%d%s", base, n, scheduledBackupExt))
+	}
+	if _, err := b.Backup(ctx, path); err != nil {
+		return "", err
+	}
+	// The new safety backup was written, so a failed cleanup does not stop
+	// the restore.
+	if err := b.rotateSafety(restoring); err != nil {
+		b.logger.Warn("Failed to delete old safety backups", "error", err)
+	}
+	return path, nil
+}
+
+// rotateSafety deletes the oldest safety backups beyond the configured
+// count, except restoring.
+func (b *BackupService) rotateSafety(restoring string) error {
+	paths, err := b.safetyBackups()
+	if err != nil {
+		return err
+	}
+	var keep os.FileInfo
+	if restoring != "" {
+		keep, _ = os.Stat(restoring)
+	}
+	for i := b.config.KeepSafety; i < len(paths); i++ {
+		if info, err := os.Stat(paths[i]); err == nil && keep != nil && os.SameFile(info, keep) {
+			continue
+		}
+		if err := os.Remove(paths[i]); err != nil {
+			return fmt.Errorf("failed to delete old safety backup: %w", err)
+		}
+		b.logger.Info("Deleted old safety backup", "path", paths[i])
+	}
+	return nil
+}
+
+// safetyBackups returns the paths of the safety backups, newest first.
+func (b *BackupService) safetyBackups() ([]string, error) {
+	entries, err := os.ReadDir(b.config.Dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to list backups: %w", err)
+	}
+
+	type safetyBackup struct {
+		path    string
+		created time.Time
+		n       int
+	}
+	var backups []safetyBackup
+	for _, entry := range entries {
+		if created, n, ok := parseSafetyBackupName(entry.Name()); ok && !entry.IsDir() {
+			backups = append(backups, safetyBackup{filepath.Join(b.config.Dir, entry.Name()), created, n})
+		}
+	}
+	// Numbered names do not sort as strings, so compare the parts.
+	sort.Slice(backups, func(i, j int) bool {
+		if !backups[i].created.Equal(backups[j].created) {
+			return backups[i].created.After(backups[j].created)
+		}
+		return backups[i].n > backups[j].n
+	})
+
+	paths := make([]string, len(backups))
+	for i, backup := range backups {
+		paths[i] = backup.path
+	}
+	return paths, nil
+}
+
+// parseSafetyBackupName returns the time of a safety backup and its number
+// within that second, 1 for an unnumbered name.
+func parseSafetyBackupName(name string) (time.Time, int, bool) {
+	if !strings.HasPrefix(name, safetyBackupPrefix) || !strings.HasSuffix(name, scheduledBackupExt) {
+		return time.Time{}, 0, false
+	}
+	stamp := strings.TrimSuffix(strings.TrimPrefix(name, safetyBackupPrefix), scheduledBackupExt)
+	if len(stamp) < len(scheduledBackupLayout) {
+		return time.Time{}, 0, false
+	}
+	created, err := time.ParseInLocation(scheduledBackupLayout, stamp[:len(scheduledBackupLayout)], time.Local)
+	if err != nil {
+		return time.Time{}, 0, false
+	}
+	n := 1
+	if rest := stamp[len(scheduledBackupLayout):]; rest != "" {
+		n, err = strconv.Atoi(strings.TrimPrefix(rest, "-"))
+		if err != nil || !strings.HasPrefix(rest, "-") || n < 2 {
+			return time.Time{}, 0, false
+		}
+	}
+	return created, n, true
+}
+
+// replaceDatabaseFile copies src over the closed database at dst. Leftover
+// WAL and shared-memory files are removed, since SQLite would otherwise
+// replay the old database's WAL into the new file.
+func replaceDatabaseFile(src, dst string) error {
+	tmp := dst + ".restore"
+	_ = os.Remove(tmp)
+	if err := copyFile(src, tmp); err != nil {
+		return fmt.Errorf("failed to copy backup: %w", err)
+	}
+	for _, suffix := range []string{"-wal", "-shm"} {
+		if err := os.Remove(dst + suffix); err != nil && !os.IsNotExist(err) {
+			_ = os.Remove(tmp)
+			return fmt.Errorf("failed to remove %s: %w", dst+suffix, err)
+		}
+	}
+	if err := os.Rename(tmp, dst); err != nil {
+		_ = os.Remove(tmp)
+		return fmt.Errorf("failed to replace database: %w", err)
+	}
+	return nil
+}
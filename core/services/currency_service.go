@@ -1,23 +1,22 @@
 package service
 
 import (
-	"context"
 	"encoding/json"
 	"fmt"
 	"io"
-	"log"
+	"log/slog"
 	"net/http"
 	"strings"
 	"sync"
 	"time"
 
 	"product-management-app/core/dto"
-
-	"github.com/wailsapp/wails/v2/pkg/runtime"
+	apperrors "product-management-app/core/errors"
+	"product-management-app/core/money"
 )
 
 type CurrencyService struct {
-	ctx                 context.Context
+	logger              *slog.Logger
 	httpClient          *http.Client
 	cachedRates         map[string]map[string]float64 // [baseCurrency][targetCurrency]rate
 	cacheExpiry         map[string]time.Time          // [baseCurrency]expiryTime
@@ -35,9 +34,9 @@ const (
 	httpTimeout = 10 * time.Second
 )
 
-func NewCurrencyService(ctx context.Context) *CurrencyService {
+func NewCurrencyService(logger *slog.Logger) *CurrencyService {
 	return &CurrencyService{
-		ctx:                 ctx,
+		logger:              logger,
 		httpClient:          &http.Client{Timeout: httpTimeout},
 		cachedRates:         make(map[string]map[string]float64),
 		cacheExpiry:         make(map[string]time.Time),
@@ -46,31 +45,18 @@ func NewCurrencyService(ctx context.Context) *CurrencyService {
 	}
 }
 
-func (cs *CurrencyService) logInfo(message string) {
-	// Para standalone testing, apenas usa log padrão
-	log.Printf("INFO: %s", message)
-}
-
-func (cs *CurrencyService) logWarning(message string) {
-	log.Printf("WARNING: %s", message)
-}
-
-func (cs *CurrencyService) logError(message string) {
-	log.Printf("ERROR: %s", message)
-}
-
 func initSupportedCurrencies() map[string]dto.CurrencyInfo {
 	return map[string]dto.CurrencyInfo{
-		"BRL": {Code: "BRL", Symbol: "R$", Name: "Brazilian Real"},
-		"USD": {Code: "USD", Symbol: "$", Name: "US Dollar"},
-		"EUR": {Code: "EUR", Symbol: "€", Name: "Euro"},
-		"GBP": {Code: "GBP", Symbol: "£", Name: "British Pound"},
-		"JPY": {Code: "JPY", Symbol: "¥", Name: "Japanese Yen"},
-		"CAD": {Code: "CAD", Symbol: "C$", Name: "Canadian Dollar"},
-		"AUD": {Code: "AUD", Symbol: "A$", Name: "Australian Dollar"},
-		"CHF": {Code: "CHF", Symbol: "CHF", Name: "Swiss Franc"},
-		"CNY": {Code: "CNY", Symbol: "¥", Name: "Chinese Yuan"},
-		"INR": {Code: "INR", Symbol: "₹", Name: "Indian Rupee"},
+		"BRL": {Code: "BRL", Symbol: "R$", Name: "Brazilian Real", Decimals: 2},
+		"USD": {Code: "USD", Symbol: "$", Name: "US Dollar", Decimals: 2},
+		"EUR": {Code: "EUR", Symbol: "€", Name: "Euro", Decimals: 2},
+		"GBP": {Code: "GBP", Symbol: "£", Name: "British Pound", Decimals: 2},
+		"JPY": {Code: "JPY", Symbol: "¥", Name: "Japanese Yen", Decimals: 0},
+		"CAD": {Code: "CAD", Symbol: "C$", Name: "Canadian Dollar", Decimals: 2},
+		"AUD": {Code: "AUD", Symbol: "A$", Name: "Australian Dollar", Decimals: 2},
+		"CHF": {Code: "CHF", Symbol: "CHF", Name: "Swiss Franc", Decimals: 2},
+		"CNY": {Code: "CNY", Symbol: "¥", Name: "Chinese Yuan", Decimals: 2},
+		"INR": {Code: "INR", Symbol: "₹", Name: "Indian Rupee", Decimals: 2},
 	}
 }
 
@@ -79,21 +65,21 @@ func (cs *CurrencyService) fetchExchangeRates(baseCurrency string) (map[string]f
 
 	rates, err := cs.fetchFromURL(fmt.Sprintf("%s/%s.json", primaryAPIURL, baseCurrency))
 	if err != nil {
-		cs.logWarning(fmt.Sprintf("Primary API failed for %s: %v, trying fallback", baseCurrency, err))
+		cs.logger.Warn("Primary exchange rate API failed, trying fallback", "currency", baseCurrency, "error", err)
 
 		rates, err = cs.fetchFromURL(fmt.Sprintf("%s/%s.json", fallbackAPIURL, baseCurrency))
 		if err != nil {
-			cs.logError(fmt.Sprintf("Both APIs failed for %s: %v", baseCurrency, err))
-			return nil, fmt.Errorf("failed to fetch exchange rates from both APIs: %v", err)
+			cs.logger.Error("Both exchange rate APIs failed", "currency", baseCurrency, "error", err)
+			return nil, apperrors.Unavailable(err, "failed to fetch exchange rates from both APIs")
 		}
 	}
 
-	cs.logInfo(fmt.Sprintf("Successfully fetched exchange rates for %s", baseCurrency))
+	cs.logger.Info("Fetched exchange rates", "currency", baseCurrency)
 	return rates, nil
 }
 
 func (cs *CurrencyService) fetchFromURL(url string) (map[string]float64, error) {
-	cs.logInfo(fmt.Sprintf("Fetching exchange rates from: %s", url))
+	cs.logger.Info("Fetching exchange rates", "url", url)
 
 	resp, err := cs.httpClient.Get(url)
 	if err != nil {
@@ -101,7 +87,7 @@ func (cs *CurrencyService) fetchFromURL(url string) (map[string]float64, error)
 	}
 	defer func() {
 		if err := resp.Body.Close(); err != nil {
-			runtime.LogError(cs.ctx, fmt.Sprintf("Failed to close response body: %v", err))
+			cs.logger.Error("Failed to close response body", "error", err)
 		}
 	}()
 
@@ -146,7 +132,7 @@ func (cs *CurrencyService) getRatesFromCache(baseCurrency string) (map[string]fl
 
 	if expiry, exists := cs.cacheExpiry[baseCurrency]; exists && time.Now().Before(expiry) {
 		if rates, exists := cs.cachedRates[baseCurrency]; exists {
-			cs.logInfo(fmt.Sprintf("Using cached rates for %s", baseCurrency))
+			cs.logger.Info("Using cached exchange rates", "currency", baseCurrency)
 			return rates, true
 		}
 	}
@@ -161,7 +147,7 @@ func (cs *CurrencyService) saveRatesToCache(baseCurrency string, rates map[strin
 	cs.cachedRates[baseCurrency] = rates
 	cs.cacheExpiry[baseCurrency] = time.Now().Add(cs.cacheTimeout)
 
-	cs.logInfo(fmt.Sprintf("Cached rates for %s until %v", baseCurrency, cs.cacheExpiry[baseCurrency]))
+	cs.logger.Info("Cached exchange rates", "currency", baseCurrency, "until", cs.cacheExpiry[baseCurrency])
 }
 
 func (cs *CurrencyService) getExchangeRate(fromCurrency, toCurrency string) (float64, error) {
@@ -189,14 +175,17 @@ func (cs *CurrencyService) getExchangeRate(fromCurrency, toCurrency string) (flo
 		return rate, nil
 	}
 
-	return 0, fmt.Errorf("exchange rate not found for %s to %s", fromCurrency, toCurrency)
+	return 0, apperrors.Validation("toCurrency", "exchange rate not found for %s to %s", fromCurrency, toCurrency)
 }
 
 func (cs *CurrencyService) ConvertCurrency(request dto.CurrencyConversionRequest) (*dto.CurrencyConversionResponse, error) {
-	cs.logInfo(fmt.Sprintf("Converting %.2f %s to %s", request.Amount, request.FromCurrency, request.ToCurrency))
+	cs.logger.Info("Converting currency", "amount", request.Amount, "from", request.FromCurrency, "to", request.ToCurrency)
 
 	if request.Amount < 0 {
-		return nil, fmt.Errorf("amount must be positive")
+		return nil, apperrors.Validation("amount", "amount must be positive")
+	}
+	if !request.Rounding.Valid() {
+		return nil, apperrors.Validation("rounding", "unknown rounding mode %q", request.Rounding)
 	}
 
 	rate, err := cs.getExchangeRate(request.FromCurrency, request.ToCurrency)
@@ -204,7 +193,7 @@ func (cs *CurrencyService) ConvertCurrency(request dto.CurrencyConversionRequest
 		return nil, err
 	}
 
-	convertedAmount := request.Amount * rate
+	convertedAmount := request.Amount.Mul(rate, cs.minorUnit(request.ToCurrency), request.Rounding)
 
 	response := &dto.CurrencyConversionResponse{
 		Amount:          request.Amount,
@@ -215,12 +204,24 @@ func (cs *CurrencyService) ConvertCurrency(request dto.CurrencyConversionRequest
 		ConversionDate:  time.Now(),
 	}
 
-	cs.logInfo(fmt.Sprintf("Conversion successful: %.2f %s = %.2f %s (rate: %.6f)",
-		request.Amount, response.FromCurrency, convertedAmount, response.ToCurrency, rate))
+	cs.logger.Info("Currency converted", "amount", request.Amount, "from", response.FromCurrency,
+		"converted", convertedAmount, "to", response.ToCurrency, "rate", rate)
 
 	return response, nil
 }
 
+// minorUnit returns the smallest amount of currency, e.g. one whole yen.
+// Currencies without a known number of decimals use cents.
+func (cs *CurrencyService) minorUnit(currency string) money.Money {
+	unit := money.Cent
+	if info, ok := cs.supportedCurrencies[strings.ToUpper(currency)]; ok {
+		for decimals := info.Decimals; decimals < money.Decimals; decimals++ {
+			unit *= 10
+		}
+	}
+	return unit
+}
+
 func (cs *CurrencyService) GetSupportedCurrencies() *dto.SupportedCurrenciesResponse {
 	currencies := make([]dto.CurrencyInfo, 0, len(cs.supportedCurrencies))
 	for _, currency := range cs.supportedCurrencies {
@@ -234,7 +235,7 @@ func (cs *CurrencyService) GetSupportedCurrencies() *dto.SupportedCurrenciesResp
 
 func (cs *CurrencyService) GetExchangeRatesForCurrency(baseCurrency string) (*dto.CurrencyRatesResponse, error) {
 	baseCurrency = strings.ToUpper(baseCurrency)
-	cs.logInfo(fmt.Sprintf("Getting all exchange rates for %s", baseCurrency))
+	cs.logger.Info("Getting exchange rates", "currency", baseCurrency)
 
 	if rates, found := cs.getRatesFromCache(baseCurrency); found {
 		return &dto.CurrencyRatesResponse{
@@ -265,5 +266,5 @@ func (cs *CurrencyService) ClearCache() {
 	cs.cachedRates = make(map[string]map[string]float64)
 	cs.cacheExpiry = make(map[string]time.Time)
 
-	cs.logInfo("Currency exchange rates cache cleared")
+	cs.logger.Info("Currency exchange rates cache cleared")
 }
@@ -4,82 +4,361 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
 
-	_ "github.com/mattn/go-sqlite3" // SQLite driver
-	"github.com/wailsapp/wails/v2/pkg/runtime"
+	"product-management-app/core/config"
+	"product-management-app/core/dto"
+	apperrors "product-management-app/core/errors"
+	"product-management-app/core/migrations"
+	"product-management-app/core/repositories"
+	"product-management-app/core/sqlite"
 )
 
+// errNotConnected is returned when the database is used while it is closed.
+var errNotConnected = apperrors.Unavailable(nil, "database connection not established")
+
 type DatabaseService struct {
-	DB  *sql.DB
-	Ctx context.Context
+	DB     *sql.DB
+	Config config.DatabaseConfig
+	logger *slog.Logger
+
+	// SchemaVersion is the migration version the database is at.
+	SchemaVersion int
+	// MigrationBackup is the copy written before the last migration run, if
+	// any migrations were applied to an existing database.
+	MigrationBackup string
+	migrator        *migrations.Migrator
+
+	// FullTextSearch reports whether the products_fts index is available.
+	// It is false when the SQLite build lacks the FTS5 extension.
+	FullTextSearch bool
 }
 
-func NewDatabaseService(ctx context.Context) *DatabaseService {
-	return &DatabaseService{Ctx: ctx}
+func NewDatabaseService(logger *slog.Logger, cfg config.DatabaseConfig) *DatabaseService {
+	return &DatabaseService{Config: cfg, logger: logger}
 }
 
 func (d *DatabaseService) InitDatabase() error {
+	if err := os.MkdirAll(filepath.Dir(d.Config.Path), 0o700); err != nil {
+		d.logger.Error("Failed to create database directory", "error", err)
+		return fmt.Errorf("failed to create database directory: %w", err)
+	}
+	if d.Config.PathSource == config.SourceDefault {
+		if err := d.moveLegacyDatabase(); err != nil {
+			d.logger.Warn("Failed to move legacy database", "error", err)
+		}
+	}
+
+	if err := d.Config.ApplyDefaults(); err != nil {
+		d.logger.Warn("Invalid database settings replaced by defaults", "error", err)
+	}
+
 	var err error
-	d.DB, err = sql.Open("sqlite3", "./database.db")
+	d.DB, err = sqlite.Open(d.Config.Path, d.connectionOptions())
 	if err != nil {
-		runtime.LogError(d.Ctx, fmt.Sprintf("Failed to open database: %v", err))
+		d.logger.Error("Failed to open database", "error", err)
 		return fmt.Errorf("failed to open database: %w", err)
 	}
-	createTableSQL := `
-	CREATE TABLE IF NOT EXISTS products (
-		id INTEGER PRIMARY KEY AUTOINCREMENT,
-		name TEXT NOT NULL,
-		price REAL NOT NULL,
-		category TEXT,
-		stock INTEGER DEFAULT 0,
-		description TEXT,
-		image_url TEXT,
-		created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
-		updated_at TIMESTAMP
-	);`
-
-	_, err = d.DB.Exec(createTableSQL)
+	d.DB.SetMaxOpenConns(d.Config.MaxOpenConns)
+	d.DB.SetMaxIdleConns(d.Config.MaxIdleConns)
+
+	if err := d.migrate(); err != nil {
+		d.logger.Error("Failed to migrate database", "error", err)
+		return err
+	}
+	d.logger.Info("SQLite database initialized", "path", d.Config.Path, "schemaVersion", d.SchemaVersion)
+
+	if err := d.initFullTextSearch(); err != nil {
+		d.FullTextSearch = false
+		d.logger.Warn("Full-text search disabled", "error", err)
+	}
+	return nil
+}
+
+// connectionOptions returns the configured pragmas, which the driver applies
+// to every pooled connection, not just the first one.
+func (d *DatabaseService) connectionOptions() sqlite.Options {
+	return sqlite.Options{
+		JournalMode:   d.Config.JournalMode,
+		Synchronous:   d.Config.Synchronous,
+		BusyTimeoutMs: d.Config.BusyTimeoutMs,
+		ForeignKeys:   *d.Config.ForeignKeys,
+		ImmediateTx:   true,
+	}
+}
+
+// Settings reports the pragmas in effect on a pooled connection and the
+// connection pool limits and usage.
+func (d *DatabaseService) Settings(ctx context.Context) (*dto.DatabaseSettingsDTO, error) {
+	if d.DB == nil {
+		return nil, errNotConnected
+	}
+
+	settings := &dto.DatabaseSettingsDTO{
+		Driver:       sqlite.Driver,
+		MaxOpenConns: d.Config.MaxOpenConns,
+		MaxIdleConns: d.Config.MaxIdleConns,
+	}
+	pragmas := []struct {
+		name string
+		dest interface{}
+	}{
+		{"journal_mode", &settings.JournalMode},
+		{"synchronous", &settings.Synchronous},
+		{"busy_timeout", &settings.BusyTimeoutMs},
+		{"foreign_keys", &settings.ForeignKeys},
+	}
+	for _, pragma := range pragmas {
+		if err := d.DB.QueryRowContext(ctx, "PRAGMA "+pragma.name).Scan(pragma.dest); err != nil {
+			return nil, fmt.Errorf("failed to read PRAGMA %s: %w", pragma.name, err)
+		}
+	}
+	settings.JournalMode = strings.ToUpper(settings.JournalMode)
+	settings.Synchronous = synchronousNames[settings.Synchronous]
+
+	stats := d.DB.Stats()
+	settings.OpenConnections = stats.OpenConnections
+	settings.InUse = stats.InUse
+	settings.WaitCount = stats.WaitCount
+	return settings, nil
+}
+
+// synchronousNames maps the numeric value PRAGMA synchronous returns to its name.
+var synchronousNames = map[string]string{"0": "OFF", "1": "NORMAL", "2": "FULL", "3": "EXTRA"}
+
+// migrate brings the schema up to the latest embedded migration. When an
+// existing database has pending migrations, a backup copy is written next to
+// it first.
+func (d *DatabaseService) migrate() error {
+	migrator, err := migrations.NewMigrator(d.DB)
+	if err != nil {
+		return err
+	}
+	d.migrator = migrator
+
+	if err := migrator.Prepare(); err != nil {
+		return err
+	}
+	current, err := migrator.CurrentVersion()
+	if err != nil {
+		return err
+	}
+	pending, err := migrator.Pending()
+	if err != nil {
+		return err
+	}
+
+	if len(pending) > 0 && current > 0 {
+		backupPath := fmt.Sprintf("%s.pre-migration-v%d-%s.bak", d.Config.Path, current, time.Now().Format("20060102-150405"))
+		if _, err := d.DB.Exec("VACUUM INTO ?", backupPath); err != nil {
+			return fmt.Errorf("failed to back up database before migrating: %w", err)
+		}
+		d.MigrationBackup = backupPath
+		d.logger.Info("Database backed up before migrating", "path", backupPath)
+	}
+
+	applied, err := migrator.Up()
+	for _, migration := range applied {
+		d.logger.Info("Applied migration", "version", migration.Version, "name", migration.Name)
+	}
+	if err != nil {
+		return err
+	}
+
+	d.SchemaVersion, err = migrator.CurrentVersion()
+	return err
+}
+
+// moveLegacyDatabase moves a database left in the working directory by
+// earlier versions to the configured path, unless a database already exists
+// there. The old file is renamed rather than deleted so it can be recovered.
+func (d *DatabaseService) moveLegacyDatabase() error {
+	legacyPath, err := filepath.Abs(config.LegacyDatabasePath)
+	if err != nil || legacyPath == d.Config.Path {
+		return err
+	}
+	if _, err := os.Stat(legacyPath); err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	if _, err := os.Stat(d.Config.Path); err == nil {
+		return nil
+	}
+
+	if err := copyFile(legacyPath, d.Config.Path); err != nil {
+		return fmt.Errorf("failed to copy %s to %s: %w", legacyPath, d.Config.Path, err)
+	}
+	if err := os.Rename(legacyPath, legacyPath+".moved"); err != nil {
+		return fmt.Errorf("database copied but %s could not be renamed: %w", legacyPath, err)
+	}
+	d.logger.Info("Moved legacy database", "from", legacyPath, "to", d.Config.Path)
+	return nil
+}
+
+// copyFile copies src to a new file at dst, removing dst again on failure.
+func copyFile(src, dst string) (err error) {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = in.Close()
+	}()
+
+	out, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o600)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if closeErr := out.Close(); err == nil {
+			err = closeErr
+		}
+		if err != nil {
+			_ = os.Remove(dst)
+		}
+	}()
+
+	if _, err = io.Copy(out, in); err != nil {
+		return err
+	}
+	return out.Sync()
+}
+
+// SchemaStatus reports the applied and pending migrations.
+func (d *DatabaseService) SchemaStatus() (*dto.SchemaStatusDTO, error) {
+	if d.migrator == nil {
+		return nil, errNotConnected
+	}
+
+	applied, err := d.migrator.Applied()
+	if err != nil {
+		return nil, err
+	}
+	pending, err := d.migrator.Pending()
+	if err != nil {
+		return nil, err
+	}
+
+	status := &dto.SchemaStatusDTO{
+		CurrentVersion:  d.SchemaVersion,
+		LatestVersion:   d.migrator.LatestVersion(),
+		Applied:         make([]dto.SchemaMigrationDTO, 0, len(applied)),
+		Pending:         make([]dto.SchemaMigrationDTO, 0, len(pending)),
+		MigrationBackup: d.MigrationBackup,
+	}
+	for _, migration := range applied {
+		status.Applied = append(status.Applied, dto.SchemaMigrationDTO{
+			Version:   migration.Version,
+			Name:      migration.Name,
+			Checksum:  migration.Checksum,
+			AppliedAt: migration.AppliedAt,
+		})
+	}
+	for _, migration := range pending {
+		status.Pending = append(status.Pending, dto.SchemaMigrationDTO{
+			Version:  migration.Version,
+			Name:     migration.Name,
+			Checksum: migration.Checksum,
+		})
+	}
+	return status, nil
+}
+
+// initFullTextSearch creates the FTS5 index and its triggers. The index is
+// rebuilt from the products table whenever it is new or its triggers were
+// missing, since rows written in the meantime were not mirrored.
+func (d *DatabaseService) initFullTextSearch() error {
+	fts5Enabled, err := sqlite.HasFTS5(d.DB)
+	if err != nil {
+		return fmt.Errorf("failed to inspect SQLite compile options: %w", err)
+	}
+	if !fts5Enabled {
+		// Triggers left by an FTS5-enabled build would make every write fail
+		// with "no such module: fts5", so drop them until FTS5 is back.
+		for _, trigger := range []string{"products_fts_ai", "products_fts_ad", "products_fts_au"} {
+			if _, err := d.DB.Exec("DROP TRIGGER IF EXISTS " + trigger); err != nil {
+				return fmt.Errorf("failed to drop trigger %s: %w", trigger, err)
+			}
+		}
+		return fmt.Errorf("SQLite was built without the FTS5 extension")
+	}
+
+	var triggerCount int
+	err = d.DB.QueryRow("SELECT COUNT(*) FROM sqlite_master WHERE type = 'trigger' AND name LIKE 'products_fts_%'").Scan(&triggerCount)
 	if err != nil {
-		return fmt.Errorf("failed to create products table: %w", err)
+		return fmt.Errorf("failed to inspect schema: %w", err)
 	}
-	runtime.LogInfo(d.Ctx, "SQLite database and 'products' table initialized successfully!")
+
+	tx, err := d.DB.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	for _, statement := range repositories.FullTextSearchSchema {
+		if _, err := tx.Exec(statement); err != nil {
+			return fmt.Errorf("failed to create full-text search index: %w", err)
+		}
+	}
+
+	if triggerCount < 3 {
+		if _, err := tx.Exec("INSERT INTO products_fts(products_fts) VALUES('rebuild')"); err != nil {
+			return fmt.Errorf("failed to populate full-text search index: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit full-text search index: %w", err)
+	}
+
+	d.FullTextSearch = true
+	d.logger.Info("Full-text search index initialized")
 	return nil
 }
 
 func (d *DatabaseService) CloseDatabase() {
 	if d.DB != nil {
-		runtime.LogInfo(d.Ctx, "Closing database connection...")
+		d.logger.Info("Closing database connection")
 
 		// Ensure all transactions are completed before closing
 		if err := d.DB.Ping(); err == nil {
 			// Database is still responsive, perform cleanup
 			_, err := d.DB.Exec("PRAGMA optimize")
 			if err != nil {
-				runtime.LogWarning(d.Ctx, fmt.Sprintf("Failed to optimize database before close: %v", err))
+				d.logger.Warn("Failed to optimize database before close", "error", err)
 			}
 		}
 
 		err := d.DB.Close()
 		if err != nil {
-			runtime.LogError(d.Ctx, fmt.Sprintf("Error closing database: %v", err))
+			d.logger.Error("Failed to close database", "error", err)
 		} else {
-			runtime.LogInfo(d.Ctx, "Database connection closed successfully.")
+			d.logger.Info("Database connection closed")
 		}
 		d.DB = nil
 	}
 }
 
-func (d *DatabaseService) HealthCheck() error {
+func (d *DatabaseService) HealthCheck(ctx context.Context) error {
 	if d.DB == nil {
-		runtime.LogError(d.Ctx, "Database connection not established")
-		return fmt.Errorf("database connection not established")
+		d.logger.Error("Database connection not established")
+		return errNotConnected
 	}
 
-	if err := d.DB.Ping(); err != nil {
-		runtime.LogError(d.Ctx, fmt.Sprintf("Database connection is not healthy: %v", err))
-		return fmt.Errorf("database connection is not healthy: %w", err)
+	if err := d.DB.PingContext(ctx); err != nil {
+		d.logger.Error("Database connection is not healthy", "error", err)
+		return apperrors.Unavailable(err, "database connection is not healthy")
 	}
 
-	runtime.LogInfo(d.Ctx, "Database connection is healthy.")
+	d.logger.Info("Database connection is healthy")
 	return nil
 }
@@ -0,0 +1,13 @@
+package service
+
+// EventEmitter delivers an event to the frontend. The Wails app passes a
+// function wrapping runtime.EventsEmit; headless callers can pass nil, in
+// which case events are dropped.
+type EventEmitter func(name string, data interface{})
+
+// Emit sends the event, doing nothing when e is nil.
+func (e EventEmitter) Emit(name string, data interface{}) {
+	if e != nil {
+		e(name, data)
+	}
+}
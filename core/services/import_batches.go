@@ -0,0 +1,231 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"product-management-app/core/dto"
+	apperrors "product-management-app/core/errors"
+	"product-management-app/core/models"
+	"product-management-app/core/repositories"
+)
+
+// Sources recorded on import batches.
+const (
+	importSourceCSV  = "csv"
+	importSourceXLSX = "xlsx"
+)
+
+// maxImportBatches caps the number of batches ListImportBatches returns.
+const maxImportBatches = 100
+
+// errImportFailed rolls back the transaction of an atomic import that has a
+// failed row.
+var errImportFailed = errors.New("import has failed rows")
+
+// runImport applies the rows of src in chunks of importChunkSize and
+// records the products it wrote as an import batch, reporting progress after
+// every chunk. An atomic import runs in one transaction and writes nothing
+// if any row fails. Otherwise every chunk is a transaction of its own, and
+// when one fails or the import is cancelled the chunks committed before it
+// are kept, with their batch, while result is left as it was after them.
+func (s *ImportExportService) runImport(ctx context.Context, source string, options dto.ImportOptions, result *dto.ImportResult, src rowSource) error {
+	ctx, job := s.startImport(ctx, source)
+	batch := &batchRecorder{source: source, mode: options.Mode}
+	var err error
+	if options.Atomic {
+		err = s.applyAtomic(ctx, job, batch, result, src)
+	} else {
+		err = s.applyChunks(ctx, job, batch, result, src)
+	}
+	job.finish(err)
+	return err
+}
+
+// applyChunks applies src one transaction per chunk.
+func (s *ImportExportService) applyChunks(ctx context.Context, job *importJob, batch *batchRecorder, result *dto.ImportResult, src rowSource) error {
+	for done := false; !done; {
+		committed := *result
+		err := s.store.WithTx(ctx, func(tx repositories.ProductStore) error {
+			plans, chunkDone, err := src.apply(ctx, tx, importChunkSize, result)
+			if err != nil {
+				return err
+			}
+			done = chunkDone
+			return batch.record(ctx, tx, plans)
+		})
+		if err != nil {
+			// The rows of the failed chunk were rolled back; the slices of
+			// result only grow, so the copy still holds the committed rows.
+			*result = committed
+			return fmt.Errorf("import stopped after %d products were imported: %w", result.SuccessCount, err)
+		}
+		result.BatchID = batch.id
+		job.report(src, result)
+	}
+	return nil
+}
+
+// applyAtomic applies src in one transaction, rolled back if any row fails.
+func (s *ImportExportService) applyAtomic(ctx context.Context, job *importJob, batch *batchRecorder, result *dto.ImportResult, src rowSource) error {
+	err := s.store.WithTx(ctx, func(tx repositories.ProductStore) error {
+		for done := false; !done; {
+			plans, chunkDone, err := src.apply(ctx, tx, importChunkSize, result)
+			if err != nil {
+				return err
+			}
+			done = chunkDone
+			if err := batch.record(ctx, tx, plans); err != nil {
+				return err
+			}
+			job.report(src, result)
+		}
+		if result.ErrorCount > 0 {
+			return errImportFailed
+		}
+		return nil
+	})
+	switch {
+	case errors.Is(err, errImportFailed):
+		result.RolledBack = true
+		result.SuccessCount, result.CreatedCount, result.UpdatedCount = 0, 0, 0
+		result.ImportedItems = []*models.Product{}
+		return nil
+	case err != nil:
+		return fmt.Errorf("atomic import wrote nothing: %w", err)
+	}
+	result.BatchID = batch.id
+	return nil
+}
+
+// batchRecorder records the products an import writes as one import batch,
+// created by the first chunk that writes a product.
+type batchRecorder struct {
+	source string
+	mode   dto.ImportMode
+	id     int
+}
+
+// record adds the products plans wrote to the batch, once per product. A
+// product written again keeps its state from before the first write.
+func (b *batchRecorder) record(ctx context.Context, store repositories.ProductStore, plans []*plannedRow) error {
+	batch := models.ImportBatch{Source: b.source, Mode: string(b.mode)}
+	items := make(map[int]int)
+	for _, plan := range plans {
+		if plan.written == nil {
+			continue
+		}
+		if i, ok := items[plan.written.ID]; ok {
+			batch.Items[i].Version = plan.written.Version
+			continue
+		}
+		item := models.ImportBatchItem{ProductID: plan.written.ID, Version: plan.written.Version}
+		if plan.status == dto.ImportRowChanged {
+			item.Before = plan.match
+			batch.UpdatedCount++
+		} else {
+			batch.CreatedCount++
+		}
+		items[item.ProductID] = len(batch.Items)
+		batch.Items = append(batch.Items, item)
+	}
+
+	switch {
+	case len(batch.Items) == 0:
+		return nil
+	case b.id != 0:
+		return store.AddImportBatchItems(ctx, b.id, batch.Items)
+	}
+	created, err := store.CreateImportBatch(ctx, batch)
+	if err != nil {
+		return err
+	}
+	b.id = created.ID
+	return nil
+}
+
+// ListImportBatches returns up to limit import batches without their
+// items, newest first. limit is capped at 100.
+func (s *ImportExportService) ListImportBatches(ctx context.Context, limit int) ([]*models.ImportBatch, error) {
+	if limit < 1 || limit > maxImportBatches {
+		limit = maxImportBatches
+	}
+	return s.store.ListImportBatches(ctx, limit)
+}
+
+// RollbackImport undoes an import batch in one transaction: the products
+// the import created are removed for good and the products it updated get
+// their earlier values back. If any of them was changed or deleted since
+// the import, it fails with a conflict and changes nothing.
+func (s *ImportExportService) RollbackImport(ctx context.Context, batchID int) (*models.ImportBatch, error) {
+	var batch *models.ImportBatch
+	err := s.store.WithTx(ctx, func(tx repositories.ProductStore) error {
+		var err error
+		if batch, err = tx.GetImportBatch(ctx, batchID); err != nil {
+			return err
+		}
+		if batch.RolledBackAt != nil {
+			return apperrors.Conflict("import batch %d was already rolled back", batchID)
+		}
+		for i := len(batch.Items) - 1; i >= 0; i-- {
+			if err := rollbackItem(ctx, tx, batch.Items[i]); err != nil {
+				return err
+			}
+		}
+		if err := tx.MarkImportBatchRolledBack(ctx, batchID); err != nil {
+			return err
+		}
+		batch, err = tx.GetImportBatch(ctx, batchID)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("Import batch rolled back", "batch", batchID, "created", batch.CreatedCount, "updated", batch.UpdatedCount)
+	return batch, nil
+}
+
+// rollbackItem removes the product item created or restores the product
+// item updated, provided it is still as the import left it.
+func rollbackItem(ctx context.Context, tx repositories.ProductStore, item models.ImportBatchItem) error {
+	product, err := tx.GetByID(ctx, item.ProductID)
+	if apperrors.CodeOf(err) == apperrors.CodeNotFound {
+		return apperrors.Conflict("product %d was deleted after the import", item.ProductID)
+	}
+	if err != nil {
+		return err
+	}
+	if product.Version != item.Version {
+		return apperrors.Conflict("product %d was changed after the import (imported version %d, current version %d)",
+			item.ProductID, item.Version, product.Version)
+	}
+
+	if item.Before == nil {
+		if err := tx.Delete(ctx, product.ID, product.Version); err != nil {
+			return err
+		}
+		return tx.Purge(ctx, product.ID)
+	}
+	_, err = tx.Update(ctx, product.ID, restoreUpdate(item.Before, product.Version))
+	return err
+}
+
+// restoreUpdate returns the update that sets every field of a product at
+// version back to before.
+func restoreUpdate(before *models.Product, version int) dto.UpdateProductDTO {
+	update := dto.UpdateProductDTO{Version: version, Name: &before.Name, Price: &before.Price, Stock: &before.Stock}
+	restore := func(field string, value *string, target **string) {
+		if value == nil {
+			update.Clear = append(update.Clear, field)
+		} else {
+			*target = value
+		}
+	}
+	restore(dto.FieldSKU, before.SKU, &update.SKU)
+	restore(dto.FieldCategory, before.Category, &update.Category)
+	restore(dto.FieldDescription, before.Description, &update.Description)
+	restore(dto.FieldImageURL, before.ImageURL, &update.ImageURL)
+	return update
+}
@@ -0,0 +1,130 @@
+package service
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"unicode"
+
+	"product-management-app/core/dto"
+)
+
+// columnAliases lists the headers recognized for each product field, in
+// English and Portuguese, in the form normalizeHeader produces.
+var columnAliases = map[string][]string{
+	dto.ImportFieldID:          {"id", "productid", "idproduto", "iddoproduto"},
+	dto.ImportFieldSKU:         {"sku", "code", "productcode", "itemcode", "reference", "ref", "codigo", "codigodoproduto", "referencia"},
+	dto.ImportFieldName:        {"name", "productname", "product", "nome", "nomedoproduto", "produto"},
+	dto.ImportFieldPrice:       {"price", "unitprice", "preco", "precounitario", "valor"},
+	dto.ImportFieldCategory:    {"category", "categoria"},
+	dto.ImportFieldStock:       {"stock", "quantity", "qty", "estoque", "quantidade"},
+	dto.ImportFieldDescription: {"description", "descricao"},
+	dto.ImportFieldImageURL:    {"imageurl", "image", "imagelink", "imagem", "urldaimagem", "urlimagem"},
+}
+
+// requiredColumns returns the fields an import file must have a column for
+// under options, which have their defaults applied. Creating products needs
+// a name and a price, and updating them needs the match key; an insert-only
+// file without the match key simply matches nothing.
+func requiredColumns(options dto.ImportOptions) []string {
+	var fields []string
+	if options.Mode != dto.ImportUpdateOnly {
+		fields = append(fields, dto.ImportFieldName, dto.ImportFieldPrice)
+	}
+	if key := string(options.MatchBy); options.Mode != dto.ImportInsertOnly && !slices.Contains(fields, key) {
+		fields = append(fields, key)
+	}
+	return fields
+}
+
+// headerFields maps every alias to its field.
+var headerFields = func() map[string]string {
+	fields := make(map[string]string)
+	for field, aliases := range columnAliases {
+		for _, alias := range aliases {
+			fields[alias] = field
+		}
+	}
+	return fields
+}()
+
+// accentReplacer drops the accents used in Portuguese headers.
+var accentReplacer = strings.NewReplacer(
+	"á", "a", "à", "a", "â", "a", "ã", "a",
+	"é", "e", "ê", "e",
+	"í", "i",
+	"ó", "o", "ô", "o", "õ", "o",
+	"ú", "u", "ü", "u",
+	"ç", "c",
+)
+
+// normalizeHeader lower-cases header, drops accents and keeps only letters
+// and digits, so "Image URL", "image_url" and "imageUrl" compare equal.
+func normalizeHeader(header string) string {
+	header = accentReplacer.Replace(strings.ToLower(strings.TrimPrefix(header, "\ufeff")))
+	return strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return r
+		}
+		return -1
+	}, header)
+}
+
+// importColumns holds the column index of each field present in an import
+// file.
+type importColumns map[string]int
+
+// resolveColumns matches the header row of an import file to product
+// fields. The column mapping of options takes precedence over the aliases,
+// and columns matching neither are ignored. It reports a missing required
+// column or two columns holding the same field as errors on row 1.
+func resolveColumns(header []string, options dto.ImportOptions) (importColumns, []dto.ImportError) {
+	userFields := make(map[string]string, len(options.ColumnMapping))
+	for name, field := range options.ColumnMapping {
+		userFields[normalizeHeader(name)] = field
+	}
+
+	columns := make(importColumns)
+	var errs []dto.ImportError
+	for i, name := range header {
+		normalized := normalizeHeader(name)
+		field, ok := userFields[normalized]
+		if !ok {
+			field = headerFields[normalized]
+		}
+		if field == "" {
+			continue
+		}
+		if previous, ok := columns[field]; ok {
+			errs = append(errs, dto.ImportError{
+				Row:     1,
+				Field:   field,
+				Message: fmt.Sprintf("Columns %q and %q both hold %s", header[previous], name, field),
+				Value:   name,
+			})
+			continue
+		}
+		columns[field] = i
+	}
+
+	for _, field := range requiredColumns(options) {
+		if _, ok := columns[field]; !ok {
+			errs = append(errs, dto.ImportError{
+				Row:     1,
+				Field:   field,
+				Message: fmt.Sprintf("Missing required column %s (expected a header such as %q)", field, columnAliases[field][0]),
+			})
+		}
+	}
+	return columns, errs
+}
+
+// value returns the trimmed cell of record holding field, or "" when the
+// file has no such column or the row is shorter.
+func (c importColumns) value(record []string, field string) string {
+	i, ok := c[field]
+	if !ok || i >= len(record) {
+		return ""
+	}
+	return strings.TrimSpace(record[i])
+}
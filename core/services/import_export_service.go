@@ -4,32 +4,56 @@ import (
 	"bytes"
 	"context"
 	"encoding/csv"
+	"errors"
 	"fmt"
+	"io"
+	"log/slog"
+	"os"
 	"strconv"
 	"strings"
+	"sync"
 
 	"product-management-app/core/dto"
+	apperrors "product-management-app/core/errors"
 	"product-management-app/core/models"
 	"product-management-app/core/repositories"
 
-	"github.com/wailsapp/wails/v2/pkg/runtime"
 	"github.com/xuri/excelize/v2"
 )
 
 type ImportExportService struct {
-	productRepo *repositories.ProductRepository
-	ctx         context.Context
+	store  repositories.ProductStore
+	logger *slog.Logger
+	emit   EventEmitter
+
+	previewsMu sync.Mutex
+	// previews holds the plans of dry-run imports by preview ID.
+	previews map[string]*importPreview
+
+	jobsMu    sync.Mutex
+	nextJobID int
+	// jobs holds the running imports by job ID.
+	jobs map[string]*importJob
 }
 
-func NewImportExportService(ctx context.Context, productRepo *repositories.ProductRepository) *ImportExportService {
+// NewImportExportService returns a service importing into and exporting
+// from store. Import progress events go to emit, which may be nil.
+func NewImportExportService(logger *slog.Logger, emit EventEmitter, store repositories.ProductStore) *ImportExportService {
 	return &ImportExportService{
-		productRepo: productRepo,
-		ctx:         ctx,
+		store:    store,
+		logger:   logger,
+		emit:     emit,
+		previews: make(map[string]*importPreview),
+		jobs:     make(map[string]*importJob),
 	}
 }
 
-func (s *ImportExportService) ExportToCSV(request dto.ExportRequest) ([]byte, error) {
-	products, err := s.getProductsForExport(request)
+// exportHeaders are the column headers of exported files. The importer
+// reads every column but the timestamps back.
+var exportHeaders = []string{"ID", "SKU", "Name", "Price", "Category", "Stock", "Description", "Image URL", "Created At", "Updated At"}
+
+func (s *ImportExportService) ExportToCSV(ctx context.Context, request dto.ExportRequest) ([]byte, error) {
+	products, err := s.getProductsForExport(ctx, request)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get products for export: %w", err)
 	}
@@ -37,17 +61,20 @@ func (s *ImportExportService) ExportToCSV(request dto.ExportRequest) ([]byte, er
 	var buf bytes.Buffer
 	writer := csv.NewWriter(&buf)
 
-	headers := []string{"ID", "Name", "Price", "Category", "Stock", "Description", "Image URL", "Created At", "Updated At"}
-	if err := writer.Write(headers); err != nil {
+	if err := writer.Write(exportHeaders); err != nil {
 		return nil, fmt.Errorf("failed to write CSV header: %w", err)
 	}
 
 	for _, product := range products {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
 		exportDTO := dto.NewProductExportDTO(product)
 		record := []string{
 			strconv.Itoa(exportDTO.ID),
+			exportDTO.SKU,
 			exportDTO.Name,
-			strconv.FormatFloat(exportDTO.Price, 'f', 2, 64),
+			exportDTO.Price.String(),
 			exportDTO.Category,
 			strconv.Itoa(exportDTO.Stock),
 			exportDTO.Description,
@@ -65,12 +92,12 @@ func (s *ImportExportService) ExportToCSV(request dto.ExportRequest) ([]byte, er
 		return nil, fmt.Errorf("CSV writer error: %w", err)
 	}
 
-	runtime.LogInfo(s.ctx, fmt.Sprintf("Exported %d products to CSV", len(products)))
+	s.logger.Info("Exported products to CSV", "count", len(products))
 	return buf.Bytes(), nil
 }
 
-func (s *ImportExportService) ExportToXLSX(request dto.ExportRequest) ([]byte, error) {
-	products, err := s.getProductsForExport(request)
+func (s *ImportExportService) ExportToXLSX(ctx context.Context, request dto.ExportRequest) ([]byte, error) {
+	products, err := s.getProductsForExport(ctx, request)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get products for export: %w", err)
 	}
@@ -78,7 +105,7 @@ func (s *ImportExportService) ExportToXLSX(request dto.ExportRequest) ([]byte, e
 	f := excelize.NewFile()
 	defer func() {
 		if err := f.Close(); err != nil {
-			runtime.LogError(s.ctx, fmt.Sprintf("Failed to close XLSX file: %v", err))
+			s.logger.Error("Failed to close XLSX file", "error", err)
 		}
 	}()
 
@@ -88,50 +115,47 @@ func (s *ImportExportService) ExportToXLSX(request dto.ExportRequest) ([]byte, e
 		return nil, fmt.Errorf("failed to create sheet: %w", err)
 	}
 
-	headers := []string{"ID", "Name", "Price", "Category", "Stock", "Description", "Image URL", "Created At", "Updated At"}
-	for i, header := range headers {
-		cell := fmt.Sprintf("%s1", string(rune('A'+i)))
+	for i, header := range exportHeaders {
+		cell, err := excelize.CoordinatesToCellName(i+1, 1)
+		if err != nil {
+			return nil, fmt.Errorf("failed to name header cell: %w", err)
+		}
 		if err := f.SetCellValue(sheetName, cell, header); err != nil {
 			return nil, fmt.Errorf("failed to set header cell %s: %w", cell, err)
 		}
 	}
 
 	for rowIndex, product := range products {
-		exportDTO := dto.NewProductExportDTO(product)
-		row := rowIndex + 2
-
-		if err := f.SetCellValue(sheetName, fmt.Sprintf("A%d", row), exportDTO.ID); err != nil {
-			return nil, fmt.Errorf("failed to set cell A%d: %w", row, err)
-		}
-		if err := f.SetCellValue(sheetName, fmt.Sprintf("B%d", row), exportDTO.Name); err != nil {
-			return nil, fmt.Errorf("failed to set cell B%d: %w", row, err)
-		}
-		if err := f.SetCellValue(sheetName, fmt.Sprintf("C%d", row), exportDTO.Price); err != nil {
-			return nil, fmt.Errorf("failed to set cell C%d: %w", row, err)
-		}
-		if err := f.SetCellValue(sheetName, fmt.Sprintf("D%d", row), exportDTO.Category); err != nil {
-			return nil, fmt.Errorf("failed to set cell D%d: %w", row, err)
-		}
-		if err := f.SetCellValue(sheetName, fmt.Sprintf("E%d", row), exportDTO.Stock); err != nil {
-			return nil, fmt.Errorf("failed to set cell E%d: %w", row, err)
-		}
-		if err := f.SetCellValue(sheetName, fmt.Sprintf("F%d", row), exportDTO.Description); err != nil {
-			return nil, fmt.Errorf("failed to set cell F%d: %w", row, err)
-		}
-		if err := f.SetCellValue(sheetName, fmt.Sprintf("G%d", row), exportDTO.ImageURL); err != nil {
-			return nil, fmt.Errorf("failed to set cell G%d: %w", row, err)
+		if err := ctx.Err(); err != nil {
+			return nil, err
 		}
-		if err := f.SetCellValue(sheetName, fmt.Sprintf("H%d", row), exportDTO.CreatedAt); err != nil {
-			return nil, fmt.Errorf("failed to set cell H%d: %w", row, err)
+		exportDTO := dto.NewProductExportDTO(product)
+		values := []interface{}{
+			exportDTO.ID,
+			exportDTO.SKU,
+			exportDTO.Name,
+			exportDTO.Price.Float64(),
+			exportDTO.Category,
+			exportDTO.Stock,
+			exportDTO.Description,
+			exportDTO.ImageURL,
+			exportDTO.CreatedAt,
+			exportDTO.UpdatedAt,
 		}
-		if err := f.SetCellValue(sheetName, fmt.Sprintf("I%d", row), exportDTO.UpdatedAt); err != nil {
-			return nil, fmt.Errorf("failed to set cell I%d: %w", row, err)
+		for col, value := range values {
+			cell, err := excelize.CoordinatesToCellName(col+1, rowIndex+2)
+			if err != nil {
+				return nil, fmt.Errorf("failed to name cell: %w", err)
+			}
+			if err := f.SetCellValue(sheetName, cell, value); err != nil {
+				return nil, fmt.Errorf("failed to set cell %s: %w", cell, err)
+			}
 		}
 	}
 
 	f.SetActiveSheet(index)
 	if err := f.DeleteSheet("Sheet1"); err != nil {
-		runtime.LogWarning(s.ctx, fmt.Sprintf("Failed to delete default sheet: %v", err))
+		s.logger.Warn("Failed to delete default sheet", "error", err)
 	}
 
 	var buf bytes.Buffer
@@ -139,64 +163,71 @@ func (s *ImportExportService) ExportToXLSX(request dto.ExportRequest) ([]byte, e
 		return nil, fmt.Errorf("failed to write XLSX: %w", err)
 	}
 
-	runtime.LogInfo(s.ctx, fmt.Sprintf("Exported %d products to XLSX", len(products)))
+	s.logger.Info("Exported products to XLSX", "count", len(products))
 	return buf.Bytes(), nil
 }
 
-func (s *ImportExportService) ImportFromCSV(data []byte) (*dto.ImportResult, error) {
-	reader := csv.NewReader(bytes.NewReader(data))
-	records, err := reader.ReadAll()
+// ImportFromCSV creates or updates a product for every row of the CSV file
+// at path, as options.Mode allows. Columns are matched to fields by their header,
+// see resolveColumns. With options.DryRun it writes nothing and returns a
+// preview that CommitImport applies, and with options.Atomic it writes
+// nothing unless every row succeeds. The written products are recorded as a
+// batch that RollbackImport undoes.
+//
+// The file is read as it is imported rather than loaded whole. Rows are
+// read and written in chunks of importChunkSize, each in its own
+// transaction, with an ImportProgressEvent after every chunk. CancelImport
+// stops the import.
+func (s *ImportExportService) ImportFromCSV(ctx context.Context, path string, options dto.ImportOptions) (*dto.ImportResult, error) {
+	if err := options.Validate(); err != nil {
+		return nil, err
+	}
+
+	file, err := os.Open(path)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read CSV: %w", err)
+		return nil, fmt.Errorf("failed to read CSV file: %w", err)
+	}
+	defer func() {
+		if err := file.Close(); err != nil {
+			s.logger.Error("Failed to close CSV file", "error", err)
+		}
+	}()
+	records, err := newCSVRecords(file)
+	if err != nil {
+		return nil, err
 	}
 
-	if len(records) < 2 {
+	header, first, err := readHeader(records)
+	if err == io.EOF {
 		return &dto.ImportResult{
 			SuccessCount: 0,
 			ErrorCount:   1,
 			Errors: []dto.ImportError{
-				{Row: 0, Message: "Arquivo CSV está vazio ou contém apenas cabeçalhos"},
+				{Row: 0, Message: "CSV file is empty or contains only headers"},
 			},
 		}, nil
 	}
-
-	result := &dto.ImportResult{
-		ImportedItems: []*models.Product{},
-		Errors:        []dto.ImportError{},
+	if err != nil {
+		return nil, err
 	}
 
-	for i, record := range records[1:] {
-		rowNum := i + 2
-
-		productDTO, errs := s.parseCSVRecord(record, rowNum)
-		if len(errs) > 0 {
-			result.Errors = append(result.Errors, errs...)
-			result.ErrorCount++
-			continue
-		}
-
-		createDTO := productDTO.ToCreateProductDTO()
-		product, err := s.productRepo.Create(createDTO)
-		if err != nil {
-			result.Errors = append(result.Errors, dto.ImportError{
-				Row:     rowNum,
-				Message: fmt.Sprintf("Error creating product: %v", err),
-			})
-			result.ErrorCount++
-			continue
-		}
+	return s.importRecords(ctx, importSourceCSV, header, &recordStream{records: records, pending: first}, options)
+}
 
-		result.ImportedItems = append(result.ImportedItems, product)
-		result.SuccessCount++
+// ImportFromXLSX creates or updates a product for every row of the first
+// sheet of the XLSX file at path, as options.Mode allows. Columns are matched to
+// fields by their header, see resolveColumns. DryRun, Atomic, the recorded
+// batch, chunks and progress work as for ImportFromCSV.
+func (s *ImportExportService) ImportFromXLSX(ctx context.Context, path string, options dto.ImportOptions) (*dto.ImportResult, error) {
+	if err := options.Validate(); err != nil {
+		return nil, err
 	}
 
-	runtime.LogInfo(s.ctx, fmt.Sprintf("Import completed: %d success, %d errors", result.SuccessCount, result.ErrorCount))
-	return result, nil
-}
-
-func (s *ImportExportService) ImportFromXLSX(data []byte) (*dto.ImportResult, error) {
-	// Validate the data before attempting to open
-	if len(data) == 0 {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read XLSX file: %w", err)
+	}
+	if info.Size() == 0 {
 		return &dto.ImportResult{
 			SuccessCount: 0,
 			ErrorCount:   1,
@@ -206,12 +237,11 @@ func (s *ImportExportService) ImportFromXLSX(data []byte) (*dto.ImportResult, er
 		}, nil
 	}
 
-	// Log the first few bytes for debugging
-	runtime.LogInfo(s.ctx, fmt.Sprintf("Opening XLSX file, size: %d bytes", len(data)))
+	s.logger.Info("Opening XLSX file", "path", path, "bytes", info.Size())
 
-	f, err := excelize.OpenReader(bytes.NewReader(data))
+	f, err := excelize.OpenFile(path)
 	if err != nil {
-		runtime.LogError(s.ctx, fmt.Sprintf("Failed to open XLSX: %v", err))
+		s.logger.Error("Failed to open XLSX", "error", err)
 		return &dto.ImportResult{
 			SuccessCount: 0,
 			ErrorCount:   1,
@@ -222,7 +252,7 @@ func (s *ImportExportService) ImportFromXLSX(data []byte) (*dto.ImportResult, er
 	}
 	defer func() {
 		if err := f.Close(); err != nil {
-			runtime.LogError(s.ctx, fmt.Sprintf("Failed to close XLSX file: %v", err))
+			s.logger.Error("Failed to close XLSX file", "error", err)
 		}
 	}()
 
@@ -237,13 +267,18 @@ func (s *ImportExportService) ImportFromXLSX(data []byte) (*dto.ImportResult, er
 		}, nil
 	}
 
-	sheetName := sheets[0]
-	rows, err := f.GetRows(sheetName)
+	records, err := openXLSXRecords(f, sheets[0])
 	if err != nil {
-		return nil, fmt.Errorf("failed to get rows: %w", err)
+		return nil, err
 	}
+	defer func() {
+		if err := records.Close(); err != nil {
+			s.logger.Error("Failed to close XLSX rows", "error", err)
+		}
+	}()
 
-	if len(rows) < 2 {
+	header, first, err := readHeader(records)
+	if err == io.EOF {
 		return &dto.ImportResult{
 			SuccessCount: 0,
 			ErrorCount:   1,
@@ -252,56 +287,82 @@ func (s *ImportExportService) ImportFromXLSX(data []byte) (*dto.ImportResult, er
 			},
 		}, nil
 	}
-
-	result := &dto.ImportResult{
-		ImportedItems: []*models.Product{},
-		Errors:        []dto.ImportError{},
+	if err != nil {
+		return nil, err
 	}
 
-	for i, row := range rows[1:] {
-		rowNum := i + 2
+	return s.importRecords(ctx, importSourceXLSX, header, &recordStream{records: records, pending: first}, options)
+}
 
-		productDTO, errs := s.parseXLSXRow(row, rowNum)
-		if len(errs) > 0 {
-			result.Errors = append(result.Errors, errs...)
-			result.ErrorCount++
-			continue
-		}
+// readHeader reads the header and the row after it, which it returns so
+// that it can be put back in front of the stream. It returns io.EOF for a
+// file with fewer than two rows.
+func readHeader(records recordReader) (header, first []string, err error) {
+	if header, err = records.Read(); err != nil {
+		return nil, nil, err
+	}
+	if first, err = records.Read(); err != nil {
+		return nil, nil, err
+	}
+	// An empty row reads as nil, which the stream takes for no pending row.
+	if first == nil {
+		first = []string{}
+	}
+	return header, first, nil
+}
 
-		createDTO := productDTO.ToCreateProductDTO()
-		product, err := s.productRepo.Create(createDTO)
-		if err != nil {
-			result.Errors = append(result.Errors, dto.ImportError{
-				Row:     rowNum,
-				Message: fmt.Sprintf("Error creating product: %v", err),
-			})
-			result.ErrorCount++
-			continue
-		}
+// importRecords applies every row stream reads after header under options,
+// or previews them for a dry run. A file whose header lacks a required
+// column imports nothing.
+func (s *ImportExportService) importRecords(ctx context.Context, source string, header []string, stream *recordStream, options dto.ImportOptions) (*dto.ImportResult, error) {
+	options = options.WithDefaults()
+	columns, headerErrs := resolveColumns(header, options)
+	if len(headerErrs) > 0 {
+		return &dto.ImportResult{ErrorCount: len(headerErrs), Errors: headerErrs}, nil
+	}
+	stream.columns, stream.options, stream.rowNum = columns, options, 1
+	if options.DryRun {
+		return s.previewRecords(ctx, source, stream)
+	}
 
-		result.ImportedItems = append(result.ImportedItems, product)
-		result.SuccessCount++
+	result := newImportResult()
+	if err := s.runImport(ctx, source, options, result, stream); err != nil {
+		return nil, err
 	}
 
-	runtime.LogInfo(s.ctx, fmt.Sprintf("Import completed: %d success, %d errors", result.SuccessCount, result.ErrorCount))
+	s.logger.Info("Import completed", "mode", options.Mode, "batch", result.BatchID, "created", result.CreatedCount,
+		"updated", result.UpdatedCount, "skipped", result.SkippedCount, "errors", result.ErrorCount, "rolledBack", result.RolledBack)
 	return result, nil
 }
 
-func (s *ImportExportService) getProductsForExport(request dto.ExportRequest) ([]*models.Product, error) {
+// getProductsForExport returns the products request selects. Exporting
+// every product reads them a page of dto.MaxPageSize at a time, so no
+// product is left out however large the catalog.
+func (s *ImportExportService) getProductsForExport(ctx context.Context, request dto.ExportRequest) ([]*models.Product, error) {
 	if request.IncludeAll {
-		pagination := dto.PaginationDTO{Page: 1, PageSize: 10000}
-		response, err := s.productRepo.GetAll(pagination)
-		if err != nil {
-			return nil, err
+		pagination := dto.PaginationDTO{Page: 1, PageSize: dto.MaxPageSize}
+		var products []*models.Product
+		for {
+			response, err := s.store.GetAll(ctx, pagination)
+			if err != nil {
+				return nil, err
+			}
+			products = append(products, response.Products...)
+			if pagination.Page >= response.TotalPages {
+				return products, nil
+			}
+			pagination.Page++
 		}
-		return response.Products, nil
 	}
 
 	var products []*models.Product
 	for _, id := range request.ProductIDs {
-		product, err := s.productRepo.GetByID(id)
+		product, err := s.store.GetByID(ctx, id)
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return nil, ctxErr
+		}
 		if err != nil {
-			runtime.LogWarning(s.ctx, fmt.Sprintf("Failed to get product with ID %d: %v", id, err))
+			s.logger.Warn("Failed to get product", "id", id, "error", err)
 			continue
 		}
 		products = append(products, product)
@@ -310,84 +371,35 @@ func (s *ImportExportService) getProductsForExport(request dto.ExportRequest) ([
 	return products, nil
 }
 
-func (s *ImportExportService) parseCSVRecord(record []string, rowNum int) (*dto.ProductImportDTO, []dto.ImportError) {
-	var errors []dto.ImportError
-
-	if len(record) < 6 {
-		errors = append(errors, dto.ImportError{
-			Row:     rowNum,
-			Message: "Incomplete record, expected at least 6 fields",
-		})
-		return nil, errors
-	}
-
-	name := strings.TrimSpace(record[0])
-	if name == "" {
-		errors = append(errors, dto.ImportError{
-			Row:     rowNum,
-			Field:   "name",
-			Message: "Name is required",
-			Value:   record[0],
-		})
+// isBlankRecord reports whether every cell of record is empty, as in the
+// trailing rows spreadsheets often leave behind.
+func isBlankRecord(record []string) bool {
+	for _, cell := range record {
+		if strings.TrimSpace(cell) != "" {
+			return false
+		}
 	}
+	return true
+}
 
-	price, err := strconv.ParseFloat(strings.TrimSpace(record[1]), 64)
-	if err != nil {
-		errors = append(errors, dto.ImportError{
-			Row:     rowNum,
-			Field:   "price",
-			Message: "Price must be a valid number",
-			Value:   record[1],
-		})
-	} else if price < 0 {
-		errors = append(errors, dto.ImportError{
-			Row:     rowNum,
-			Field:   "price",
-			Message: "Price must be positive",
-			Value:   record[1],
-		})
+// rowErrors turns the error from writing the product of row rowNum into
+// import errors. A validation error yields one import error per invalid
+// field, carrying the row's value for it; any other error yields one import
+// error whose message starts with action.
+func rowErrors(value func(field string) string, rowNum int, action string, err error) []dto.ImportError {
+	var validation *apperrors.ValidationError
+	if !errors.As(err, &validation) {
+		return []dto.ImportError{{Row: rowNum, Message: fmt.Sprintf("%s: %v", action, err)}}
 	}
 
-	category := strings.TrimSpace(record[2])
-
-	stock, err := strconv.Atoi(strings.TrimSpace(record[3]))
-	if err != nil {
-		errors = append(errors, dto.ImportError{
-			Row:     rowNum,
-			Field:   "stock",
-			Message: "Stock must be a valid integer",
-			Value:   record[3],
-		})
-	} else if stock < 0 {
-		errors = append(errors, dto.ImportError{
+	importErrors := make([]dto.ImportError, 0, len(validation.Fields))
+	for _, field := range validation.Fields {
+		importErrors = append(importErrors, dto.ImportError{
 			Row:     rowNum,
-			Field:   "stock",
-			Message: "Stock must be non-negative",
-			Value:   record[3],
+			Field:   field.Field,
+			Message: field.Message,
+			Value:   value(field.Field),
 		})
 	}
-
-	description := ""
-	if len(record) > 4 {
-		description = strings.TrimSpace(record[4])
-	}
-
-	imageURL := ""
-	if len(record) > 5 {
-		imageURL = strings.TrimSpace(record[5])
-	}
-
-	if len(errors) > 0 {
-		return nil, errors
-	}
-
-	return dto.NewProductImportDTO(name, price, category, stock, description, imageURL), nil
-}
-
-func (s *ImportExportService) parseXLSXRow(row []string, rowNum int) (*dto.ProductImportDTO, []dto.ImportError) {
-	record := make([]string, 6)
-	for i := 0; i < len(record) && i < len(row); i++ {
-		record[i] = row[i]
-	}
-	return s.parseCSVRecord(record, rowNum)
+	return importErrors
 }
@@ -0,0 +1,361 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"product-management-app/core/dto"
+	apperrors "product-management-app/core/errors"
+	"product-management-app/core/models"
+	"product-management-app/core/repositories"
+)
+
+const (
+	// previewTTL is how long the plan of a dry-run import can be committed.
+	previewTTL = 30 * time.Minute
+	// maxPreviewRows caps ImportPreview.Rows, so the preview of a large file
+	// stays small enough to send to the frontend. The whole plan is still
+	// committed.
+	maxPreviewRows = 1000
+)
+
+// errPreviewDone rolls back the transaction a dry run is planned in.
+var errPreviewDone = errors.New("import preview done")
+
+// plannedRow is what an import does with one row: its status and the
+// create or update that carries it out. It does not keep the row's cells,
+// since a preview stores the plan of every row until it is committed.
+type plannedRow struct {
+	num    int
+	name   string
+	status dto.ImportRowStatus
+	// match is the product the row matched, as it was before the row.
+	match  *models.Product
+	create *dto.CreateProductDTO
+	update *dto.UpdateProductDTO
+	reason string
+	errors []dto.ImportError
+	// createdID is the ID of the product the row created.
+	createdID int
+	// written is the product as the row's write left it.
+	written *models.Product
+}
+
+// importPreview is the stored plan of a dry-run import.
+type importPreview struct {
+	source  string
+	options dto.ImportOptions
+	rows    []*plannedRow
+	expires time.Time
+}
+
+// planRecord decides what to do with record, the row numbered rowNum,
+// without writing to store.
+func planRecord(ctx context.Context, store repositories.ProductStore, columns importColumns, record []string, rowNum int, options dto.ImportOptions) *plannedRow {
+	plan := &plannedRow{num: rowNum, name: columns.value(record, dto.ImportFieldName)}
+	row, errs := parseRecord(columns, record, rowNum)
+	if len(errs) > 0 {
+		return plan.fail(errs...)
+	}
+	match, err := findMatch(ctx, store, row, options.MatchBy)
+	if err != nil {
+		cell := func(field string) string { return columns.value(record, field) }
+		return plan.fail(rowErrors(cell, rowNum, "Error matching product", err)...)
+	}
+	plan.match = match
+	if match != nil && plan.name == "" {
+		plan.name = match.Name
+	}
+
+	switch {
+	case match == nil && options.Mode == dto.ImportUpdateOnly:
+		plan.status = dto.ImportRowSkipped
+		plan.reason = fmt.Sprintf("No product matches the row's %s", options.MatchBy)
+	case match == nil:
+		create, rowErr := row.createDTO()
+		if rowErr != nil {
+			return plan.fail(*rowErr)
+		}
+		plan.status, plan.create = dto.ImportRowNew, &create
+	case options.Mode == dto.ImportInsertOnly:
+		plan.status = dto.ImportRowSkipped
+		plan.reason = fmt.Sprintf("Product %d already has this %s", match.ID, options.MatchBy)
+	default:
+		update := mergeUpdate(match, row, options)
+		if update.IsEmpty() {
+			plan.status = dto.ImportRowUnchanged
+			plan.reason = fmt.Sprintf("Product %d is already up to date", match.ID)
+		} else {
+			plan.status, plan.update = dto.ImportRowChanged, &update
+		}
+	}
+	return plan
+}
+
+// fail marks the row invalid because of errs.
+func (p *plannedRow) fail(errs ...dto.ImportError) *plannedRow {
+	p.status = dto.ImportRowInvalid
+	p.errors = errs
+	return p
+}
+
+func (p *plannedRow) matchID() int {
+	if p.match == nil {
+		return 0
+	}
+	return p.match.ID
+}
+
+// value returns the value the row writes to field, which the errors of a
+// failed write report.
+func (p *plannedRow) value(field string) string {
+	var changes []dto.ImportFieldChange
+	switch {
+	case p.create != nil:
+		changes = createChanges(*p.create)
+	case p.update != nil:
+		changes = updateChanges(p.match, *p.update)
+	}
+	for _, change := range changes {
+		if change.Field == field {
+			return change.After
+		}
+	}
+	return ""
+}
+
+// applyPlan carries out plan against store and records the outcome in
+// result. A changed row updates the product targetID. A write that fails
+// turns the row invalid.
+func applyPlan(ctx context.Context, store repositories.ProductStore, plan *plannedRow, targetID int, result *dto.ImportResult) {
+	plan.written = nil
+	switch plan.status {
+	case dto.ImportRowNew:
+		product, err := store.Create(ctx, *plan.create)
+		if err != nil {
+			plan.fail(rowErrors(plan.value, plan.num, "Error creating product", err)...)
+			break
+		}
+		plan.createdID, plan.written = product.ID, product
+		addImportedItem(result, product)
+		result.CreatedCount++
+		result.SuccessCount++
+		return
+	case dto.ImportRowChanged:
+		product, err := store.Update(ctx, targetID, *plan.update)
+		if err != nil {
+			plan.fail(rowErrors(plan.value, plan.num, fmt.Sprintf("Error updating product %d", targetID), err)...)
+			break
+		}
+		plan.written = product
+		addImportedItem(result, product)
+		result.UpdatedCount++
+		result.SuccessCount++
+		return
+	case dto.ImportRowUnchanged, dto.ImportRowSkipped:
+		result.Skipped = append(result.Skipped, dto.ImportSkip{Row: plan.num, ProductID: plan.matchID(), Reason: plan.reason})
+		result.SkippedCount++
+		return
+	}
+
+	// The row was invalid from the start or its write failed above.
+	result.Errors = append(result.Errors, plan.errors...)
+	result.ErrorCount++
+}
+
+// previewRecords applies the rows of stream one chunk per transaction, as
+// applyChunks does, but rolls every transaction back, so every row is
+// validated exactly as a real import would and other writers are only held
+// back for a chunk at a time. A row therefore sees the writes of the earlier
+// rows of its chunk but not of earlier chunks. The plan is kept for
+// CommitImport. Progress is reported as for an import.
+func (s *ImportExportService) previewRecords(ctx context.Context, source string, stream *recordStream) (*dto.ImportResult, error) {
+	ctx, job := s.startImport(ctx, source)
+	result := newImportResult()
+	var plans []*plannedRow
+	for done := false; !done; {
+		err := s.store.WithTx(ctx, func(tx repositories.ProductStore) error {
+			chunk, chunkDone, err := stream.apply(ctx, tx, importChunkSize, result)
+			if err != nil {
+				return err
+			}
+			plans, done = append(plans, chunk...), chunkDone
+			return errPreviewDone
+		})
+		if !errors.Is(err, errPreviewDone) {
+			job.finish(err)
+			return nil, err
+		}
+		job.report(stream, result)
+	}
+	job.finish(nil)
+
+	preview := &importPreview{source: source, options: stream.options, rows: plans, expires: time.Now().Add(previewTTL)}
+	id, err := s.savePreview(preview)
+	if err != nil {
+		return nil, err
+	}
+	counts := make(map[dto.ImportRowStatus]int)
+	rows := make([]dto.ImportPreviewRow, 0, min(len(plans), maxPreviewRows))
+	for _, plan := range plans {
+		counts[plan.status]++
+		if len(rows) < maxPreviewRows {
+			rows = append(rows, plan.previewRow())
+		}
+	}
+
+	result.ImportedItems = nil
+	result.Preview = &dto.ImportPreview{
+		ID:        id,
+		ExpiresAt: preview.expires.UTC().Format(time.RFC3339),
+		Rows:      rows,
+		TotalRows: len(plans),
+		Counts:    counts,
+	}
+	s.logger.Info("Import previewed", "preview", id, "new", result.CreatedCount, "changed", result.UpdatedCount,
+		"skipped", result.SkippedCount, "invalid", result.ErrorCount)
+	return result, nil
+}
+
+// CommitImport applies the plan of a dry-run import as it was previewed,
+// atomically if the import options asked for it. A row whose product
+// changed since the preview fails with a version conflict, and rows the
+// preview found invalid are reported again without being retried. A preview
+// can be committed once.
+func (s *ImportExportService) CommitImport(ctx context.Context, previewID string) (*dto.ImportResult, error) {
+	preview, err := s.takePreview(previewID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := newImportResult()
+	replay := &planReplay{rows: preview.rows, createdIDs: make(map[int]int)}
+	if err := s.runImport(ctx, preview.source, preview.options, result, replay); err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("Import preview committed", "preview", previewID, "batch", result.BatchID, "created", result.CreatedCount,
+		"updated", result.UpdatedCount, "skipped", result.SkippedCount, "errors", result.ErrorCount, "rolledBack", result.RolledBack)
+	return result, nil
+}
+
+// DiscardPreview forgets the plan of a dry-run import. Unknown IDs are
+// ignored.
+func (s *ImportExportService) DiscardPreview(previewID string) {
+	s.previewsMu.Lock()
+	defer s.previewsMu.Unlock()
+	delete(s.previews, previewID)
+}
+
+// savePreview stores preview under a new random ID, dropping expired ones.
+func (s *ImportExportService) savePreview(preview *importPreview) (string, error) {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("failed to generate preview ID: %w", err)
+	}
+	id := hex.EncodeToString(buf)
+
+	s.previewsMu.Lock()
+	defer s.previewsMu.Unlock()
+	now := time.Now()
+	for key, stored := range s.previews {
+		if now.After(stored.expires) {
+			delete(s.previews, key)
+		}
+	}
+	s.previews[id] = preview
+	return id, nil
+}
+
+// takePreview removes and returns the preview with the given ID.
+func (s *ImportExportService) takePreview(previewID string) (*importPreview, error) {
+	s.previewsMu.Lock()
+	defer s.previewsMu.Unlock()
+	preview, ok := s.previews[previewID]
+	delete(s.previews, previewID)
+	if !ok || time.Now().After(preview.expires) {
+		return nil, apperrors.NotFound("import preview", previewID)
+	}
+	return preview, nil
+}
+
+// previewRow describes the plan for the frontend.
+func (p *plannedRow) previewRow() dto.ImportPreviewRow {
+	row := dto.ImportPreviewRow{
+		Row:       p.num,
+		Status:    p.status,
+		ProductID: p.matchID(),
+		Name:      p.name,
+		Errors:    p.errors,
+		Reason:    p.reason,
+	}
+	switch p.status {
+	case dto.ImportRowNew:
+		row.Changes = createChanges(*p.create)
+	case dto.ImportRowChanged:
+		row.Changes = updateChanges(p.match, *p.update)
+	}
+	return row
+}
+
+// createChanges lists the fields a new product sets.
+func createChanges(product dto.CreateProductDTO) []dto.ImportFieldChange {
+	changes := []dto.ImportFieldChange{{Field: dto.ImportFieldName, After: product.Name}}
+	add := func(field, value string) {
+		if value != "" {
+			changes = append(changes, dto.ImportFieldChange{Field: field, After: value})
+		}
+	}
+	add(dto.ImportFieldSKU, product.SKU)
+	add(dto.ImportFieldPrice, product.Price.String())
+	add(dto.ImportFieldCategory, product.Category)
+	add(dto.ImportFieldStock, strconv.Itoa(product.Stock))
+	add(dto.ImportFieldDescription, product.Description)
+	add(dto.ImportFieldImageURL, product.ImageURL)
+	return changes
+}
+
+// updateChanges lists the before and after value of every field update
+// sets on product.
+func updateChanges(product *models.Product, update dto.UpdateProductDTO) []dto.ImportFieldChange {
+	var changes []dto.ImportFieldChange
+	add := func(field, before string, after *string) {
+		if after != nil {
+			changes = append(changes, dto.ImportFieldChange{Field: field, Before: before, After: *after})
+		}
+	}
+	add(dto.ImportFieldName, product.Name, update.Name)
+	add(dto.ImportFieldSKU, textOf(product.SKU), update.SKU)
+	if update.Price != nil {
+		price := update.Price.String()
+		add(dto.ImportFieldPrice, product.Price.String(), &price)
+	}
+	add(dto.ImportFieldCategory, textOf(product.Category), update.Category)
+	if update.Stock != nil {
+		stock := strconv.Itoa(*update.Stock)
+		add(dto.ImportFieldStock, strconv.Itoa(product.Stock), &stock)
+	}
+	add(dto.ImportFieldDescription, textOf(product.Description), update.Description)
+	add(dto.ImportFieldImageURL, textOf(product.ImageURL), update.ImageURL)
+	return changes
+}
+
+func textOf(value *string) string {
+	if value == nil {
+		return ""
+	}
+	return *value
+}
+
+func newImportResult() *dto.ImportResult {
+	return &dto.ImportResult{
+		ImportedItems: []*models.Product{},
+		Errors:        []dto.ImportError{},
+		Skipped:       []dto.ImportSkip{},
+	}
+}
@@ -0,0 +1,184 @@
+package service
+
+import (
+	"context"
+	"strconv"
+
+	"product-management-app/core/dto"
+	apperrors "product-management-app/core/errors"
+	"product-management-app/core/models"
+	"product-management-app/core/money"
+	"product-management-app/core/repositories"
+)
+
+// importRow holds the parsed cells of one import row. An empty string or a
+// nil pointer stands for a blank cell or a column the file does not have.
+type importRow struct {
+	num         int
+	id          *int
+	sku         string
+	name        string
+	price       *money.Money
+	category    string
+	stock       *int
+	description string
+	imageURL    string
+}
+
+// parseRecord reads the cells of record, reporting every cell that cannot
+// be parsed. Range and format rules are left to the store's validator.
+func parseRecord(columns importColumns, record []string, rowNum int) (*importRow, []dto.ImportError) {
+	row := &importRow{
+		num:         rowNum,
+		sku:         columns.value(record, dto.ImportFieldSKU),
+		name:        columns.value(record, dto.ImportFieldName),
+		category:    columns.value(record, dto.ImportFieldCategory),
+		description: columns.value(record, dto.ImportFieldDescription),
+		imageURL:    columns.value(record, dto.ImportFieldImageURL),
+	}
+	var errors []dto.ImportError
+
+	if rawID := columns.value(record, dto.ImportFieldID); rawID != "" {
+		id, err := strconv.Atoi(rawID)
+		if err != nil {
+			errors = append(errors, dto.ImportError{
+				Row:     rowNum,
+				Field:   dto.ImportFieldID,
+				Message: "ID must be a valid integer",
+				Value:   rawID,
+			})
+		}
+		row.id = &id
+	}
+
+	if rawPrice := columns.value(record, dto.ImportFieldPrice); rawPrice != "" {
+		price, err := money.Parse(rawPrice, money.RoundNearest)
+		if err != nil {
+			errors = append(errors, dto.ImportError{
+				Row:     rowNum,
+				Field:   dto.ImportFieldPrice,
+				Message: "Price must be a valid number",
+				Value:   rawPrice,
+			})
+		}
+		row.price = &price
+	}
+
+	if rawStock := columns.value(record, dto.ImportFieldStock); rawStock != "" {
+		stock, err := strconv.Atoi(rawStock)
+		if err != nil {
+			errors = append(errors, dto.ImportError{
+				Row:     rowNum,
+				Field:   dto.ImportFieldStock,
+				Message: "Stock must be a valid integer",
+				Value:   rawStock,
+			})
+		}
+		row.stock = &stock
+	}
+
+	if len(errors) > 0 {
+		return nil, errors
+	}
+	return row, nil
+}
+
+// createDTO returns the product the row creates. The price is required; a
+// blank stock is zero.
+func (r *importRow) createDTO() (dto.CreateProductDTO, *dto.ImportError) {
+	if r.price == nil {
+		return dto.CreateProductDTO{}, &dto.ImportError{
+			Row:     r.num,
+			Field:   dto.ImportFieldPrice,
+			Message: "Price is required to create a product",
+		}
+	}
+	product := dto.NewProductImportDTO(r.name, *r.price, r.category, 0, r.description, r.imageURL).ToCreateProductDTO()
+	product.SKU = r.sku
+	if r.stock != nil {
+		product.Stock = *r.stock
+	}
+	return product, nil
+}
+
+// findMatch returns the product of store the row matches by key, or nil
+// when the row's key is blank or matches nothing.
+func findMatch(ctx context.Context, store repositories.ProductStore, row *importRow, key dto.ImportMatchKey) (*models.Product, error) {
+	var product *models.Product
+	var err error
+	switch key {
+	case dto.MatchByID:
+		if row.id == nil {
+			return nil, nil
+		}
+		product, err = store.GetByID(ctx, *row.id)
+	case dto.MatchBySKU:
+		if row.sku == "" {
+			return nil, nil
+		}
+		product, err = store.GetBySKU(ctx, row.sku)
+	case dto.MatchByName:
+		if row.name == "" {
+			return nil, nil
+		}
+		products, err := store.FindByName(ctx, row.name)
+		if err != nil {
+			return nil, err
+		}
+		if len(products) > 1 {
+			return nil, apperrors.Validation(dto.ImportFieldName, "name matches %d products: match by ID or SKU instead", len(products))
+		}
+		if len(products) == 1 {
+			product = products[0]
+		}
+	}
+	if apperrors.CodeOf(err) == apperrors.CodeNotFound {
+		return nil, nil
+	}
+	return product, err
+}
+
+// mergeUpdate returns the update that brings the row's non-blank cells into
+// product under the merge rules of options. The update is empty when the
+// rules leave nothing to change.
+func mergeUpdate(product *models.Product, row *importRow, options dto.ImportOptions) dto.UpdateProductDTO {
+	update := dto.UpdateProductDTO{Version: product.Version}
+	mergeText := func(field, cell string, stored *string) *string {
+		current := ""
+		if stored != nil {
+			current = *stored
+		}
+		if cell == "" || !mergeApplies(options.MergeRuleFor(field), current == "", current != cell) {
+			return nil
+		}
+		return &cell
+	}
+
+	update.Name = mergeText(dto.ImportFieldName, row.name, &product.Name)
+	update.SKU = mergeText(dto.ImportFieldSKU, row.sku, product.SKU)
+	update.Category = mergeText(dto.ImportFieldCategory, row.category, product.Category)
+	update.Description = mergeText(dto.ImportFieldDescription, row.description, product.Description)
+	update.ImageURL = mergeText(dto.ImportFieldImageURL, row.imageURL, product.ImageURL)
+	if row.price != nil && mergeApplies(options.MergeRuleFor(dto.ImportFieldPrice), product.Price == 0, product.Price != *row.price) {
+		update.Price = row.price
+	}
+	if row.stock != nil && mergeApplies(options.MergeRuleFor(dto.ImportFieldStock), product.Stock == 0, product.Stock != *row.stock) {
+		update.Stock = row.stock
+	}
+	return update
+}
+
+// mergeApplies reports whether a cell that differs from the stored value
+// replaces it under rule, given whether the stored value is blank.
+func mergeApplies(rule dto.MergeRule, storedBlank, differs bool) bool {
+	if !differs {
+		return false
+	}
+	switch rule {
+	case dto.MergeKeepExisting:
+		return false
+	case dto.MergeFillBlanks:
+		return storedBlank
+	}
+	return true
+}
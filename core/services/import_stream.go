@@ -0,0 +1,323 @@
+package service
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"time"
+
+	"product-management-app/core/dto"
+	apperrors "product-management-app/core/errors"
+	"product-management-app/core/models"
+	"product-management-app/core/repositories"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// ImportProgressEvent is emitted to the frontend with an ImportProgressDTO
+// when an import starts, after every chunk of rows and when it finishes.
+const ImportProgressEvent = "import:progress"
+
+const (
+	// importChunkSize is how many rows an import applies per transaction
+	// and between progress events.
+	importChunkSize = 500
+	// maxImportedItems caps ImportResult.ImportedItems, so the result of a
+	// large file stays small enough to send to the frontend.
+	maxImportedItems = 1000
+)
+
+// recordReader reads the rows of an import file one at a time.
+type recordReader interface {
+	// Read returns the next row, or io.EOF after the last one.
+	Read() ([]string, error)
+	// Progress returns the share of the file read so far, between 0 and 1.
+	Progress() float64
+}
+
+// csvRecords reads a CSV file row by row.
+type csvRecords struct {
+	reader *csv.Reader
+	size   int64
+}
+
+func newCSVRecords(file *os.File) (*csvRecords, error) {
+	info, err := file.Stat()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CSV file: %w", err)
+	}
+	reader := csv.NewReader(file)
+	// Rows may be shorter than the header; missing cells are empty.
+	reader.FieldsPerRecord = -1
+	return &csvRecords{reader: reader, size: info.Size()}, nil
+}
+
+func (c *csvRecords) Read() ([]string, error) {
+	record, err := c.reader.Read()
+	if err != nil && err != io.EOF {
+		return nil, fmt.Errorf("failed to read CSV: %w", err)
+	}
+	return record, err
+}
+
+func (c *csvRecords) Progress() float64 {
+	if c.size == 0 {
+		return 1
+	}
+	return float64(c.reader.InputOffset()) / float64(c.size)
+}
+
+// xlsxRecords reads a sheet of an XLSX file row by row. Rows between the
+// ones the sheet stores are read as empty.
+type xlsxRecords struct {
+	rows        *excelize.Rows
+	read, total int
+}
+
+// openXLSXRecords opens sheet for reading. It counts the rows first, which
+// only scans the sheet's XML, so that Progress is known.
+func openXLSXRecords(f *excelize.File, sheet string) (*xlsxRecords, error) {
+	counter, err := f.Rows(sheet)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read XLSX rows: %w", err)
+	}
+	total := 0
+	for counter.Next() {
+		total++
+	}
+	if err := counter.Close(); err != nil {
+		return nil, fmt.Errorf("failed to read XLSX rows: %w", err)
+	}
+
+	rows, err := f.Rows(sheet)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read XLSX rows: %w", err)
+	}
+	return &xlsxRecords{rows: rows, total: total}, nil
+}
+
+func (x *xlsxRecords) Read() ([]string, error) {
+	if !x.rows.Next() {
+		if err := x.rows.Error(); err != nil {
+			return nil, fmt.Errorf("failed to read XLSX row %d: %w", x.read+1, err)
+		}
+		return nil, io.EOF
+	}
+	x.read++
+	record, err := x.rows.Columns()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read XLSX row %d: %w", x.read, err)
+	}
+	return record, nil
+}
+
+func (x *xlsxRecords) Progress() float64 {
+	if x.total == 0 {
+		return 1
+	}
+	return float64(x.read) / float64(x.total)
+}
+
+func (x *xlsxRecords) Close() error {
+	return x.rows.Close()
+}
+
+// rowSource feeds the rows of an import to runImport in chunks.
+type rowSource interface {
+	// apply plans and applies up to limit more rows against store,
+	// recording their outcomes in result. It returns their plans and
+	// whether every row has been applied.
+	apply(ctx context.Context, store repositories.ProductStore, limit int, result *dto.ImportResult) ([]*plannedRow, bool, error)
+	// position returns the number of rows processed and the share of the
+	// import they make up, between 0 and 1.
+	position() (int, float64)
+}
+
+// recordStream applies the rows of an import file as they are read.
+type recordStream struct {
+	records recordReader
+	columns importColumns
+	options dto.ImportOptions
+	// rowNum is the number of the last row read; the header is row 1.
+	rowNum int
+	// pending is a row read ahead of the stream, returned first.
+	pending []string
+}
+
+func (r *recordStream) next() ([]string, error) {
+	record := r.pending
+	r.pending = nil
+	if record == nil {
+		var err error
+		if record, err = r.records.Read(); err != nil {
+			return nil, err
+		}
+	}
+	r.rowNum++
+	return record, nil
+}
+
+func (r *recordStream) apply(ctx context.Context, store repositories.ProductStore, limit int, result *dto.ImportResult) ([]*plannedRow, bool, error) {
+	var plans []*plannedRow
+	for len(plans) < limit {
+		if err := ctx.Err(); err != nil {
+			return plans, false, err
+		}
+		record, err := r.next()
+		if err == io.EOF {
+			return plans, true, nil
+		}
+		if err != nil {
+			return plans, false, err
+		}
+		if isBlankRecord(record) {
+			continue
+		}
+
+		plan := planRecord(ctx, store, r.columns, record, r.rowNum, r.options)
+		applyPlan(ctx, store, plan, plan.matchID(), result)
+		plans = append(plans, plan)
+	}
+	return plans, false, nil
+}
+
+func (r *recordStream) position() (int, float64) {
+	return r.rowNum - 1, r.records.Progress()
+}
+
+// planReplay applies the stored plan of a previewed import.
+type planReplay struct {
+	rows []*plannedRow
+	next int
+	// createdIDs maps the IDs products got in the preview to the IDs they
+	// get when the plan is committed, for rows that update products
+	// created by earlier rows.
+	createdIDs map[int]int
+}
+
+func (p *planReplay) apply(ctx context.Context, store repositories.ProductStore, limit int, result *dto.ImportResult) ([]*plannedRow, bool, error) {
+	start := p.next
+	for p.next < len(p.rows) && p.next-start < limit {
+		if err := ctx.Err(); err != nil {
+			return p.rows[start:p.next], false, err
+		}
+		plan := p.rows[p.next]
+		targetID := plan.matchID()
+		if id, ok := p.createdIDs[targetID]; ok {
+			targetID = id
+		}
+		previewedID := plan.createdID
+		applyPlan(ctx, store, plan, targetID, result)
+		if previewedID != 0 && plan.createdID != 0 {
+			p.createdIDs[previewedID] = plan.createdID
+		}
+		p.next++
+	}
+	return p.rows[start:p.next], p.next == len(p.rows), nil
+}
+
+func (p *planReplay) position() (int, float64) {
+	if len(p.rows) == 0 {
+		return 0, 1
+	}
+	return p.next, float64(p.next) / float64(len(p.rows))
+}
+
+// importJob is a running import, reported through ImportProgressEvent and
+// stopped by CancelImport. Its progress is only touched by the goroutine
+// running the import.
+type importJob struct {
+	s        *ImportExportService
+	progress dto.ImportProgressDTO
+	started  time.Time
+	cancel   context.CancelFunc
+}
+
+// startImport registers a running import of a source file and returns the
+// context it must run under, which CancelImport cancels.
+func (s *ImportExportService) startImport(ctx context.Context, source string) (context.Context, *importJob) {
+	ctx, cancel := context.WithCancel(ctx)
+	now := time.Now()
+
+	s.jobsMu.Lock()
+	s.nextJobID++
+	job := &importJob{
+		s: s,
+		progress: dto.ImportProgressDTO{
+			ID:         strconv.Itoa(s.nextJobID),
+			Source:     source,
+			Status:     dto.JobRunning,
+			ETASeconds: -1,
+			StartedAt:  now.Format(time.RFC3339),
+		},
+		started: now,
+		cancel:  cancel,
+	}
+	s.jobs[job.progress.ID] = job
+	s.jobsMu.Unlock()
+
+	s.emit.Emit(ImportProgressEvent, job.progress)
+	return ctx, job
+}
+
+// report emits the progress of the job once src has applied more rows.
+func (j *importJob) report(src rowSource, result *dto.ImportResult) {
+	rows, fraction := src.position()
+	j.progress.RowsProcessed = rows
+	j.progress.SuccessCount = result.SuccessCount
+	j.progress.ErrorCount = result.ErrorCount
+	j.progress.Progress = fraction
+	j.progress.ETASeconds = -1
+	if fraction > 0 {
+		elapsed := time.Since(j.started).Seconds()
+		j.progress.ETASeconds = int(elapsed*(1-fraction)/fraction + 0.5)
+	}
+	j.s.emit.Emit(ImportProgressEvent, j.progress)
+}
+
+// finish unregisters the job and emits its final state: completed, or
+// cancelled or failed because of err.
+func (j *importJob) finish(err error) {
+	j.cancel()
+	j.s.jobsMu.Lock()
+	delete(j.s.jobs, j.progress.ID)
+	j.s.jobsMu.Unlock()
+
+	switch {
+	case err == nil:
+		j.progress.Status = dto.JobCompleted
+		j.progress.Progress = 1
+		j.progress.ETASeconds = 0
+	case apperrors.CodeOf(err) == apperrors.CodeCancelled:
+		j.progress.Status = dto.JobCancelled
+	default:
+		j.progress.Status = dto.JobFailed
+		j.progress.Error = err.Error()
+	}
+	j.s.emit.Emit(ImportProgressEvent, j.progress)
+}
+
+// CancelImport stops a running import before its next row. The chunks a
+// non-atomic import has committed are kept and stay in its import batch;
+// everything else is rolled back.
+func (s *ImportExportService) CancelImport(id string) error {
+	s.jobsMu.Lock()
+	job, ok := s.jobs[id]
+	s.jobsMu.Unlock()
+	if !ok {
+		return apperrors.NotFound("running import", id)
+	}
+	job.cancel()
+	return nil
+}
+
+// addImportedItem adds product to the imported items of result, up to
+// maxImportedItems.
+func addImportedItem(result *dto.ImportResult, product *models.Product) {
+	if len(result.ImportedItems) < maxImportedItems {
+		result.ImportedItems = append(result.ImportedItems, product)
+	}
+}
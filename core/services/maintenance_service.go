@@ -0,0 +1,336 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"product-management-app/core/dto"
+	apperrors "product-management-app/core/errors"
+)
+
+// MaintenanceJobEvent is emitted to the frontend with a MaintenanceJobDTO
+// whenever a maintenance job makes progress or finishes.
+const MaintenanceJobEvent = "maintenance:job"
+
+const (
+	// maxIntegrityProblems caps the problems an integrity check reports.
+	maxIntegrityProblems = 100
+	// maxFinishedJobs is how many finished jobs are kept for GetMaintenanceJobs.
+	maxFinishedJobs = 20
+)
+
+// MaintenanceService runs integrity checks, VACUUM, ANALYZE and statistics
+// as background jobs. Only one job runs at a time, since VACUUM needs the
+// database to itself and the checks are slow on large files.
+type MaintenanceService struct {
+	logger *slog.Logger
+	emit   EventEmitter
+	db     *DatabaseService
+
+	mu      sync.Mutex
+	nextID  int
+	jobs    []*maintenanceJob
+	running *maintenanceJob
+}
+
+type maintenanceJob struct {
+	state  dto.MaintenanceJobDTO
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+func NewMaintenanceService(logger *slog.Logger, emit EventEmitter, db *DatabaseService) *MaintenanceService {
+	return &MaintenanceService{logger: logger, emit: emit, db: db}
+}
+
+// Start launches task in the background and returns the new job.
+func (m *MaintenanceService) Start(task dto.MaintenanceTask) (*dto.MaintenanceJobDTO, error) {
+	if err := task.Validate(); err != nil {
+		return nil, err
+	}
+	if m.db.DB == nil {
+		return nil, errNotConnected
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.running != nil {
+		return nil, apperrors.Conflict("maintenance job %s (%s) is already running", m.running.state.ID, m.running.state.Task)
+	}
+
+	m.nextID++
+	jobCtx, cancel := context.WithCancel(context.Background())
+	job := &maintenanceJob{
+		state: dto.MaintenanceJobDTO{
+			ID:        strconv.Itoa(m.nextID),
+			Task:      task,
+			Status:    dto.JobRunning,
+			Message:   "Starting",
+			StartedAt: time.Now().Format(time.RFC3339),
+		},
+		cancel: cancel,
+		done:   make(chan struct{}),
+	}
+	m.running = job
+	m.jobs = append(m.jobs, job)
+	if len(m.jobs) > maxFinishedJobs+1 {
+		m.jobs = m.jobs[len(m.jobs)-maxFinishedJobs-1:]
+	}
+
+	m.logger.Info("Maintenance job started", "job", job.state.ID, "task", task)
+	go m.run(jobCtx, job)
+
+	state := job.state
+	return &state, nil
+}
+
+// Cancel asks a running job to stop. The statement in progress is
+// interrupted and its changes rolled back.
+func (m *MaintenanceService) Cancel(id string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, job := range m.jobs {
+		if job.state.ID == id {
+			if job.state.Status != dto.JobRunning {
+				return apperrors.Conflict("maintenance job %s is already %s", id, job.state.Status)
+			}
+			job.cancel()
+			return nil
+		}
+	}
+	return apperrors.NotFound("maintenance job", id)
+}
+
+// Job returns the state of the job with the given ID.
+func (m *MaintenanceService) Job(id string) (*dto.MaintenanceJobDTO, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, job := range m.jobs {
+		if job.state.ID == id {
+			state := job.state
+			return &state, nil
+		}
+	}
+	return nil, apperrors.NotFound("maintenance job", id)
+}
+
+// Jobs returns the running job and the most recent finished ones, oldest first.
+func (m *MaintenanceService) Jobs() []dto.MaintenanceJobDTO {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	jobs := make([]dto.MaintenanceJobDTO, len(m.jobs))
+	for i, job := range m.jobs {
+		jobs[i] = job.state
+	}
+	return jobs
+}
+
+// CancelAll cancels the running job, if any, and waits for it to stop.
+func (m *MaintenanceService) CancelAll() {
+	m.mu.Lock()
+	job := m.running
+	m.mu.Unlock()
+	if job == nil {
+		return
+	}
+	job.cancel()
+	<-job.done
+}
+
+func (m *MaintenanceService) run(ctx context.Context, job *maintenanceJob) {
+	defer close(job.done)
+	defer job.cancel()
+
+	var result interface{}
+	var err error
+	switch job.state.Task {
+	case dto.MaintenanceIntegrityCheck:
+		result, err = m.checkIntegrity(ctx, job, "integrity_check")
+	case dto.MaintenanceQuickCheck:
+		result, err = m.checkIntegrity(ctx, job, "quick_check")
+	case dto.MaintenanceVacuum:
+		err = m.vacuum(ctx, job)
+	case dto.MaintenanceAnalyze:
+		err = m.analyze(ctx, job)
+	case dto.MaintenanceStats:
+		result, err = m.stats(ctx, job)
+	}
+
+	m.mu.Lock()
+	job.state.FinishedAt = time.Now().Format(time.RFC3339)
+	switch {
+	case ctx.Err() != nil:
+		job.state.Status = dto.JobCancelled
+		job.state.Message = "Cancelled"
+	case err != nil:
+		job.state.Status = dto.JobFailed
+		job.state.Message = "Failed"
+		job.state.Error = err.Error()
+	default:
+		job.state.Status = dto.JobCompleted
+		job.state.Message = "Completed"
+		job.state.Progress = 1
+		job.state.Result = result
+	}
+	m.running = nil
+	state := job.state
+	m.mu.Unlock()
+
+	if state.Status == dto.JobFailed {
+		m.logger.Error("Maintenance job failed", "job", state.ID, "task", state.Task, "error", err)
+	} else {
+		m.logger.Info("Maintenance job finished", "job", state.ID, "task", state.Task, "status", state.Status)
+	}
+	m.emit.Emit(MaintenanceJobEvent, state)
+}
+
+// report records the progress of job, between 0 and 1, and emits it.
+func (m *MaintenanceService) report(job *maintenanceJob, progress float64, message string) {
+	m.mu.Lock()
+	job.state.Progress = progress
+	job.state.Message = message
+	state := job.state
+	m.mu.Unlock()
+	m.emit.Emit(MaintenanceJobEvent, state)
+}
+
+// checkIntegrity runs PRAGMA integrity_check or quick_check. SQLite reports
+// no progress while a check runs, so the job moves from 0 to 1 in one step.
+func (m *MaintenanceService) checkIntegrity(ctx context.Context, job *maintenanceJob, pragma string) (*dto.IntegrityCheckResult, error) {
+	m.report(job, 0, fmt.Sprintf("Running %s", pragma))
+
+	rows, err := m.db.DB.QueryContext(ctx, fmt.Sprintf("PRAGMA %s(%d)", pragma, maxIntegrityProblems+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to run %s: %w", pragma, err)
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	result := &dto.IntegrityCheckResult{Problems: []string{}}
+	for rows.Next() {
+		var line string
+		if err := rows.Scan(&line); err != nil {
+			return nil, fmt.Errorf("failed to read %s result: %w", pragma, err)
+		}
+		if line == "ok" {
+			continue
+		}
+		if len(result.Problems) == maxIntegrityProblems {
+			result.Truncated = true
+			continue
+		}
+		result.Problems = append(result.Problems, line)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to run %s: %w", pragma, err)
+	}
+	result.OK = len(result.Problems) == 0
+	return result, nil
+}
+
+// vacuum rebuilds the database file, reclaiming free pages. Like the checks
+// it runs as a single statement without intermediate progress.
+func (m *MaintenanceService) vacuum(ctx context.Context, job *maintenanceJob) error {
+	m.report(job, 0, "Running VACUUM")
+	if _, err := m.db.DB.ExecContext(ctx, "VACUUM"); err != nil {
+		return fmt.Errorf("failed to vacuum database: %w", err)
+	}
+	return nil
+}
+
+// analyze gathers query planner statistics one table at a time, reporting
+// progress after each.
+func (m *MaintenanceService) analyze(ctx context.Context, job *maintenanceJob) error {
+	tables, err := m.tables(ctx)
+	if err != nil {
+		return err
+	}
+	for i, table := range tables {
+		m.report(job, float64(i)/float64(len(tables)), fmt.Sprintf("Analyzing %s", table))
+		if _, err := m.db.DB.ExecContext(ctx, "ANALYZE "+quoteIdentifier(table)); err != nil {
+			return fmt.Errorf("failed to analyze %s: %w", table, err)
+		}
+	}
+	return nil
+}
+
+// stats reads the page counts of the database and the row count of each
+// table, reporting progress after each table.
+func (m *MaintenanceService) stats(ctx context.Context, job *maintenanceJob) (*dto.DatabaseStatsDTO, error) {
+	m.report(job, 0, "Reading page counts")
+	stats := &dto.DatabaseStatsDTO{Path: m.db.Config.Path, Tables: []dto.TableStatsDTO{}}
+	if info, err := os.Stat(m.db.Config.Path); err == nil {
+		stats.FileSize = info.Size()
+	}
+	if info, err := os.Stat(m.db.Config.Path + "-wal"); err == nil {
+		stats.WALSize = info.Size()
+	}
+
+	pragmas := []struct {
+		name string
+		dest *int64
+	}{
+		{"page_size", &stats.PageSize},
+		{"page_count", &stats.PageCount},
+		{"freelist_count", &stats.FreelistCount},
+	}
+	for _, pragma := range pragmas {
+		if err := m.db.DB.QueryRowContext(ctx, "PRAGMA "+pragma.name).Scan(pragma.dest); err != nil {
+			return nil, fmt.Errorf("failed to read PRAGMA %s: %w", pragma.name, err)
+		}
+	}
+
+	tables, err := m.tables(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for i, table := range tables {
+		m.report(job, float64(i+1)/float64(len(tables)+1), fmt.Sprintf("Counting rows in %s", table))
+		var rows int64
+		if err := m.db.DB.QueryRowContext(ctx, "SELECT COUNT(*) FROM "+quoteIdentifier(table)).Scan(&rows); err != nil {
+			return nil, fmt.Errorf("failed to count rows in %s: %w", table, err)
+		}
+		stats.Tables = append(stats.Tables, dto.TableStatsDTO{Name: table, Rows: rows})
+	}
+	return stats, nil
+}
+
+// tables lists the ordinary tables of the main schema, leaving out SQLite's
+// internal tables and the full-text search index.
+func (m *MaintenanceService) tables(ctx context.Context) ([]string, error) {
+	rows, err := m.db.DB.QueryContext(ctx, "SELECT name FROM pragma_table_list WHERE schema = 'main' AND type = 'table' AND name NOT LIKE 'sqlite_%' ORDER BY name")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tables: %w", err)
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	var tables []string
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, fmt.Errorf("failed to list tables: %w", err)
+		}
+		tables = append(tables, name)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list tables: %w", err)
+	}
+	if len(tables) == 0 {
+		return nil, errors.New("database has no tables")
+	}
+	return tables, nil
+}
+
+func quoteIdentifier(name string) string {
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+}
@@ -3,155 +3,662 @@ package service
 import (
 	"context"
 	"fmt"
+	"log/slog"
+	"os"
+	"sync"
 
+	"product-management-app/core/config"
 	"product-management-app/core/dto"
+	apperrors "product-management-app/core/errors"
 	"product-management-app/core/models"
 	"product-management-app/core/repositories"
-
-	"github.com/wailsapp/wails/v2/pkg/runtime"
+	"product-management-app/core/validation"
 )
 
+// errDatabaseNotInitialized is returned by every method that needs the
+// database before InitDatabase has succeeded.
+var errDatabaseNotInitialized = apperrors.Unavailable(nil, "database service not initialized")
+
 type ProductService struct {
-	repo                *repositories.ProductRepository
-	ctx                 context.Context
+	logger    *slog.Logger
+	emit      EventEmitter
+	dbConfig  config.DatabaseConfig
+	validator *validation.ProductValidator
+
+	// mu guards the database handles below. Every call holds it for
+	// reading while it runs, and opening, closing or swapping the database
+	// holds it for writing, so the database is never closed under a call.
+	mu                  sync.RWMutex
+	store               repositories.ProductStore
 	db                  *DatabaseService
 	importExportService *ImportExportService
+	backups             *BackupService
+	maintenance         *MaintenanceService
 }
 
-func (s *ProductService) SetContext(ctx context.Context) {
-	s.ctx = ctx
-	runtime.LogInfo(s.ctx, "Context set for ProductService")
+// NewProductService returns a service for the database described by
+// dbConfig. Every product written through it, including by imports and bulk
+// operations, must pass validator. Everything it and the services it opens
+// log goes to logger, and progress events for the frontend go to emit, which
+// may be nil.
+func NewProductService(dbConfig config.DatabaseConfig, validator *validation.ProductValidator, logger *slog.Logger, emit EventEmitter) *ProductService {
+	return &ProductService{dbConfig: dbConfig, validator: validator, logger: logger, emit: emit}
 }
 
-func NewProductService() *ProductService {
-	return &ProductService{}
+// InitDatabase opens the database and the services that use it, closing the
+// ones already open first. It waits for the calls in progress to return. If
+// the database cannot be opened, the service is left closed.
+func (s *ProductService) InitDatabase() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.initDatabase()
 }
 
-func (s *ProductService) InitDatabase() error {
-	s.db = NewDatabaseService(s.ctx)
-	if err := s.db.InitDatabase(); err != nil {
+func (s *ProductService) initDatabase() error {
+	s.closeDatabase()
+	db := NewDatabaseService(s.logger, s.dbConfig)
+	if err := db.InitDatabase(); err != nil {
+		// The connection pool may already be open when the migrations or
+		// settings fail.
+		db.CloseDatabase()
 		return err
 	}
-	s.repo = repositories.NewProductRepository(s.ctx, s.db.DB)
-	s.importExportService = NewImportExportService(s.ctx, s.repo)
+	s.db = db
+	s.store = validation.NewValidatingStore(repositories.NewProductRepository(s.db.DB, s.logger), s.validator)
+	s.importExportService = NewImportExportService(s.logger, s.emit, s.store)
+	s.backups = NewBackupService(s.logger, s.db)
+	s.backups.Start()
+	s.maintenance = NewMaintenanceService(s.logger, s.emit, s.db)
 	return nil
 }
 
+// RemoveStaleArchiveFiles removes the plain databases an encrypted archive
+// export or import left in the backup directory when the app stopped before
+// it finished. Call it at startup, before any archive is exported or
+// imported.
+func (s *ProductService) RemoveStaleArchiveFiles() {
+	removeArchiveTemps(s.logger, s.dbConfig.Backup.Dir)
+}
+
+// CloseDatabase cancels the running maintenance job, stops the scheduled
+// backups and closes the database once the calls in progress have returned.
 func (s *ProductService) CloseDatabase() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.closeDatabase()
+}
+
+func (s *ProductService) closeDatabase() {
+	if s.maintenance != nil {
+		s.maintenance.CancelAll()
+	}
+	if s.backups != nil {
+		s.backups.Stop()
+	}
 	if s.db != nil {
 		s.db.CloseDatabase()
 	}
+	s.store = nil
+	s.db = nil
+	s.importExportService = nil
+	s.backups = nil
+	s.maintenance = nil
 }
 
-func (s *ProductService) HealthCheck() error {
+// StartMaintenance runs a maintenance task as a background job.
+func (s *ProductService) StartMaintenance(task dto.MaintenanceTask) (*dto.MaintenanceJobDTO, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.maintenance == nil {
+		return nil, errDatabaseNotInitialized
+	}
+	return s.maintenance.Start(task)
+}
+
+// CancelMaintenance stops a running maintenance job.
+func (s *ProductService) CancelMaintenance(id string) error {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.maintenance == nil {
+		return errDatabaseNotInitialized
+	}
+	return s.maintenance.Cancel(id)
+}
+
+// MaintenanceJob returns the state of a maintenance job.
+func (s *ProductService) MaintenanceJob(id string) (*dto.MaintenanceJobDTO, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.maintenance == nil {
+		return nil, errDatabaseNotInitialized
+	}
+	return s.maintenance.Job(id)
+}
+
+// MaintenanceJobs returns the running and recently finished maintenance jobs.
+func (s *ProductService) MaintenanceJobs() []dto.MaintenanceJobDTO {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.maintenance == nil {
+		return []dto.MaintenanceJobDTO{}
+	}
+	return s.maintenance.Jobs()
+}
+
+// BackupDatabase writes a consistent copy of the open database to path.
+func (s *ProductService) BackupDatabase(ctx context.Context, path string) (*dto.BackupDTO, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.backups == nil {
+		return nil, errDatabaseNotInitialized
+	}
+	return s.backups.Backup(ctx, path)
+}
+
+// ListBackups returns the scheduled backups, newest first.
+func (s *ProductService) ListBackups() ([]dto.BackupDTO, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.backups == nil {
+		return nil, errDatabaseNotInitialized
+	}
+	return s.backups.List()
+}
+
+// ExportArchive writes the database to path as an encrypted archive
+// protected by passphrase.
+func (s *ProductService) ExportArchive(ctx context.Context, path, passphrase string) (*dto.BackupDTO, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.backups == nil {
+		return nil, errDatabaseNotInitialized
+	}
+	return s.backups.ExportArchive(ctx, path, passphrase)
+}
+
+// ImportArchive decrypts and verifies the archive at path, then either
+// replaces the database with it or merges its products in. The current
+// database is backed up first either way.
+func (s *ProductService) ImportArchive(ctx context.Context, path, passphrase string, mode dto.ArchiveImportMode) (*dto.ArchiveImportResult, error) {
+	if err := mode.Validate(); err != nil {
+		return nil, err
+	}
+
+	plain, archived, err := s.openArchive(ctx, path, passphrase)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = os.Remove(plain)
+	}()
+
+	result := &dto.ArchiveImportResult{Mode: mode, Archive: *archived}
+	if mode == dto.ArchiveReplace {
+		restored, err := s.RestoreBackup(ctx, plain)
+		if err != nil {
+			return nil, err
+		}
+		result.SafetyBackup = restored.SafetyBackup
+		return result, nil
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.backups == nil {
+		return nil, errDatabaseNotInitialized
+	}
+	result.SafetyBackup, err = s.backups.SafetyBackup(ctx, "")
+	if err != nil {
+		s.logger.Error("Failed to back up database before merge", "error", err)
+		return nil, err
+	}
+	result.Merge, err = repositories.NewProductRepository(s.db.DB, s.logger).MergeFrom(ctx, plain, s.validator.ValidateCreate)
+	if err != nil {
+		s.logger.Error("Failed to merge archive", "path", path, "error", err)
+		return nil, err
+	}
+	s.logger.Info("Merged archive", "path", path, "inserted", result.Merge.Inserted, "updated", result.Merge.Updated,
+		"skipped", result.Merge.Skipped, "rejected", len(result.Merge.Rejected))
+	return result, nil
+}
+
+// openArchive decrypts and verifies the archive at path into a plain
+// database file, which the caller must remove.
+func (s *ProductService) openArchive(ctx context.Context, path, passphrase string) (string, *dto.BackupDTO, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.backups == nil {
+		return "", nil, errDatabaseNotInitialized
+	}
+	plain, archived, err := s.backups.OpenArchive(ctx, path, passphrase)
+	if err != nil {
+		s.logger.Error("Rejected archive", "path", path, "error", err)
+		return "", nil, err
+	}
+	return plain, archived, nil
+}
+
+// RestoreBackup replaces the database with the backup at path and reopens
+// it. The backup is validated first, and the current database is backed up
+// so the restore can be undone. If the restored database cannot be opened,
+// the previous database is put back. ctx can stop the validation and the
+// safety backup; once the database is closed the restore runs to the end.
+// The swap waits for the calls in progress to return and holds back new
+// ones until it is done.
+func (s *ProductService) RestoreBackup(ctx context.Context, path string) (*dto.RestoreResultDTO, error) {
+	backup, safetyBackup, err := s.prepareRestore(ctx, path)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.closeDatabase()
+	if err := replaceDatabaseFile(path, s.dbConfig.Path); err != nil {
+		s.logger.Error("Failed to restore backup", "path", path, "error", err)
+		if reopenErr := s.initDatabase(); reopenErr != nil {
+			return nil, fmt.Errorf("%w; reopening the database also failed: %v", err, reopenErr)
+		}
+		return nil, err
+	}
+
+	if err := s.initDatabase(); err != nil {
+		s.logger.Error("Restored database could not be opened, putting back the previous one", "safetyBackup", safetyBackup, "error", err)
+		if rollbackErr := replaceDatabaseFile(safetyBackup, s.dbConfig.Path); rollbackErr != nil {
+			return nil, fmt.Errorf("restored database could not be opened: %w; putting back the previous database failed: %v", err, rollbackErr)
+		}
+		if reopenErr := s.initDatabase(); reopenErr != nil {
+			return nil, fmt.Errorf("restored database could not be opened: %w; reopening the previous database failed: %v", err, reopenErr)
+		}
+		return nil, fmt.Errorf("restored database could not be opened, the previous database was put back: %w", err)
+	}
+
+	s.logger.Info("Database restored", "path", path, "safetyBackup", safetyBackup)
+	return &dto.RestoreResultDTO{Restored: *backup, SafetyBackup: safetyBackup}, nil
+}
+
+// prepareRestore validates the backup at path and backs up the current
+// database, returning the backup and the path of the safety backup.
+func (s *ProductService) prepareRestore(ctx context.Context, path string) (*dto.BackupDTO, string, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.backups == nil {
+		return nil, "", errDatabaseNotInitialized
+	}
+
+	backup, err := s.backups.Validate(ctx, path)
+	if err != nil {
+		s.logger.Error("Rejected backup", "path", path, "error", err)
+		return nil, "", fmt.Errorf("invalid backup: %w", err)
+	}
+	safetyBackup, err := s.backups.SafetyBackup(ctx, path)
+	if err != nil {
+		s.logger.Error("Failed to back up database before restore", "error", err)
+		return nil, "", err
+	}
+	return backup, safetyBackup, nil
+}
+
+func (s *ProductService) HealthCheck(ctx context.Context) error {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
 	if s.db != nil {
-		return s.db.HealthCheck()
+		return s.db.HealthCheck(ctx)
 	}
-	return fmt.Errorf("database service not initialized")
+	return errDatabaseNotInitialized
 }
 
-func (s *ProductService) CreateProduct(createProductDTO dto.CreateProductDTO) (*models.Product, error) {
-	product, err := s.repo.Create(createProductDTO)
+func (s *ProductService) CreateProduct(ctx context.Context, createProductDTO dto.CreateProductDTO) (*models.Product, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.store == nil {
+		return nil, errDatabaseNotInitialized
+	}
+	product, err := s.store.Create(ctx, createProductDTO)
 	if err != nil {
-		runtime.LogError(s.ctx, fmt.Sprintf("Failed to create product: %v", err))
+		s.logger.Error("Failed to create product", "error", err)
 		return nil, err
 	}
-	runtime.LogInfo(s.ctx, fmt.Sprintf("Product created: %+v", product))
+	s.logger.Info("Product created", "id", product.ID, "name", product.Name)
 	return product, nil
 }
 
-func (s *ProductService) GetProductByID(id int) (*models.Product, error) {
-	product, err := s.repo.GetByID(id)
+func (s *ProductService) GetProductByID(ctx context.Context, id int) (*models.Product, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.store == nil {
+		return nil, errDatabaseNotInitialized
+	}
+	product, err := s.store.GetByID(ctx, id)
 	if err != nil {
-		runtime.LogError(s.ctx, fmt.Sprintf("Failed to fetch product with ID %d: %v", id, err))
+		s.logger.Error("Failed to fetch product", "id", id, "error", err)
 		return nil, err
 	}
-	runtime.LogInfo(s.ctx, fmt.Sprintf("Product found: %+v", product))
+	s.logger.Info("Product found", "id", product.ID)
 	return product, nil
 }
 
-func (s *ProductService) GetAllProducts(params dto.PaginationDTO) (*dto.PaginationResponse, error) {
-	response, err := s.repo.GetAll(params)
+func (s *ProductService) GetAllProducts(ctx context.Context, params dto.PaginationDTO) (*dto.PaginationResponse, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.store == nil {
+		return nil, errDatabaseNotInitialized
+	}
+	response, err := s.store.GetAll(ctx, params)
+	if err != nil {
+		s.logger.Error("Failed to fetch products", "error", err)
+		return nil, err
+	}
+	s.logger.Info("Products found", "count", len(response.Products), "total", response.TotalCount)
+	return response, nil
+}
+
+// DatabasePath returns the path of the SQLite database file in use.
+func (s *ProductService) DatabasePath() string {
+	return s.dbConfig.Path
+}
+
+// SchemaStatus reports the database schema version and migration history.
+func (s *ProductService) SchemaStatus() (*dto.SchemaStatusDTO, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.db == nil {
+		return nil, errDatabaseNotInitialized
+	}
+	return s.db.SchemaStatus()
+}
+
+// DatabaseSettings reports the connection pragmas and pool usage.
+func (s *ProductService) DatabaseSettings(ctx context.Context) (*dto.DatabaseSettingsDTO, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.db == nil {
+		return nil, errDatabaseNotInitialized
+	}
+	return s.db.Settings(ctx)
+}
+
+// FullTextSearchAvailable reports whether SearchProducts can be used.
+func (s *ProductService) FullTextSearchAvailable() bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.fullTextSearchAvailable()
+}
+
+func (s *ProductService) fullTextSearchAvailable() bool {
+	return s.db != nil && s.db.FullTextSearch
+}
+
+// SearchProducts runs a ranked full-text search over the catalog.
+func (s *ProductService) SearchProducts(ctx context.Context, params dto.ProductSearchDTO) (*dto.ProductSearchResponse, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.store == nil {
+		return nil, errDatabaseNotInitialized
+	}
+	if !s.fullTextSearchAvailable() {
+		return nil, apperrors.Unavailable(nil, "full-text search is not available in this build")
+	}
+
+	response, err := s.store.Search(ctx, params)
 	if err != nil {
-		runtime.LogError(s.ctx, fmt.Sprintf("Failed to fetch products: %v", err))
+		s.logger.Error("Failed to search products", "error", err)
 		return nil, err
 	}
-	runtime.LogInfo(s.ctx, fmt.Sprintf("Products found: %d of %d total", len(response.Products), response.TotalCount))
+	s.logger.Info("Search completed", "query", params.Query, "matches", response.TotalCount)
 	return response, nil
 }
 
-func (s *ProductService) UpdateProduct(id int, name string, price float64) (*models.Product, error) {
-	product, err := s.repo.Update(id, name, price)
+func (s *ProductService) UpdateProduct(ctx context.Context, id int, update dto.UpdateProductDTO) (*models.Product, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.store == nil {
+		return nil, errDatabaseNotInitialized
+	}
+	product, err := s.store.Update(ctx, id, update)
 	if err != nil {
-		runtime.LogError(s.ctx, fmt.Sprintf("Failed to update product with ID %d: %v", id, err))
+		s.logger.Error("Failed to update product", "id", id, "error", err)
 		return nil, err
 	}
-	runtime.LogInfo(s.ctx, fmt.Sprintf("Product updated: %+v", product))
+	s.logger.Info("Product updated", "id", product.ID, "version", product.Version)
 	return product, nil
 }
 
-func (s *ProductService) DeleteProduct(id int) error {
-	err := s.repo.Delete(id)
+func (s *ProductService) DeleteProduct(ctx context.Context, id, version int) error {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.store == nil {
+		return errDatabaseNotInitialized
+	}
+	err := s.store.Delete(ctx, id, version)
 	if err != nil {
-		runtime.LogError(s.ctx, fmt.Sprintf("Failed to delete product with ID %d: %v", id, err))
+		s.logger.Error("Failed to delete product", "id", id, "error", err)
 		return err
 	}
-	runtime.LogInfo(s.ctx, fmt.Sprintf("Product deleted: ID %d", id))
+	s.logger.Info("Product moved to trash", "id", id)
 	return nil
 }
 
-func (s *ProductService) ExportProductsToCSV(includeAll bool, productIDs []int) ([]byte, error) {
+// ApplyBulkOperation applies one operation to a selection of products in a
+// single transaction.
+func (s *ProductService) ApplyBulkOperation(ctx context.Context, op dto.BulkOperationDTO) (*dto.BulkOperationResult, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.store == nil {
+		return nil, errDatabaseNotInitialized
+	}
+	result, err := s.store.BulkApply(ctx, op)
+	if err != nil {
+		s.logger.Error("Failed to apply bulk operation", "operation", op.Operation, "error", err)
+		return nil, err
+	}
+	if !result.Committed {
+		s.logger.Warn("Bulk operation rolled back", "operation", op.Operation, "failed", result.ErrorCount, "total", len(result.Items))
+		return result, nil
+	}
+	s.logger.Info("Bulk operation applied", "operation", op.Operation, "products", result.SuccessCount)
+	return result, nil
+}
+
+func (s *ProductService) GetTrash(ctx context.Context, params dto.PaginationDTO) (*dto.PaginationResponse, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.store == nil {
+		return nil, errDatabaseNotInitialized
+	}
+	response, err := s.store.GetTrash(ctx, params)
+	if err != nil {
+		s.logger.Error("Failed to fetch trash", "error", err)
+		return nil, err
+	}
+	s.logger.Info("Trashed products found", "count", len(response.Products), "total", response.TotalCount)
+	return response, nil
+}
+
+func (s *ProductService) RestoreProduct(ctx context.Context, id, version int) (*models.Product, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.store == nil {
+		return nil, errDatabaseNotInitialized
+	}
+	if err := s.store.Restore(ctx, id, version); err != nil {
+		s.logger.Error("Failed to restore product", "id", id, "error", err)
+		return nil, err
+	}
+	s.logger.Info("Product restored from trash", "id", id)
+	return s.store.GetByID(ctx, id)
+}
+
+func (s *ProductService) PurgeProduct(ctx context.Context, id int) error {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.store == nil {
+		return errDatabaseNotInitialized
+	}
+	if err := s.store.Purge(ctx, id); err != nil {
+		s.logger.Error("Failed to purge product", "id", id, "error", err)
+		return err
+	}
+	s.logger.Info("Product purged", "id", id)
+	return nil
+}
+
+func (s *ProductService) PurgeTrash(ctx context.Context, olderThanDays int) (int, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.store == nil {
+		return 0, errDatabaseNotInitialized
+	}
+	purged, err := s.store.PurgeOlderThan(ctx, olderThanDays)
+	if err != nil {
+		s.logger.Error("Failed to purge trash", "error", err)
+		return 0, err
+	}
+	s.logger.Info("Purged trash", "products", purged, "olderThanDays", olderThanDays)
+	return purged, nil
+}
+
+func (s *ProductService) ExportProductsToCSV(ctx context.Context, includeAll bool, productIDs []int) ([]byte, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.importExportService == nil {
+		return nil, errDatabaseNotInitialized
+	}
 	request := dto.ExportRequest{
 		Format:     dto.FormatCSV,
 		IncludeAll: includeAll,
 		ProductIDs: productIDs,
 	}
 
-	data, err := s.importExportService.ExportToCSV(request)
+	data, err := s.importExportService.ExportToCSV(ctx, request)
 	if err != nil {
-		runtime.LogError(s.ctx, fmt.Sprintf("Failed to export products to CSV: %v", err))
+		s.logger.Error("Failed to export products to CSV", "error", err)
 		return nil, err
 	}
 
-	runtime.LogInfo(s.ctx, "Products exported to CSV successfully")
+	s.logger.Info("Products exported to CSV")
 	return data, nil
 }
 
-func (s *ProductService) ExportProductsToXLSX(includeAll bool, productIDs []int) ([]byte, error) {
+func (s *ProductService) ExportProductsToXLSX(ctx context.Context, includeAll bool, productIDs []int) ([]byte, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.importExportService == nil {
+		return nil, errDatabaseNotInitialized
+	}
 	request := dto.ExportRequest{
 		Format:     dto.FormatXLSX,
 		IncludeAll: includeAll,
 		ProductIDs: productIDs,
 	}
 
-	data, err := s.importExportService.ExportToXLSX(request)
+	data, err := s.importExportService.ExportToXLSX(ctx, request)
 	if err != nil {
-		runtime.LogError(s.ctx, fmt.Sprintf("Failed to export products to XLSX: %v", err))
+		s.logger.Error("Failed to export products to XLSX", "error", err)
 		return nil, err
 	}
 
-	runtime.LogInfo(s.ctx, "Products exported to XLSX successfully")
+	s.logger.Info("Products exported to XLSX")
 	return data, nil
 }
 
-func (s *ProductService) ImportProductsFromCSV(data []byte) (*dto.ImportResult, error) {
-	result, err := s.importExportService.ImportFromCSV(data)
+func (s *ProductService) ImportProductsFromCSV(ctx context.Context, path string, options dto.ImportOptions) (*dto.ImportResult, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.importExportService == nil {
+		return nil, errDatabaseNotInitialized
+	}
+	result, err := s.importExportService.ImportFromCSV(ctx, path, options)
+	if err != nil {
+		s.logger.Error("Failed to import products from CSV", "path", path, "error", err)
+		return nil, err
+	}
+
+	s.logger.Info("CSV import completed", "created", result.CreatedCount, "updated", result.UpdatedCount, "skipped", result.SkippedCount, "errors", result.ErrorCount)
+	return result, nil
+}
+
+func (s *ProductService) ImportProductsFromXLSX(ctx context.Context, path string, options dto.ImportOptions) (*dto.ImportResult, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.importExportService == nil {
+		return nil, errDatabaseNotInitialized
+	}
+	result, err := s.importExportService.ImportFromXLSX(ctx, path, options)
 	if err != nil {
-		runtime.LogError(s.ctx, fmt.Sprintf("Failed to import products from CSV: %v", err))
+		s.logger.Error("Failed to import products from XLSX", "path", path, "error", err)
 		return nil, err
 	}
 
-	runtime.LogInfo(s.ctx, fmt.Sprintf("CSV import completed: %d success, %d errors", result.SuccessCount, result.ErrorCount))
+	s.logger.Info("XLSX import completed", "created", result.CreatedCount, "updated", result.UpdatedCount, "skipped", result.SkippedCount, "errors", result.ErrorCount)
 	return result, nil
 }
 
-func (s *ProductService) ImportProductsFromXLSX(data []byte) (*dto.ImportResult, error) {
-	result, err := s.importExportService.ImportFromXLSX(data)
+// CommitImport applies the plan of a dry-run import returned by
+// ImportProductsFromCSV or ImportProductsFromXLSX.
+func (s *ProductService) CommitImport(ctx context.Context, previewID string) (*dto.ImportResult, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.importExportService == nil {
+		return nil, errDatabaseNotInitialized
+	}
+	result, err := s.importExportService.CommitImport(ctx, previewID)
 	if err != nil {
-		runtime.LogError(s.ctx, fmt.Sprintf("Failed to import products from XLSX: %v", err))
+		s.logger.Error("Failed to commit import", "preview", previewID, "error", err)
 		return nil, err
 	}
 
-	runtime.LogInfo(s.ctx, fmt.Sprintf("XLSX import completed: %d success, %d errors", result.SuccessCount, result.ErrorCount))
+	s.logger.Info("Import commit completed", "success", result.SuccessCount, "errors", result.ErrorCount)
 	return result, nil
 }
+
+// DiscardImportPreview forgets the plan of a dry-run import that will not
+// be committed.
+func (s *ProductService) DiscardImportPreview(previewID string) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.importExportService == nil {
+		return
+	}
+	s.importExportService.DiscardPreview(previewID)
+}
+
+// CancelImport stops a running import or import preview.
+func (s *ProductService) CancelImport(id string) error {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.importExportService == nil {
+		return errDatabaseNotInitialized
+	}
+	return s.importExportService.CancelImport(id)
+}
+
+// ListImportBatches returns the most recent import batches, newest first.
+func (s *ProductService) ListImportBatches(ctx context.Context, limit int) ([]*models.ImportBatch, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.importExportService == nil {
+		return nil, errDatabaseNotInitialized
+	}
+	batches, err := s.importExportService.ListImportBatches(ctx, limit)
+	if err != nil {
+		s.logger.Error("Failed to list import batches", "error", err)
+		return nil, err
+	}
+	return batches, nil
+}
+
+// RollbackImport undoes every product write of an import batch.
+func (s *ProductService) RollbackImport(ctx context.Context, batchID int) (*models.ImportBatch, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.importExportService == nil {
+		return nil, errDatabaseNotInitialized
+	}
+	batch, err := s.importExportService.RollbackImport(ctx, batchID)
+	if err != nil {
+		s.logger.Error("Failed to roll back import", "batch", batchID, "error", err)
+		return nil, err
+	}
+	return batch, nil
+}
@@ -0,0 +1,41 @@
+//go:build sqlite_cgo
+
+package sqlite
+
+import (
+	"net/url"
+	"strconv"
+
+	_ "github.com/mattn/go-sqlite3" // SQLite driver
+)
+
+// DriverName is the database/sql name of the compiled-in driver.
+const DriverName = "sqlite3"
+
+// Driver identifies the compiled-in driver in status reports.
+const Driver = "github.com/mattn/go-sqlite3"
+
+// dataSourceName passes pragmas as go-sqlite3's own DSN parameters, which it
+// applies to every new connection.
+func dataSourceName(path string, opts Options) string {
+	params := url.Values{}
+	if opts.ReadOnly {
+		params.Set("mode", "ro")
+	}
+	if opts.BusyTimeoutMs > 0 {
+		params.Set("_busy_timeout", strconv.Itoa(opts.BusyTimeoutMs))
+	}
+	if opts.ForeignKeys {
+		params.Set("_foreign_keys", "1")
+	}
+	if opts.JournalMode != "" {
+		params.Set("_journal_mode", opts.JournalMode)
+	}
+	if opts.Synchronous != "" {
+		params.Set("_synchronous", opts.Synchronous)
+	}
+	if opts.ImmediateTx {
+		params.Set("_txlock", "immediate")
+	}
+	return fileURI(path, params)
+}
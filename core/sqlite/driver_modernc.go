@@ -0,0 +1,41 @@
+//go:build !sqlite_cgo
+
+package sqlite
+
+import (
+	"fmt"
+	"net/url"
+
+	_ "modernc.org/sqlite" // SQLite driver
+)
+
+// DriverName is the database/sql name of the compiled-in driver.
+const DriverName = "sqlite"
+
+// Driver identifies the compiled-in driver in status reports.
+const Driver = "modernc.org/sqlite"
+
+// dataSourceName passes pragmas as _pragma parameters, which
+// modernc.org/sqlite runs on every new connection.
+func dataSourceName(path string, opts Options) string {
+	params := url.Values{}
+	if opts.ReadOnly {
+		params.Set("mode", "ro")
+	}
+	if opts.BusyTimeoutMs > 0 {
+		params.Add("_pragma", fmt.Sprintf("busy_timeout(%d)", opts.BusyTimeoutMs))
+	}
+	if opts.ForeignKeys {
+		params.Add("_pragma", "foreign_keys(1)")
+	}
+	if opts.JournalMode != "" {
+		params.Add("_pragma", fmt.Sprintf("journal_mode(%s)", opts.JournalMode))
+	}
+	if opts.Synchronous != "" {
+		params.Add("_pragma", fmt.Sprintf("synchronous(%s)", opts.Synchronous))
+	}
+	if opts.ImmediateTx {
+		params.Set("_txlock", "immediate")
+	}
+	return fileURI(path, params)
+}
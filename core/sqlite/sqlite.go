@@ -0,0 +1,59 @@
+// Package sqlite opens SQLite databases through the database/sql driver
+// selected at build time:
+//
+//   - modernc.org/sqlite, a pure-Go translation of SQLite, is the default
+//     and needs no C toolchain, so every platform can be cross-compiled.
+//   - github.com/mattn/go-sqlite3, the cgo binding, is used when building
+//     with the sqlite_cgo tag. Add the sqlite_fts5 tag to enable full-text
+//     search with it; modernc.org/sqlite always includes FTS5.
+//
+// Each driver spells its connection parameters differently, so callers
+// describe the connection with Options and let Open build the DSN.
+package sqlite
+
+import (
+	"database/sql"
+	"net/url"
+	"path/filepath"
+)
+
+// Options are applied to every connection the pool opens. Zero values leave
+// SQLite's own defaults in place.
+type Options struct {
+	JournalMode   string
+	Synchronous   string
+	BusyTimeoutMs int
+	ForeignKeys   bool
+	// ImmediateTx begins transactions with BEGIN IMMEDIATE, so a writer
+	// waits on the busy timeout instead of failing when it upgrades from a
+	// read lock.
+	ImmediateTx bool
+	// ReadOnly opens the file without write access and never creates it.
+	ReadOnly bool
+}
+
+// Open opens the database file at path with the compiled-in driver.
+func Open(path string, opts Options) (*sql.DB, error) {
+	return sql.Open(DriverName, dataSourceName(path, opts))
+}
+
+// HasFTS5 reports whether the SQLite library behind db was built with the
+// FTS5 extension, which full-text search needs.
+func HasFTS5(db *sql.DB) (bool, error) {
+	var enabled bool
+	if err := db.QueryRow("SELECT sqlite_compileoption_used('ENABLE_FTS5')").Scan(&enabled); err != nil {
+		return false, err
+	}
+	return enabled, nil
+}
+
+// fileURI turns path into an SQLite URI, escaping characters such as ? and #
+// that would otherwise be read as URI syntax. Both drivers open URIs, which
+// is also how read-only mode is requested.
+func fileURI(path string, params url.Values) string {
+	uri := "file:" + (&url.URL{Path: filepath.ToSlash(path)}).EscapedPath()
+	if len(params) > 0 {
+		uri += "?" + params.Encode()
+	}
+	return uri
+}
@@ -0,0 +1,232 @@
+package test
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"product-management-app/core/archive"
+	"product-management-app/core/config"
+	"product-management-app/core/dto"
+	"product-management-app/core/money"
+)
+
+func encryptPayload(t *testing.T, payload []byte, passphrase string) []byte {
+	t.Helper()
+	var sealed bytes.Buffer
+	if err := archive.Encrypt(&sealed, bytes.NewReader(payload), []byte(passphrase)); err != nil {
+		t.Fatalf("Encrypt failed: %v", err)
+	}
+	return sealed.Bytes()
+}
+
+func TestArchiveRoundTrip(t *testing.T) {
+	large := make([]byte, 3*64*1024+123)
+	if _, err := rand.Read(large); err != nil {
+		t.Fatalf("Failed to generate payload: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		payload []byte
+	}{
+		{name: "Empty payload", payload: []byte{}},
+		{name: "Small payload", payload: []byte("SQLite format 3\x00")},
+		{name: "Exact chunk multiple", payload: large[:2*64*1024]},
+		{name: "Several chunks", payload: large},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sealed := encryptPayload(t, tt.payload, "correct horse")
+			if bytes.Contains(sealed, []byte("SQLite format 3")) {
+				t.Errorf("Archive contains the plaintext")
+			}
+
+			var opened bytes.Buffer
+			if err := archive.Decrypt(&opened, bytes.NewReader(sealed), []byte("correct horse")); err != nil {
+				t.Fatalf("Decrypt failed: %v", err)
+			}
+			if !bytes.Equal(opened.Bytes(), tt.payload) {
+				t.Errorf("Decrypted payload differs from the original")
+			}
+		})
+	}
+}
+
+func TestArchiveRejectsTampering(t *testing.T) {
+	payload := bytes.Repeat([]byte("supplier price 12.34\n"), 10000)
+	sealed := encryptPayload(t, payload, "correct horse")
+
+	flipped := bytes.Clone(sealed)
+	flipped[len(flipped)/2] ^= 0x01
+	// The header is 71 bytes and each sealed chunk 64 KiB plus a 16 byte tag.
+	chunkBoundary := 71 + 2*(64*1024+16)
+	headerFlipped := bytes.Clone(sealed)
+	headerFlipped[12] ^= 0x01
+
+	tests := []struct {
+		name       string
+		archive    []byte
+		passphrase string
+		expected   error
+	}{
+		{name: "Wrong passphrase", archive: sealed, passphrase: "wrong horse", expected: archive.ErrWrongPassphrase},
+		{name: "Flipped payload byte", archive: flipped, passphrase: "correct horse", expected: archive.ErrCorrupt},
+		{name: "Truncated at chunk boundary", archive: sealed[:chunkBoundary], passphrase: "correct horse", expected: archive.ErrCorrupt},
+		{name: "Truncated mid chunk", archive: sealed[:len(sealed)-10], passphrase: "correct horse", expected: archive.ErrCorrupt},
+		{name: "Plain database file", archive: []byte("SQLite format 3\x00 and then some more bytes to fill the header"), passphrase: "correct horse", expected: archive.ErrNotArchive},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := archive.Decrypt(&bytes.Buffer{}, bytes.NewReader(tt.archive), []byte(tt.passphrase))
+			if !errors.Is(err, tt.expected) {
+				t.Errorf("Expected %v, got %v", tt.expected, err)
+			}
+		})
+	}
+
+	if err := archive.Decrypt(&bytes.Buffer{}, bytes.NewReader(headerFlipped), []byte("correct horse")); err == nil {
+		t.Errorf("Expected an error for a modified header")
+	}
+}
+
+func TestArchiveImportOptionsValidate(t *testing.T) {
+	tests := []struct {
+		name        string
+		validate    func() error
+		expectError bool
+	}{
+		{name: "Replace mode", validate: dto.ArchiveReplace.Validate},
+		{name: "Merge mode", validate: dto.ArchiveMerge.Validate},
+		{name: "Unknown mode", validate: dto.ArchiveImportMode("append").Validate, expectError: true},
+		{name: "Long passphrase", validate: func() error { return dto.ValidatePassphrase("correct horse") }},
+		{name: "Short passphrase", validate: func() error { return dto.ValidatePassphrase("horse") }, expectError: true},
+		{name: "Passphrase length counts characters", validate: func() error { return dto.ValidatePassphrase("çãõéíóúâ") }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.validate()
+			if tt.expectError && err == nil {
+				t.Errorf("Expected error but got none")
+			}
+			if !tt.expectError && err != nil {
+				t.Errorf("Unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestArchiveMergeRejectsInvalidProducts(t *testing.T) {
+	ctx := context.Background()
+	source, _ := newTestProductService(t, nil)
+	for _, product := range []dto.CreateProductDTO{
+		{Name: "Lamp", Category: "Home", Price: money.FromUnits(20)},
+		{Name: "Robot", Category: "Toys", Price: money.FromUnits(35)},
+	} {
+		if _, err := source.CreateProduct(ctx, product); err != nil {
+			t.Fatalf("Failed to create product: %v", err)
+		}
+	}
+	archivePath := filepath.Join(t.TempDir(), "products.pmarchive")
+	if _, err := source.ExportArchive(ctx, archivePath, "correct horse"); err != nil {
+		t.Fatalf("ExportArchive failed: %v", err)
+	}
+
+	// The target only allows the Home category, so Robot must be left out.
+	target, _ := newRuledProductService(t, config.ValidationConfig{Categories: []string{"Home"}}, nil)
+	result, err := target.ImportArchive(ctx, archivePath, "correct horse", dto.ArchiveMerge)
+	if err != nil {
+		t.Fatalf("ImportArchive failed: %v", err)
+	}
+	merge := result.Merge
+	if merge.Inserted != 1 || merge.Skipped != 0 || len(merge.Rejected) != 1 {
+		t.Fatalf("Expected 1 inserted and 1 rejected product, got %+v", merge)
+	}
+	if rejected := merge.Rejected[0]; rejected.Name != "Robot" || len(rejected.Errors) != 1 || rejected.Errors[0].Field != "category" {
+		t.Errorf("Expected Robot to be rejected for its category, got %+v", rejected)
+	}
+	page, err := target.GetAllProducts(ctx, dto.PaginationDTO{Page: 1, PageSize: 10})
+	if err != nil {
+		t.Fatalf("Failed to list products: %v", err)
+	}
+	if page.TotalCount != 1 || page.Products[0].Name != "Lamp" {
+		t.Errorf("Expected only Lamp to be merged, got %+v", page.Products)
+	}
+}
+
+// archiveTemps lists the plain archive databases in dir.
+func archiveTemps(t *testing.T, dir string) []string {
+	t.Helper()
+	var paths []string
+	for _, pattern := range []string{"export-*.db", "import-*.db"} {
+		matches, err := filepath.Glob(filepath.Join(dir, pattern))
+		if err != nil {
+			t.Fatalf("Glob failed: %v", err)
+		}
+		paths = append(paths, matches...)
+	}
+	return paths
+}
+
+func TestArchiveTemporaryFiles(t *testing.T) {
+	ctx := context.Background()
+	products, cfg := newTestProductService(t, nil)
+	createServiceProduct(t, products, "Lamp")
+	dir := cfg.Backup.Dir
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		t.Fatalf("Failed to create backup directory: %v", err)
+	}
+
+	stale := []string{"export-20240131-020000.db", "import-20240131-020000.db", "import-123456.db"}
+	kept := []string{"backup-20240131-020000.db", "pre-restore-20240131-020000.db", "notes.txt"}
+	for _, name := range append(append([]string{}, stale...), kept...) {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte("SQLite format 3\x00"), 0o600); err != nil {
+			t.Fatalf("Failed to write %s: %v", name, err)
+		}
+	}
+	products.RemoveStaleArchiveFiles()
+	if temps := archiveTemps(t, dir); len(temps) != 0 {
+		t.Errorf("Expected stale archive databases to be removed, got %v", temps)
+	}
+	for _, name := range kept {
+		if _, err := os.Stat(filepath.Join(dir, name)); err != nil {
+			t.Errorf("Expected %s to be kept: %v", name, err)
+		}
+	}
+
+	archivePath := filepath.Join(t.TempDir(), "products.pmarchive")
+	if _, err := products.ExportArchive(ctx, archivePath, "correct horse"); err != nil {
+		t.Fatalf("ExportArchive failed: %v", err)
+	}
+	if temps := archiveTemps(t, dir); len(temps) != 0 {
+		t.Errorf("Expected no plain database after the export, got %v", temps)
+	}
+
+	tests := []struct {
+		name        string
+		passphrase  string
+		expectError bool
+	}{
+		{name: "Wrong passphrase", passphrase: "wrong horse", expectError: true},
+		{name: "Merge", passphrase: "correct horse"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := products.ImportArchive(ctx, archivePath, tt.passphrase, dto.ArchiveMerge)
+			if tt.expectError != (err != nil) {
+				t.Fatalf("Expected error %v, got %v", tt.expectError, err)
+			}
+			if temps := archiveTemps(t, dir); len(temps) != 0 {
+				t.Errorf("Expected no plain database after the import, got %v", temps)
+			}
+		})
+	}
+}
@@ -0,0 +1,386 @@
+package test
+
+import (
+	"context"
+	"database/sql"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"sync"
+	"testing"
+
+	"product-management-app/core/config"
+	"product-management-app/core/dto"
+	apperrors "product-management-app/core/errors"
+	"product-management-app/core/money"
+	service "product-management-app/core/services"
+	"product-management-app/core/sqlite"
+	"product-management-app/core/validation"
+)
+
+// newTestProductService opens a product service on a new database in a
+// temporary directory, with scheduled backups off and the default
+// validation rules.
+func newTestProductService(t *testing.T, emit service.EventEmitter) (*service.ProductService, config.DatabaseConfig) {
+	t.Helper()
+	return newRuledProductService(t, config.ValidationConfig{}, emit)
+}
+
+// newRuledProductService is newTestProductService with the validation
+// rules products must satisfy.
+func newRuledProductService(t *testing.T, rules config.ValidationConfig, emit service.EventEmitter) (*service.ProductService, config.DatabaseConfig) {
+	t.Helper()
+	scheduled := false
+	cfg := config.DatabaseConfig{
+		Path:       filepath.Join(t.TempDir(), "products.db"),
+		PathSource: config.SourceFlag,
+		Backup:     config.BackupConfig{Scheduled: &scheduled},
+	}
+	if err := cfg.ApplyDefaults(); err != nil {
+		t.Fatalf("ApplyDefaults failed: %v", err)
+	}
+	products := service.NewProductService(cfg, validation.NewProductValidator(rules), slog.Default(), emit)
+	if err := products.InitDatabase(); err != nil {
+		t.Fatalf("Failed to initialize database: %v", err)
+	}
+	t.Cleanup(products.CloseDatabase)
+	return products, cfg
+}
+
+func createServiceProduct(t *testing.T, products *service.ProductService, name string) {
+	t.Helper()
+	if _, err := products.CreateProduct(context.Background(), dto.CreateProductDTO{Name: name, Price: money.FromUnits(10)}); err != nil {
+		t.Fatalf("Failed to create product: %v", err)
+	}
+}
+
+func TestRestoreBackupWhileCallsRun(t *testing.T) {
+	ctx := context.Background()
+	products, _ := newTestProductService(t, nil)
+	createServiceProduct(t, products, "Keyboard")
+	backupPath := filepath.Join(t.TempDir(), "before.db")
+	if _, err := products.BackupDatabase(ctx, backupPath); err != nil {
+		t.Fatalf("Backup failed: %v", err)
+	}
+	createServiceProduct(t, products, "Mouse")
+
+	// Calls running while the database is swapped must never see it closed.
+	stop := make(chan struct{})
+	var wg sync.WaitGroup
+	for range 4 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case <-stop:
+					return
+				default:
+				}
+				if _, err := products.GetAllProducts(ctx, dto.PaginationDTO{Page: 1, PageSize: 10}); err != nil {
+					t.Errorf("Call failed during restore: %v", err)
+					return
+				}
+			}
+		}()
+	}
+
+	_, err := products.RestoreBackup(ctx, backupPath)
+	close(stop)
+	wg.Wait()
+	if err != nil {
+		t.Fatalf("Restore failed: %v", err)
+	}
+	page, err := products.GetAllProducts(ctx, dto.PaginationDTO{Page: 1, PageSize: 10})
+	if err != nil {
+		t.Fatalf("Failed to list products: %v", err)
+	}
+	if page.TotalCount != 1 || page.Products[0].Name != "Keyboard" {
+		t.Errorf("Expected only the backed up product, got %+v", page.Products)
+	}
+}
+
+// newTestBackupService opens a new database in a temporary directory and
+// returns a backup service for it configured by backup.
+func newTestBackupService(t *testing.T, backup config.BackupConfig) (*service.BackupService, *service.DatabaseService) {
+	t.Helper()
+	cfg := config.DatabaseConfig{
+		Path:       filepath.Join(t.TempDir(), "products.db"),
+		PathSource: config.SourceFlag,
+		Backup:     backup,
+	}
+	if err := cfg.ApplyDefaults(); err != nil {
+		t.Fatalf("ApplyDefaults failed: %v", err)
+	}
+	db := service.NewDatabaseService(slog.Default(), cfg)
+	if err := db.InitDatabase(); err != nil {
+		t.Fatalf("Failed to initialize database: %v", err)
+	}
+	t.Cleanup(db.CloseDatabase)
+	return service.NewBackupService(slog.Default(), db), db
+}
+
+// writeTempFile writes data to a new file in a temporary directory.
+func writeTempFile(t *testing.T, name string, data []byte) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, data, 0600); err != nil {
+		t.Fatalf("Failed to write %s: %v", name, err)
+	}
+	return path
+}
+
+// openSQLiteFile opens the SQLite database at path, creating it if needed.
+func openSQLiteFile(t *testing.T, path string) *sql.DB {
+	t.Helper()
+	db, err := sqlite.Open(path, sqlite.Options{})
+	if err != nil {
+		t.Fatalf("Failed to open %s: %v", path, err)
+	}
+	return db
+}
+
+func TestBackupValidate(t *testing.T) {
+	ctx := context.Background()
+	scheduled := false
+	backups, db := newTestBackupService(t, config.BackupConfig{Scheduled: &scheduled})
+	insertProducts(t, db.DB,
+		dto.CreateProductDTO{Name: "Keyboard", Price: money.FromMinorUnits(4999), Category: "Electronics", Stock: 5},
+		dto.CreateProductDTO{Name: "Mouse", Price: money.FromMinorUnits(1999), Category: "Electronics", Stock: 10},
+	)
+
+	// backup writes a fresh backup of the database for a test case.
+	backup := func(t *testing.T) string {
+		t.Helper()
+		path := filepath.Join(t.TempDir(), "backup.db")
+		if _, err := backups.Backup(ctx, path); err != nil {
+			t.Fatalf("Backup failed: %v", err)
+		}
+		return path
+	}
+
+	tests := []struct {
+		name        string
+		prepare     func(t *testing.T) string
+		expectError bool
+	}{
+		{name: "Backup of the open database", prepare: backup},
+		{name: "Truncated backup", expectError: true, prepare: func(t *testing.T) string {
+			path := backup(t)
+			info, err := os.Stat(path)
+			if err != nil {
+				t.Fatalf("Failed to read backup: %v", err)
+			}
+			if err := os.Truncate(path, info.Size()/2); err != nil {
+				t.Fatalf("Failed to truncate backup: %v", err)
+			}
+			return path
+		}},
+		{name: "Backup newer than the application", expectError: true, prepare: func(t *testing.T) string {
+			path := backup(t)
+			newer := openSQLiteFile(t, path)
+			defer func() {
+				_ = newer.Close()
+			}()
+			if _, err := newer.Exec("INSERT INTO schema_migrations (version, name, checksum) VALUES (?, 'future', 'future')", db.SchemaVersion+1); err != nil {
+				t.Fatalf("Failed to record a newer migration: %v", err)
+			}
+			return path
+		}},
+		{name: "SQLite database without products", expectError: true, prepare: func(t *testing.T) string {
+			path := filepath.Join(t.TempDir(), "other.db")
+			other := openSQLiteFile(t, path)
+			defer func() {
+				_ = other.Close()
+			}()
+			if _, err := other.Exec("CREATE TABLE notes (body TEXT)"); err != nil {
+				t.Fatalf("Failed to create table: %v", err)
+			}
+			return path
+		}},
+		{name: "Not a SQLite file", expectError: true, prepare: func(t *testing.T) string {
+			return writeTempFile(t, "products.csv", []byte("Name,Price\nKeyboard,49.99\n"))
+		}},
+		{name: "Empty file", expectError: true, prepare: func(t *testing.T) string {
+			return writeTempFile(t, "empty.db", nil)
+		}},
+		{name: "Directory", expectError: true, prepare: func(t *testing.T) string {
+			return t.TempDir()
+		}},
+		{name: "Missing file", expectError: true, prepare: func(t *testing.T) string {
+			return filepath.Join(t.TempDir(), "missing.db")
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := backups.Validate(ctx, tt.prepare(t))
+			if tt.expectError {
+				if err == nil {
+					t.Errorf("Expected error but got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if result.ProductCount != 2 || result.SchemaVersion != db.SchemaVersion || result.Size == 0 {
+				t.Errorf("Expected a backup of 2 products at schema version %d, got %+v", db.SchemaVersion, result)
+			}
+		})
+	}
+}
+
+func TestScheduledBackupRotation(t *testing.T) {
+	ctx := context.Background()
+	scheduled := true
+	backups, db := newTestBackupService(t, config.BackupConfig{Scheduled: &scheduled, IntervalHours: 24, Keep: 2})
+	dir := db.Config.Backup.Dir
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		t.Fatalf("Failed to create backup dir: %v", err)
+	}
+	old := []string{"backup-20240101-020000.db", "backup-20240102-020000.db", "backup-20240103-020000.db"}
+	// Only scheduled backups are rotated; other files are left alone.
+	kept := []string{"pre-restore-20240101-020000.db", "manual.db"}
+	for _, name := range append(old, kept...) {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte("backup"), 0600); err != nil {
+			t.Fatalf("Failed to write %s: %v", name, err)
+		}
+	}
+
+	// The newest backup is older than the interval, so starting the
+	// schedule takes a backup at once; Stop waits for it to finish.
+	backups.Start()
+	backups.Stop()
+
+	list, err := backups.List()
+	if err != nil {
+		t.Fatalf("List failed: %v", err)
+	}
+	if len(list) != 2 {
+		t.Fatalf("Expected 2 backups to be kept, got %+v", list)
+	}
+	if _, err := backups.Validate(ctx, list[0].Path); err != nil {
+		t.Errorf("Expected the new backup to be valid, got %v", err)
+	}
+	if list[1].Path != filepath.Join(dir, old[2]) {
+		t.Errorf("Expected the newest old backup to be kept, got %s", list[1].Path)
+	}
+	for _, name := range old[:2] {
+		if _, err := os.Stat(filepath.Join(dir, name)); !os.IsNotExist(err) {
+			t.Errorf("Expected %s to be deleted, got %v", name, err)
+		}
+	}
+	for _, name := range kept {
+		if _, err := os.Stat(filepath.Join(dir, name)); err != nil {
+			t.Errorf("Expected %s to be kept, got %v", name, err)
+		}
+	}
+}
+
+func TestSafetyBackupRotation(t *testing.T) {
+	ctx := context.Background()
+	scheduled := false
+	backups, db := newTestBackupService(t, config.BackupConfig{Scheduled: &scheduled, KeepSafety: 2})
+	dir := db.Config.Backup.Dir
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		t.Fatalf("Failed to create backup dir: %v", err)
+	}
+	// A numbered name is newer than the unnumbered one from the same second.
+	old := []string{"pre-restore-20240101-020000.db", "pre-restore-20240102-020000.db", "pre-restore-20240102-020000-2.db"}
+	scheduledBackup := "backup-20240101-020000.db"
+	for _, name := range append(old, scheduledBackup) {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte("backup"), 0600); err != nil {
+			t.Fatalf("Failed to write %s: %v", name, err)
+		}
+	}
+
+	// Restoring the oldest safety backup keeps it even though it is beyond
+	// the count.
+	first, err := backups.SafetyBackup(ctx, filepath.Join(dir, old[0]))
+	if err != nil {
+		t.Fatalf("SafetyBackup failed: %v", err)
+	}
+	for name, want := range map[string]bool{filepath.Base(first): true, old[2]: true, old[0]: true, old[1]: false, scheduledBackup: true} {
+		if _, err := os.Stat(filepath.Join(dir, name)); (err == nil) != want {
+			t.Errorf("Expected %s to be kept: %v, got %v", name, want, err)
+		}
+	}
+
+	second, err := backups.SafetyBackup(ctx, "")
+	if err != nil {
+		t.Fatalf("SafetyBackup failed: %v", err)
+	}
+	if second == first {
+		t.Fatalf("Expected a new safety backup, got %s again", second)
+	}
+	for name, want := range map[string]bool{filepath.Base(first): true, filepath.Base(second): true, old[2]: false, old[0]: false, scheduledBackup: true} {
+		if _, err := os.Stat(filepath.Join(dir, name)); (err == nil) != want {
+			t.Errorf("Expected %s to be kept: %v, got %v", name, want, err)
+		}
+	}
+}
+
+func TestRestoreBackupRoundTrip(t *testing.T) {
+	ctx := context.Background()
+	products, _ := newTestProductService(t, nil)
+	createServiceProduct(t, products, "Keyboard")
+	createServiceProduct(t, products, "Mouse")
+	pagination := dto.PaginationDTO{Page: 1, PageSize: 10}
+	before, err := products.GetAllProducts(ctx, pagination)
+	if err != nil {
+		t.Fatalf("Failed to list products: %v", err)
+	}
+	backupPath := filepath.Join(t.TempDir(), "before.db")
+	if _, err := products.BackupDatabase(ctx, backupPath); err != nil {
+		t.Fatalf("Backup failed: %v", err)
+	}
+
+	keyboard := before.Products[0]
+	renamed := "Mechanical keyboard"
+	if _, err := products.UpdateProduct(ctx, keyboard.ID, dto.UpdateProductDTO{Version: keyboard.Version, Name: &renamed}); err != nil {
+		t.Fatalf("Update failed: %v", err)
+	}
+	mouse := before.Products[1]
+	if err := products.DeleteProduct(ctx, mouse.ID, mouse.Version); err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+	createServiceProduct(t, products, "Lamp")
+
+	// A file that is not a backup is rejected before anything is replaced.
+	notBackup := writeTempFile(t, "products.csv", []byte("Name,Price\n"))
+	if _, err := products.RestoreBackup(ctx, notBackup); apperrors.CodeOf(err) != apperrors.CodeValidation {
+		t.Fatalf("Expected a validation error, got %v", err)
+	}
+
+	result, err := products.RestoreBackup(ctx, backupPath)
+	if err != nil {
+		t.Fatalf("Restore failed: %v", err)
+	}
+	if result.Restored.ProductCount != 2 {
+		t.Errorf("Expected a backup of 2 products, got %+v", result.Restored)
+	}
+	after, err := products.GetAllProducts(ctx, pagination)
+	if err != nil {
+		t.Fatalf("Failed to list products: %v", err)
+	}
+	if !reflect.DeepEqual(after.Products, before.Products) {
+		t.Errorf("Expected the backed up products, got %v", productNames(after.Products))
+	}
+
+	// The safety backup holds the database as it was before the restore.
+	if _, err := products.RestoreBackup(ctx, result.SafetyBackup); err != nil {
+		t.Fatalf("Restoring the safety backup failed: %v", err)
+	}
+	replaced, err := products.GetAllProducts(ctx, pagination)
+	if err != nil {
+		t.Fatalf("Failed to list products: %v", err)
+	}
+	names := productNames(replaced.Products)
+	sort.Strings(names)
+	if !reflect.DeepEqual(names, []string{"Lamp", "Mechanical keyboard"}) {
+		t.Errorf("Expected the replaced products, got %v", names)
+	}
+}
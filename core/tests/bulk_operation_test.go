@@ -0,0 +1,61 @@
+package test
+
+import (
+	"testing"
+
+	"product-management-app/core/dto"
+	"product-management-app/core/money"
+)
+
+func TestPriceAdjustmentApply(t *testing.T) {
+	tests := []struct {
+		name       string
+		adjustment dto.PriceAdjustmentDTO
+		price      money.Money
+		expected   money.Money
+	}{
+		{name: "Percent increase", adjustment: dto.PriceAdjustmentDTO{Type: dto.AdjustByPercent, Amount: 10}, price: 1999, expected: 2199},
+		{name: "Percent decrease", adjustment: dto.PriceAdjustmentDTO{Type: dto.AdjustByPercent, Amount: -25}, price: 1000, expected: 750},
+		{name: "Fixed amount", adjustment: dto.PriceAdjustmentDTO{Type: dto.AdjustByAmount, Amount: 0.2}, price: 10, expected: 30},
+		{name: "Round up to nickel", adjustment: dto.PriceAdjustmentDTO{Type: dto.AdjustByPercent, Amount: 10, Rounding: dto.RoundUp, Increment: 5}, price: 110, expected: 125},
+		{name: "Round down to whole", adjustment: dto.PriceAdjustmentDTO{Type: dto.AdjustByAmount, Amount: 0.99, Rounding: dto.RoundDown, Increment: 100}, price: 1000, expected: 1000},
+		{name: "Exact multiple not rounded up", adjustment: dto.PriceAdjustmentDTO{Type: dto.AdjustByPercent, Amount: 10, Rounding: dto.RoundUp}, price: 100, expected: 110},
+		{name: "Half cent rounded to even", adjustment: dto.PriceAdjustmentDTO{Type: dto.AdjustByPercent, Amount: 50, Rounding: dto.RoundHalfEven}, price: 5, expected: 8},
+		{name: "Half cent rounded away from zero", adjustment: dto.PriceAdjustmentDTO{Type: dto.AdjustByPercent, Amount: 50}, price: 3, expected: 5},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.adjustment.Apply(tt.price); got != tt.expected {
+				t.Errorf("Apply(%v) = %v, expected %v", tt.price, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestBulkOperationValidate(t *testing.T) {
+	tests := []struct {
+		name        string
+		op          dto.BulkOperationDTO
+		expectError bool
+	}{
+		{name: "Delete by IDs", op: dto.BulkOperationDTO{Operation: dto.BulkDelete, ProductIDs: []int{1, 2}}},
+		{name: "Set category by filter", op: dto.BulkOperationDTO{Operation: dto.BulkSetCategory, Filters: &dto.ProductFilterDTO{}, Category: "Tools"}},
+		{name: "No selection", op: dto.BulkOperationDTO{Operation: dto.BulkDelete}, expectError: true},
+		{name: "Unknown operation", op: dto.BulkOperationDTO{Operation: "archive", ProductIDs: []int{1}}, expectError: true},
+		{name: "Negative price", op: dto.BulkOperationDTO{Operation: dto.BulkSetPrice, ProductIDs: []int{1}, Price: -100}, expectError: true},
+		{name: "Missing adjustment", op: dto.BulkOperationDTO{Operation: dto.BulkAdjustPrice, ProductIDs: []int{1}}, expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.op.Validate()
+			if tt.expectError && err == nil {
+				t.Errorf("Expected error but got none")
+			}
+			if !tt.expectError && err != nil {
+				t.Errorf("Unexpected error: %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,186 @@
+package test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"product-management-app/core/config"
+)
+
+func TestConfigDatabasePathPrecedence(t *testing.T) {
+	dir := t.TempDir()
+	configFile := filepath.Join(dir, "config.json")
+	if err := os.WriteFile(configFile, []byte(`{"database": {"path": "from-file.db"}}`), 0600); err != nil {
+		t.Fatalf("Failed to write config file: %v", err)
+	}
+	t.Setenv(config.EnvConfigFile, configFile)
+	t.Setenv(config.EnvDatabasePath, "")
+
+	cfg, err := config.Load(nil)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if cfg.Database.PathSource != config.SourceFile || cfg.Database.Path != filepath.Join(dir, "from-file.db") {
+		t.Errorf("Expected path from config file, got %s (%s)", cfg.Database.Path, cfg.Database.PathSource)
+	}
+
+	envPath := filepath.Join(dir, "from-env.db")
+	t.Setenv(config.EnvDatabasePath, envPath)
+	cfg, _ = config.Load(nil)
+	if cfg.Database.PathSource != config.SourceEnv || cfg.Database.Path != envPath {
+		t.Errorf("Expected path from environment, got %s (%s)", cfg.Database.Path, cfg.Database.PathSource)
+	}
+
+	flagPath := filepath.Join(dir, "from-flag.db")
+	cfg, _ = config.Load([]string{"-db-path", flagPath})
+	if cfg.Database.PathSource != config.SourceFlag || cfg.Database.Path != flagPath {
+		t.Errorf("Expected path from flag, got %s (%s)", cfg.Database.Path, cfg.Database.PathSource)
+	}
+}
+
+func TestConfigIgnoresUnknownArgs(t *testing.T) {
+	dir := t.TempDir()
+	configFile := filepath.Join(dir, "custom.json")
+	if err := os.WriteFile(configFile, []byte(`{"database": {"path": "from-file.db"}}`), 0600); err != nil {
+		t.Fatalf("Failed to write config file: %v", err)
+	}
+	t.Setenv(config.EnvConfigFile, "")
+	t.Setenv(config.EnvDatabasePath, "")
+	flagPath := filepath.Join(dir, "from-flag.db")
+
+	tests := []struct {
+		name         string
+		args         []string
+		expectedFile string
+		expectedPath string
+	}{
+		{name: "Finder process serial number first", args: []string{"-psn_0_12345", "-config", configFile, "-db-path", flagPath}, expectedFile: configFile, expectedPath: flagPath},
+		{name: "Unknown flag between", args: []string{"-config", configFile, "-NSDocumentRevisionsDebugMode", "YES", "--db-path=" + flagPath}, expectedFile: configFile, expectedPath: flagPath},
+		{name: "Positional argument first", args: []string{"products.csv", "-config=" + configFile}, expectedFile: configFile, expectedPath: filepath.Join(dir, "from-file.db")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg, err := config.Load(tt.args)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if cfg.File != tt.expectedFile {
+				t.Errorf("Expected config file %s, got %s", tt.expectedFile, cfg.File)
+			}
+			if cfg.Database.Path != tt.expectedPath {
+				t.Errorf("Expected database path %s, got %s", tt.expectedPath, cfg.Database.Path)
+			}
+		})
+	}
+}
+
+func TestConfigDefaultDatabasePath(t *testing.T) {
+	t.Setenv(config.EnvConfigFile, filepath.Join(t.TempDir(), "missing.json"))
+	t.Setenv(config.EnvDatabasePath, "")
+
+	cfg, err := config.Load(nil)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if cfg.Database.PathSource != config.SourceDefault {
+		t.Errorf("Expected default source, got %s", cfg.Database.PathSource)
+	}
+	expectedSuffix := filepath.Join(config.AppName, config.DefaultDatabaseFile)
+	if !filepath.IsAbs(cfg.Database.Path) || filepath.Base(filepath.Dir(cfg.Database.Path)) != config.AppName {
+		t.Errorf("Expected default path ending in %s, got %s", expectedSuffix, cfg.Database.Path)
+	}
+}
+
+func TestDatabaseConfigApplyDefaults(t *testing.T) {
+	tests := []struct {
+		name        string
+		input       config.DatabaseConfig
+		expectError bool
+		journalMode string
+		maxIdle     int
+	}{
+		{
+			name:        "Empty config uses defaults",
+			input:       config.DatabaseConfig{},
+			expectError: false,
+			journalMode: config.DefaultJournalMode,
+			maxIdle:     config.DefaultMaxIdleConns,
+		},
+		{
+			name:        "Lowercase journal mode is accepted",
+			input:       config.DatabaseConfig{JournalMode: "delete"},
+			expectError: false,
+			journalMode: "DELETE",
+			maxIdle:     config.DefaultMaxIdleConns,
+		},
+		{
+			name:        "Invalid journal mode falls back to default",
+			input:       config.DatabaseConfig{JournalMode: "fast"},
+			expectError: true,
+			journalMode: config.DefaultJournalMode,
+			maxIdle:     config.DefaultMaxIdleConns,
+		},
+		{
+			name:        "Idle connections are capped by open connections",
+			input:       config.DatabaseConfig{MaxOpenConns: 2, MaxIdleConns: 8},
+			expectError: false,
+			journalMode: config.DefaultJournalMode,
+			maxIdle:     2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := tt.input
+			err := cfg.ApplyDefaults()
+			if tt.expectError && err == nil {
+				t.Errorf("Expected error but got none")
+			}
+			if !tt.expectError && err != nil {
+				t.Errorf("Unexpected error: %v", err)
+			}
+			if cfg.JournalMode != tt.journalMode {
+				t.Errorf("Expected journal mode %s, got %s", tt.journalMode, cfg.JournalMode)
+			}
+			if cfg.MaxIdleConns != tt.maxIdle {
+				t.Errorf("Expected %d idle connections, got %d", tt.maxIdle, cfg.MaxIdleConns)
+			}
+			if cfg.ForeignKeys == nil || !*cfg.ForeignKeys {
+				t.Errorf("Expected foreign keys to be enabled by default")
+			}
+		})
+	}
+}
+
+func TestConfigBackupDefaults(t *testing.T) {
+	dir := t.TempDir()
+	configFile := filepath.Join(dir, "config.json")
+	if err := os.WriteFile(configFile, []byte(`{"database": {"path": "catalog.db", "backup": {"dir": "saved", "keep": 3}}}`), 0600); err != nil {
+		t.Fatalf("Failed to write config file: %v", err)
+	}
+	t.Setenv(config.EnvConfigFile, configFile)
+	t.Setenv(config.EnvDatabasePath, "")
+
+	cfg, err := config.Load(nil)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	backup := cfg.Database.Backup
+	if backup.Dir != filepath.Join(dir, "saved") {
+		t.Errorf("Expected backup dir relative to the config file, got %s", backup.Dir)
+	}
+	if backup.Keep != 3 || backup.IntervalHours != config.DefaultBackupIntervalHours {
+		t.Errorf("Expected keep 3 and default interval, got keep %d interval %d", backup.Keep, backup.IntervalHours)
+	}
+	if backup.Scheduled == nil || !*backup.Scheduled {
+		t.Errorf("Expected scheduled backups to be enabled by default")
+	}
+
+	cfg.Database.Backup = config.BackupConfig{}
+	_ = cfg.Database.ApplyDefaults()
+	if cfg.Database.Backup.Dir != filepath.Join(dir, config.DefaultBackupDir) {
+		t.Errorf("Expected default backup dir next to the database, got %s", cfg.Database.Backup.Dir)
+	}
+}
@@ -1,17 +1,17 @@
 package test
 
 import (
-	"context"
+	"log/slog"
 	"testing"
 	"time"
 
 	"product-management-app/core/dto"
+	"product-management-app/core/money"
 	service "product-management-app/core/services"
 )
 
 func TestCurrencyService_ConvertCurrency(t *testing.T) {
-	ctx := context.Background()
-	currencyService := service.NewCurrencyService(ctx)
+	currencyService := service.NewCurrencyService(slog.Default())
 
 	tests := []struct {
 		name        string
@@ -21,7 +21,7 @@ func TestCurrencyService_ConvertCurrency(t *testing.T) {
 		{
 			name: "Valid conversion USD to EUR",
 			request: dto.CurrencyConversionRequest{
-				Amount:       100.0,
+				Amount:       money.FromUnits(100),
 				FromCurrency: "USD",
 				ToCurrency:   "EUR",
 			},
@@ -30,7 +30,7 @@ func TestCurrencyService_ConvertCurrency(t *testing.T) {
 		{
 			name: "Valid conversion EUR to BRL",
 			request: dto.CurrencyConversionRequest{
-				Amount:       50.0,
+				Amount:       money.FromUnits(50),
 				FromCurrency: "EUR",
 				ToCurrency:   "BRL",
 			},
@@ -39,7 +39,7 @@ func TestCurrencyService_ConvertCurrency(t *testing.T) {
 		{
 			name: "Same currency conversion",
 			request: dto.CurrencyConversionRequest{
-				Amount:       100.0,
+				Amount:       money.FromUnits(100),
 				FromCurrency: "USD",
 				ToCurrency:   "USD",
 			},
@@ -48,7 +48,7 @@ func TestCurrencyService_ConvertCurrency(t *testing.T) {
 		{
 			name: "Negative amount",
 			request: dto.CurrencyConversionRequest{
-				Amount:       -100.0,
+				Amount:       money.FromUnits(-100),
 				FromCurrency: "USD",
 				ToCurrency:   "EUR",
 			},
@@ -79,7 +79,7 @@ func TestCurrencyService_ConvertCurrency(t *testing.T) {
 
 			// Validate response fields
 			if response.Amount != tt.request.Amount {
-				t.Errorf("Expected amount %f, got %f", tt.request.Amount, response.Amount)
+				t.Errorf("Expected amount %s, got %s", tt.request.Amount, response.Amount)
 			}
 
 			if response.FromCurrency != tt.request.FromCurrency {
@@ -96,7 +96,7 @@ func TestCurrencyService_ConvertCurrency(t *testing.T) {
 					t.Errorf("Expected exchange rate 1.0 for same currency, got %f", response.ExchangeRate)
 				}
 				if response.ConvertedAmount != tt.request.Amount {
-					t.Errorf("Expected converted amount %f for same currency, got %f", tt.request.Amount, response.ConvertedAmount)
+					t.Errorf("Expected converted amount %s for same currency, got %s", tt.request.Amount, response.ConvertedAmount)
 				}
 			}
 
@@ -109,8 +109,7 @@ func TestCurrencyService_ConvertCurrency(t *testing.T) {
 }
 
 func TestCurrencyService_GetSupportedCurrencies(t *testing.T) {
-	ctx := context.Background()
-	currencyService := service.NewCurrencyService(ctx)
+	currencyService := service.NewCurrencyService(slog.Default())
 
 	response := currencyService.GetSupportedCurrencies()
 
@@ -151,8 +150,7 @@ func TestCurrencyService_GetSupportedCurrencies(t *testing.T) {
 }
 
 func TestCurrencyService_GetExchangeRatesForCurrency(t *testing.T) {
-	ctx := context.Background()
-	currencyService := service.NewCurrencyService(ctx)
+	currencyService := service.NewCurrencyService(slog.Default())
 
 	// Test with EUR as base currency
 	response, err := currencyService.GetExchangeRatesForCurrency("EUR")
@@ -180,3 +178,53 @@ func TestCurrencyService_GetExchangeRatesForCurrency(t *testing.T) {
 		t.Errorf("Expected date but got empty string")
 	}
 }
+
+func TestCurrencyService_ConvertCurrencyRounding(t *testing.T) {
+	currencyService := service.NewCurrencyService(slog.Default())
+
+	tests := []struct {
+		name        string
+		request     dto.CurrencyConversionRequest
+		expected    money.Money
+		expectError bool
+	}{
+		{
+			name:     "Cents kept for two-decimal currencies",
+			request:  dto.CurrencyConversionRequest{Amount: money.FromMinorUnits(1999), FromCurrency: "USD", ToCurrency: "USD"},
+			expected: 1999,
+		},
+		{
+			name:     "Yen rounded to whole units",
+			request:  dto.CurrencyConversionRequest{Amount: money.FromMinorUnits(10050), FromCurrency: "JPY", ToCurrency: "JPY"},
+			expected: 10100,
+		},
+		{
+			name:     "Yen rounded half to even",
+			request:  dto.CurrencyConversionRequest{Amount: money.FromMinorUnits(10050), FromCurrency: "JPY", ToCurrency: "JPY", Rounding: money.RoundHalfEven},
+			expected: 10000,
+		},
+		{
+			name:        "Unknown rounding mode",
+			request:     dto.CurrencyConversionRequest{Amount: money.FromUnits(1), FromCurrency: "USD", ToCurrency: "USD", Rounding: "sideways"},
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			response, err := currencyService.ConvertCurrency(tt.request)
+			if tt.expectError {
+				if err == nil {
+					t.Errorf("Expected error but got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if response.ConvertedAmount != tt.expected {
+				t.Errorf("Expected %s, got %s", tt.expected, response.ConvertedAmount)
+			}
+		})
+	}
+}
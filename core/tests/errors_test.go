@@ -0,0 +1,121 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"product-management-app/core/dto"
+	apperrors "product-management-app/core/errors"
+	"product-management-app/core/money"
+	"product-management-app/core/repositories"
+)
+
+func TestErrorCodes(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		expected apperrors.Code
+	}{
+		{name: "Not found", err: apperrors.NotFound("product", 7), expected: apperrors.CodeNotFound},
+		{name: "Wrapped not found", err: fmt.Errorf("failed to fetch product: %w", apperrors.NotFound("product", 7)), expected: apperrors.CodeNotFound},
+		{name: "Validation", err: apperrors.Validation("name", "name is required"), expected: apperrors.CodeValidation},
+		{name: "Conflict", err: apperrors.VersionConflict(7, 1, 2), expected: apperrors.CodeConflict},
+		{name: "Unavailable", err: apperrors.Unavailable(errors.New("dial failed"), "exchange rates unavailable"), expected: apperrors.CodeUnavailable},
+		{name: "Cancelled", err: apperrors.Cancelled("operation cancelled by user"), expected: apperrors.CodeCancelled},
+		{name: "Context canceled", err: fmt.Errorf("import stopped: %w", context.Canceled), expected: apperrors.CodeCancelled},
+		{name: "Deadline exceeded", err: context.DeadlineExceeded, expected: apperrors.CodeCancelled},
+		{name: "Plain error", err: errors.New("disk full"), expected: apperrors.CodeInternal},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if code := apperrors.CodeOf(tt.err); code != tt.expected {
+				t.Errorf("Expected code %s, got %s", tt.expected, code)
+			}
+			response := apperrors.ToResponse(tt.err)
+			if response.Code != tt.expected || response.Message != tt.err.Error() {
+				t.Errorf("Unexpected response %+v", response)
+			}
+		})
+	}
+}
+
+func TestErrorResponses(t *testing.T) {
+	if response := apperrors.ToResponse(nil); response != nil {
+		t.Errorf("Expected no response for a nil error, got %+v", response)
+	}
+
+	validation := new(apperrors.ValidationError).
+		Add("name", "name is required").
+		Add("price", "price must not be negative")
+	response := apperrors.ToResponse(validation)
+	if len(response.Fields) != 2 || response.Fields[1].Field != "price" {
+		t.Errorf("Expected both invalid fields, got %+v", response.Fields)
+	}
+	if response.Message != "name is required; price must not be negative" {
+		t.Errorf("Unexpected message %q", response.Message)
+	}
+	if err := new(apperrors.ValidationError).OrNil(); err != nil {
+		t.Errorf("Expected an empty validation error to be nil, got %v", err)
+	}
+
+	response = apperrors.ToResponse(apperrors.VersionConflict(7, 1, 3))
+	if response.Details["currentVersion"] != 3 || response.Details["id"] != 7 {
+		t.Errorf("Expected the conflicting versions in the details, got %+v", response.Details)
+	}
+
+	response = apperrors.ToResponse(fmt.Errorf("search failed: %w", context.DeadlineExceeded))
+	if response.Details["timedOut"] != true {
+		t.Errorf("Expected a deadline to be reported as timed out, got %+v", response.Details)
+	}
+}
+
+func TestStoreErrorsAreTyped(t *testing.T) {
+	ctx := context.Background()
+	store := repositories.NewMemoryProductStore()
+
+	tests := []struct {
+		name     string
+		call     func() error
+		expected apperrors.Code
+	}{
+		{
+			name: "Missing product",
+			call: func() error {
+				_, err := store.GetByID(ctx, 42)
+				return err
+			},
+			expected: apperrors.CodeNotFound,
+		},
+		{
+			name: "Invalid page",
+			call: func() error {
+				_, err := store.GetAll(ctx, dto.PaginationDTO{Page: 0, PageSize: 10})
+				return err
+			},
+			expected: apperrors.CodeValidation,
+		},
+		{
+			name: "Restore a live product",
+			call: func() error {
+				product, err := store.Create(ctx, dto.CreateProductDTO{Name: "Lamp", Price: money.FromUnits(10)})
+				if err != nil {
+					return err
+				}
+				return store.Restore(ctx, product.ID, product.Version)
+			},
+			expected: apperrors.CodeNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.call()
+			if code := apperrors.CodeOf(err); code != tt.expected {
+				t.Errorf("Expected code %s, got %s (%v)", tt.expected, code, err)
+			}
+		})
+	}
+}
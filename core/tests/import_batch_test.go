@@ -0,0 +1,181 @@
+package test
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+
+	"product-management-app/core/dto"
+	apperrors "product-management-app/core/errors"
+	service "product-management-app/core/services"
+)
+
+func TestAtomicImport(t *testing.T) {
+	tests := []struct {
+		name        string
+		csv         string
+		atomic      bool
+		rolledBack  bool
+		created     int
+		failed      int
+		expectBatch bool
+	}{
+		{
+			name:       "Failed row writes nothing",
+			csv:        "SKU,Name,Price\nCUP-1,Cup,3\nBAD-1,Bad,cheap\nLAMP-1,Lamp,25\n",
+			atomic:     true,
+			rolledBack: true,
+			failed:     1,
+		},
+		{
+			name:        "Clean file is written",
+			csv:         "SKU,Name,Price\nCUP-1,Cup,3\nBOWL-1,Bowl,4\n",
+			atomic:      true,
+			created:     2,
+			expectBatch: true,
+		},
+		{
+			name:        "Without atomic the other rows are written",
+			csv:         "SKU,Name,Price\nCUP-1,Cup,3\nBAD-1,Bad,cheap\n",
+			created:     1,
+			failed:      1,
+			expectBatch: true,
+		},
+		{
+			name:   "Nothing written records no batch",
+			csv:    "SKU,Name,Price\nLAMP-1,Lamp,19.90\n",
+			atomic: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			store := seedImportStore(t)
+			importer := service.NewImportExportService(slog.Default(), nil, store)
+
+			result, err := importer.ImportFromCSV(ctx, writeTempFile(t, "products.csv", []byte(tt.csv)), dto.ImportOptions{Atomic: tt.atomic})
+			if err != nil {
+				t.Fatalf("Import failed: %v", err)
+			}
+			if result.RolledBack != tt.rolledBack || result.CreatedCount != tt.created || result.ErrorCount != tt.failed {
+				t.Fatalf("Expected rolledBack=%v, %d created and %d failed, got %+v", tt.rolledBack, tt.created, tt.failed, result)
+			}
+			if (result.BatchID != 0) != tt.expectBatch {
+				t.Errorf("Expected a batch: %v, got batch %d", tt.expectBatch, result.BatchID)
+			}
+			if count := countProducts(t, store); count != 4+tt.created {
+				t.Errorf("Expected %d products, got %d", 4+tt.created, count)
+			}
+		})
+	}
+}
+
+func TestRollbackImport(t *testing.T) {
+	ctx := context.Background()
+	store := seedImportStore(t)
+	importer := service.NewImportExportService(slog.Default(), nil, store)
+
+	csv := "SKU,Name,Price,Category,Description,Stock\n" +
+		"CUP-1,Cup,3,,,1\n" +
+		"LAMP-1,Lamp,25,Office,,\n" +
+		"CUP-1,Cup,3,,,8\n"
+	result, err := importer.ImportFromCSV(ctx, writeTempFile(t, "products.csv", []byte(csv)), dto.ImportOptions{Mode: dto.ImportUpsert})
+	if err != nil {
+		t.Fatalf("Import failed: %v", err)
+	}
+	if result.BatchID == 0 || result.CreatedCount != 1 || result.UpdatedCount != 2 {
+		t.Fatalf("Expected a batch with 1 created and 2 updated rows, got %+v", result)
+	}
+
+	batches, err := importer.ListImportBatches(ctx, 10)
+	if err != nil {
+		t.Fatalf("ListImportBatches failed: %v", err)
+	}
+	if len(batches) != 1 || batches[0].Source != "csv" || batches[0].CreatedCount != 1 || batches[0].UpdatedCount != 1 {
+		t.Fatalf("Expected one batch with one product created and one updated, got %+v", batches)
+	}
+
+	batch, err := importer.RollbackImport(ctx, result.BatchID)
+	if err != nil {
+		t.Fatalf("Rollback failed: %v", err)
+	}
+	if batch.RolledBackAt == nil {
+		t.Errorf("Expected the batch to be marked as rolled back, got %+v", batch)
+	}
+	if _, err := store.GetBySKU(ctx, "CUP-1"); apperrors.CodeOf(err) != apperrors.CodeNotFound {
+		t.Errorf("Expected the created product to be gone, got %v", err)
+	}
+	if count := countProducts(t, store); count != 4 {
+		t.Errorf("Expected the 4 seeded products, got %d", count)
+	}
+	lamp, err := store.GetByID(ctx, 1)
+	if err != nil {
+		t.Fatalf("Failed to fetch product: %v", err)
+	}
+	if lamp.Price != 1990 || value(lamp.Category) != "Home" || value(lamp.Description) != "Brass" || lamp.Stock != 3 || value(lamp.SKU) != "LAMP-1" {
+		t.Errorf("Expected Lamp to be restored, got %+v", lamp)
+	}
+
+	// The freed SKU can be imported again.
+	if result, err := importer.ImportFromCSV(ctx, writeTempFile(t, "products.csv", []byte("SKU,Name,Price\nCUP-1,Cup,3\n")), dto.ImportOptions{}); err != nil || result.CreatedCount != 1 {
+		t.Errorf("Expected the rolled back SKU to be free, got %+v (%v)", result, err)
+	}
+
+	if _, err := importer.RollbackImport(ctx, batch.ID); apperrors.CodeOf(err) != apperrors.CodeConflict {
+		t.Errorf("Expected a conflict for a second rollback, got %v", err)
+	}
+	if _, err := importer.RollbackImport(ctx, 99); apperrors.CodeOf(err) != apperrors.CodeNotFound {
+		t.Errorf("Expected an unknown batch not to be found, got %v", err)
+	}
+}
+
+func TestRollbackImportRefusesChangedProducts(t *testing.T) {
+	ctx := context.Background()
+	store := seedImportStore(t)
+	importer := service.NewImportExportService(slog.Default(), nil, store)
+
+	result, err := importer.ImportFromCSV(ctx, writeTempFile(t, "products.csv", []byte("SKU,Name,Price\nCUP-1,Cup,3\nLAMP-1,Lamp,25\n")), dto.ImportOptions{Mode: dto.ImportUpsert})
+	if err != nil {
+		t.Fatalf("Import failed: %v", err)
+	}
+	stock := 5
+	if _, err := store.Update(ctx, 1, dto.UpdateProductDTO{Version: 2, Stock: &stock}); err != nil {
+		t.Fatalf("Failed to update product: %v", err)
+	}
+
+	if _, err := importer.RollbackImport(ctx, result.BatchID); apperrors.CodeOf(err) != apperrors.CodeConflict {
+		t.Fatalf("Expected a conflict, got %v", err)
+	}
+	if _, err := store.GetBySKU(ctx, "CUP-1"); err != nil {
+		t.Errorf("Expected a refused rollback to change nothing, got %v", err)
+	}
+	if lamp, _ := store.GetByID(ctx, 1); lamp == nil || lamp.Price != 2500 || lamp.Stock != 5 {
+		t.Errorf("Expected Lamp to keep its edits, got %+v", lamp)
+	}
+}
+
+func TestCommitImportAtomic(t *testing.T) {
+	ctx := context.Background()
+	store := seedImportStore(t)
+	importer := service.NewImportExportService(slog.Default(), nil, store)
+
+	options := dto.ImportOptions{Mode: dto.ImportUpdateOnly, Atomic: true, DryRun: true}
+	result, err := importer.ImportFromCSV(ctx, writeTempFile(t, "products.csv", []byte("SKU,Stock\nLAMP-1,10\n")), options)
+	if err != nil {
+		t.Fatalf("Preview failed: %v", err)
+	}
+	// The row is stale once Lamp changes, which fails the whole commit.
+	stock := 5
+	if _, err := store.Update(ctx, 1, dto.UpdateProductDTO{Version: 1, Stock: &stock}); err != nil {
+		t.Fatalf("Failed to update product: %v", err)
+	}
+
+	committed, err := importer.CommitImport(ctx, result.Preview.ID)
+	if err != nil {
+		t.Fatalf("Commit failed: %v", err)
+	}
+	if !committed.RolledBack || committed.ErrorCount != 1 || committed.BatchID != 0 {
+		t.Errorf("Expected a rolled back commit, got %+v", committed)
+	}
+}
@@ -0,0 +1,186 @@
+package test
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+
+	"product-management-app/core/dto"
+	apperrors "product-management-app/core/errors"
+	"product-management-app/core/money"
+	"product-management-app/core/repositories"
+	service "product-management-app/core/services"
+)
+
+func TestImportColumnsByHeader(t *testing.T) {
+	tests := []struct {
+		name        string
+		csv         string
+		options     dto.ImportOptions
+		expected    []dto.CreateProductDTO
+		errorFields []string
+	}{
+		{
+			name:     "Template order",
+			csv:      "Name,Price,Category,Stock,Description,Image URL\nLamp,19.90,Home,3,Desk lamp,https://example.com/lamp.png\n",
+			expected: []dto.CreateProductDTO{{Name: "Lamp", Price: 1990, Category: "Home", Stock: 3, Description: "Desk lamp", ImageURL: "https://example.com/lamp.png"}},
+		},
+		{
+			name:     "Export layout with ID and timestamps",
+			csv:      "ID,Name,Price,Category,Stock,Description,Image URL,Created At,Updated At\n7,Lamp,19.90,Home,3,,,2024-01-01 10:00:00,\n",
+			expected: []dto.CreateProductDTO{{Name: "Lamp", Price: 1990, Category: "Home", Stock: 3}},
+		},
+		{
+			name:     "Portuguese headers in another order",
+			csv:      "Preço,Estoque,Nome,Observações,Categoria,Descrição\n5.50,2,Caneca,frágil,Cozinha,Caneca azul\n",
+			expected: []dto.CreateProductDTO{{Name: "Caneca", Price: 550, Category: "Cozinha", Stock: 2, Description: "Caneca azul"}},
+		},
+		{
+			name:     "Headers in any case and spacing",
+			csv:      "\ufeffPRODUCT NAME,unit_price,IMAGE_URL\nMug,2,https://example.com/mug.png\n",
+			expected: []dto.CreateProductDTO{{Name: "Mug", Price: 200, ImageURL: "https://example.com/mug.png"}},
+		},
+		{
+			name:     "User mapping",
+			csv:      "Artikel,Kosten,Notiz\nTasse,3.25,ignored\n",
+			options:  dto.ImportOptions{ColumnMapping: map[string]string{"artikel": dto.ImportFieldName, "KOSTEN": dto.ImportFieldPrice}},
+			expected: []dto.CreateProductDTO{{Name: "Tasse", Price: 325}},
+		},
+		{
+			name:     "User mapping overrides and ignores aliases",
+			csv:      "Name,Price,Valor\nCup,1.00,9.99\n",
+			options:  dto.ImportOptions{ColumnMapping: map[string]string{"Price": "", "Valor": dto.ImportFieldPrice}},
+			expected: []dto.CreateProductDTO{{Name: "Cup", Price: 999}},
+		},
+		{
+			name:     "Short rows and blank rows",
+			csv:      "Name,Price,Category,Stock\nCup,1\n,,,\n",
+			expected: []dto.CreateProductDTO{{Name: "Cup", Price: 100}},
+		},
+		{
+			name:        "Missing required column",
+			csv:         "Name,Category\nCup,Kitchen\n",
+			errorFields: []string{dto.ImportFieldPrice},
+		},
+		{
+			name:        "Two columns for one field",
+			csv:         "Name,Nome,Price\nCup,Caneca,1\n",
+			errorFields: []string{dto.ImportFieldName},
+		},
+		{
+			name:        "Invalid cells",
+			csv:         "Name,Price,Stock\nCup,cheap,many\n",
+			errorFields: []string{dto.ImportFieldPrice, dto.ImportFieldStock},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			store := repositories.NewMemoryProductStore()
+			importer := service.NewImportExportService(slog.Default(), nil, store)
+
+			result, err := importer.ImportFromCSV(ctx, writeTempFile(t, "products.csv", []byte(tt.csv)), tt.options)
+			if err != nil {
+				t.Fatalf("Import failed: %v", err)
+			}
+
+			if len(result.Errors) != len(tt.errorFields) {
+				t.Fatalf("Expected errors on %v, got %+v", tt.errorFields, result.Errors)
+			}
+			for i, field := range tt.errorFields {
+				if result.Errors[i].Field != field {
+					t.Errorf("Expected error %d on %s, got %+v", i, field, result.Errors[i])
+				}
+			}
+
+			if len(result.ImportedItems) != len(tt.expected) {
+				t.Fatalf("Expected %d products, got %d", len(tt.expected), len(result.ImportedItems))
+			}
+			for i, want := range tt.expected {
+				got := result.ImportedItems[i]
+				if got.Name != want.Name || got.Price != want.Price || got.Stock != want.Stock ||
+					value(got.Category) != want.Category || value(got.Description) != want.Description || value(got.ImageURL) != want.ImageURL {
+					t.Errorf("Expected %+v, got %+v", want, got)
+				}
+			}
+		})
+	}
+}
+
+func TestImportRejectsUnknownMappedField(t *testing.T) {
+	importer := service.NewImportExportService(slog.Default(), nil, repositories.NewMemoryProductStore())
+	options := dto.ImportOptions{ColumnMapping: map[string]string{"Kosten": "cost"}}
+
+	_, err := importer.ImportFromCSV(context.Background(), writeTempFile(t, "products.csv", []byte("Name,Kosten\nCup,1\n")), options)
+	if apperrors.CodeOf(err) != apperrors.CodeValidation {
+		t.Errorf("Expected a validation error, got %v", err)
+	}
+}
+
+func TestExportRoundTrip(t *testing.T) {
+	ctx := context.Background()
+	source := repositories.NewMemoryProductStore()
+	for _, product := range []dto.CreateProductDTO{
+		{Name: "Lamp", Price: money.FromMinorUnits(1990), Category: "Home", Stock: 3, Description: "Desk lamp, brass", ImageURL: "https://example.com/lamp.png"},
+		{Name: "Mug", Price: money.FromMinorUnits(5), Stock: 0},
+	} {
+		if _, err := source.Create(ctx, product); err != nil {
+			t.Fatalf("Failed to create product: %v", err)
+		}
+	}
+	exporter := service.NewImportExportService(slog.Default(), nil, source)
+	request := dto.ExportRequest{IncludeAll: true}
+
+	csvData, err := exporter.ExportToCSV(ctx, request)
+	if err != nil {
+		t.Fatalf("CSV export failed: %v", err)
+	}
+	xlsxData, err := exporter.ExportToXLSX(ctx, request)
+	if err != nil {
+		t.Fatalf("XLSX export failed: %v", err)
+	}
+	csvPath := writeTempFile(t, "products.csv", csvData)
+	xlsxPath := writeTempFile(t, "products.xlsx", xlsxData)
+
+	formats := []struct {
+		name string
+		read func(*service.ImportExportService) (*dto.ImportResult, error)
+	}{
+		{name: "CSV", read: func(s *service.ImportExportService) (*dto.ImportResult, error) {
+			return s.ImportFromCSV(ctx, csvPath, dto.ImportOptions{})
+		}},
+		{name: "XLSX", read: func(s *service.ImportExportService) (*dto.ImportResult, error) {
+			return s.ImportFromXLSX(ctx, xlsxPath, dto.ImportOptions{})
+		}},
+	}
+
+	for _, format := range formats {
+		t.Run(format.name, func(t *testing.T) {
+			target := repositories.NewMemoryProductStore()
+			result, err := format.read(service.NewImportExportService(slog.Default(), nil, target))
+			if err != nil {
+				t.Fatalf("Import failed: %v", err)
+			}
+			if result.ErrorCount != 0 || result.SuccessCount != 2 {
+				t.Fatalf("Expected 2 products without errors, got %+v", result)
+			}
+
+			lamp, mug := result.ImportedItems[0], result.ImportedItems[1]
+			if lamp.Name != "Lamp" || lamp.Price != 1990 || lamp.Stock != 3 || value(lamp.Category) != "Home" ||
+				value(lamp.Description) != "Desk lamp, brass" || value(lamp.ImageURL) != "https://example.com/lamp.png" {
+				t.Errorf("Lamp did not round-trip: %+v", lamp)
+			}
+			if mug.Name != "Mug" || mug.Price != 5 || value(mug.Category) != "" {
+				t.Errorf("Mug did not round-trip: %+v", mug)
+			}
+		})
+	}
+}
+
+func value(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
@@ -5,6 +5,7 @@ import (
 	"testing"
 
 	"product-management-app/core/dto"
+	"product-management-app/core/money"
 )
 
 func TestImportExportBasicFunctionality(t *testing.T) {
@@ -20,7 +21,7 @@ func TestImportExportBasicFunctionality(t *testing.T) {
 		t.Error("Template should contain CSV headers")
 	}
 
-	dto := dto.NewProductImportDTO("Test Product", 29.99, "Category", 10, "Description", "")
+	dto := dto.NewProductImportDTO("Test Product", money.FromMinorUnits(2999), "Category", 10, "Description", "")
 
 	createDTO := dto.ToCreateProductDTO()
 	if createDTO.Name != "Test Product" {
@@ -0,0 +1,215 @@
+package test
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+
+	"product-management-app/core/dto"
+	apperrors "product-management-app/core/errors"
+	"product-management-app/core/models"
+	"product-management-app/core/money"
+	"product-management-app/core/repositories"
+	service "product-management-app/core/services"
+)
+
+// seedImportStore creates Lamp (ID 1, SKU LAMP-1), Mug (ID 2, no price or
+// stock) and two products named Plate.
+func seedImportStore(t *testing.T) *repositories.MemoryProductStore {
+	t.Helper()
+	store := repositories.NewMemoryProductStore()
+	for _, product := range []dto.CreateProductDTO{
+		{Name: "Lamp", SKU: "LAMP-1", Price: money.FromMinorUnits(1990), Category: "Home", Stock: 3, Description: "Brass"},
+		{Name: "Mug"},
+		{Name: "Plate", Price: money.FromUnits(2)},
+		{Name: "Plate", Price: money.FromUnits(3)},
+	} {
+		if _, err := store.Create(context.Background(), product); err != nil {
+			t.Fatalf("Failed to create product: %v", err)
+		}
+	}
+	return store
+}
+
+func TestImportModes(t *testing.T) {
+	tests := []struct {
+		name                              string
+		csv                               string
+		options                           dto.ImportOptions
+		created, updated, skipped, failed int
+		// expect checks the products with these IDs after the import.
+		expect map[int]func(*models.Product) bool
+	}{
+		{
+			name:    "Insert-only skips matched rows",
+			csv:     "SKU,Name,Price\nLAMP-1,Lamp,25\nCUP-1,Cup,3\n",
+			created: 1, skipped: 1,
+			expect: map[int]func(*models.Product) bool{
+				1: func(p *models.Product) bool { return p.Price == 1990 },
+				5: func(p *models.Product) bool { return p.Name == "Cup" && value(p.SKU) == "CUP-1" },
+			},
+		},
+		{
+			name:    "Insert-only without a key column creates every row",
+			csv:     "Name,Price\nLamp,25\n",
+			created: 1,
+		},
+		{
+			name:    "Update-only skips unmatched rows",
+			csv:     "SKU,Name,Price\nLAMP-1,Lamp,25\nCUP-1,Cup,3\n",
+			options: dto.ImportOptions{Mode: dto.ImportUpdateOnly},
+			updated: 1, skipped: 1,
+			expect: map[int]func(*models.Product) bool{
+				1: func(p *models.Product) bool { return p.Price == 2500 && p.Version == 2 },
+			},
+		},
+		{
+			name:    "Upsert creates and updates",
+			csv:     "SKU,Name,Price\nLAMP-1,Lamp,25\nCUP-1,Cup,3\n",
+			options: dto.ImportOptions{Mode: dto.ImportUpsert},
+			created: 1, updated: 1,
+		},
+		{
+			name:    "Update-only by ID with only the changed column",
+			csv:     "ID,Stock\n1,10\n999,1\n",
+			options: dto.ImportOptions{Mode: dto.ImportUpdateOnly, MatchBy: dto.MatchByID},
+			updated: 1, skipped: 1,
+			expect: map[int]func(*models.Product) bool{
+				1: func(p *models.Product) bool { return p.Stock == 10 && p.Price == 1990 },
+			},
+		},
+		{
+			name:    "Update-only by exact name",
+			csv:     "Name,Category,Stock\nMug,Kitchen,7\nmug,Other,1\n",
+			options: dto.ImportOptions{Mode: dto.ImportUpdateOnly, MatchBy: dto.MatchByName},
+			updated: 1, skipped: 1,
+			expect: map[int]func(*models.Product) bool{
+				2: func(p *models.Product) bool { return value(p.Category) == "Kitchen" && p.Stock == 7 },
+			},
+		},
+		{
+			name:    "Name shared by several products fails the row",
+			csv:     "Name,Price\nPlate,4\n",
+			options: dto.ImportOptions{Mode: dto.ImportUpsert, MatchBy: dto.MatchByName},
+			failed:  1,
+		},
+		{
+			name: "Field rules keep, fill and overwrite",
+			csv:  "SKU,Price,Description,Category,Stock\nLAMP-1,30,New text,Office,9\n",
+			options: dto.ImportOptions{
+				Mode:  dto.ImportUpdateOnly,
+				Merge: map[string]dto.MergeRule{dto.ImportFieldPrice: dto.MergeKeepExisting, dto.ImportFieldDescription: dto.MergeFillBlanks},
+			},
+			updated: 1,
+			expect: map[int]func(*models.Product) bool{
+				1: func(p *models.Product) bool {
+					return p.Price == 1990 && value(p.Description) == "Brass" && value(p.Category) == "Office" && p.Stock == 9
+				},
+			},
+		},
+		{
+			name:    "Fill blanks by default",
+			csv:     "ID,Price,Stock,Category\n1,30,9,Office\n2,4.50,6,Kitchen\n",
+			options: dto.ImportOptions{Mode: dto.ImportUpdateOnly, MatchBy: dto.MatchByID, DefaultMerge: dto.MergeFillBlanks},
+			updated: 1, skipped: 1,
+			expect: map[int]func(*models.Product) bool{
+				1: func(p *models.Product) bool { return p.Price == 1990 && p.Stock == 3 && value(p.Category) == "Home" },
+				2: func(p *models.Product) bool { return p.Price == 450 && p.Stock == 6 && value(p.Category) == "Kitchen" },
+			},
+		},
+		{
+			name:    "Blank cells and equal values change nothing",
+			csv:     "SKU,Name,Price,Category,Description\nLAMP-1,Lamp,19.90,,\n",
+			options: dto.ImportOptions{Mode: dto.ImportUpsert},
+			skipped: 1,
+			expect: map[int]func(*models.Product) bool{
+				1: func(p *models.Product) bool { return p.Version == 1 && value(p.Category) == "Home" },
+			},
+		},
+		{
+			name:    "Update-only needs the key column",
+			csv:     "Name,Price\nLamp,25\n",
+			options: dto.ImportOptions{Mode: dto.ImportUpdateOnly},
+			failed:  1,
+		},
+		{
+			name:    "Creating needs a price",
+			csv:     "SKU,Name,Price\nCUP-1,Cup,\n",
+			options: dto.ImportOptions{Mode: dto.ImportUpsert},
+			failed:  1,
+		},
+		{
+			name:    "Taken SKU fails the row",
+			csv:     "ID,SKU\n2,LAMP-1\n",
+			options: dto.ImportOptions{Mode: dto.ImportUpdateOnly, MatchBy: dto.MatchByID},
+			failed:  1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			store := seedImportStore(t)
+			importer := service.NewImportExportService(slog.Default(), nil, store)
+
+			result, err := importer.ImportFromCSV(ctx, writeTempFile(t, "products.csv", []byte(tt.csv)), tt.options)
+			if err != nil {
+				t.Fatalf("Import failed: %v", err)
+			}
+			if result.CreatedCount != tt.created || result.UpdatedCount != tt.updated ||
+				result.SkippedCount != tt.skipped || result.ErrorCount != tt.failed {
+				t.Fatalf("Expected %d created, %d updated, %d skipped and %d failed, got %+v",
+					tt.created, tt.updated, tt.skipped, tt.failed, result)
+			}
+			if result.SuccessCount != tt.created+tt.updated || len(result.ImportedItems) != result.SuccessCount {
+				t.Errorf("Expected %d imported items, got %d", tt.created+tt.updated, len(result.ImportedItems))
+			}
+			if len(result.Skipped) != tt.skipped {
+				t.Errorf("Expected a reason for every skipped row, got %+v", result.Skipped)
+			}
+
+			for id, check := range tt.expect {
+				product, err := store.GetByID(ctx, id)
+				if err != nil {
+					t.Fatalf("Failed to fetch product %d: %v", id, err)
+				}
+				if !check(product) {
+					t.Errorf("Unexpected product %d: %+v", id, product)
+				}
+			}
+		})
+	}
+}
+
+func TestImportOptionsValidate(t *testing.T) {
+	tests := []struct {
+		name        string
+		options     dto.ImportOptions
+		expectError bool
+	}{
+		{name: "Defaults", options: dto.ImportOptions{}},
+		{name: "Every setting", options: dto.ImportOptions{
+			Mode:         dto.ImportUpsert,
+			MatchBy:      dto.MatchByName,
+			DefaultMerge: dto.MergeKeepExisting,
+			Merge:        map[string]dto.MergeRule{dto.ImportFieldStock: dto.MergeOverwrite},
+		}},
+		{name: "Unknown mode", options: dto.ImportOptions{Mode: "replace"}, expectError: true},
+		{name: "Unknown match key", options: dto.ImportOptions{MatchBy: "category"}, expectError: true},
+		{name: "Unknown default rule", options: dto.ImportOptions{DefaultMerge: "append"}, expectError: true},
+		{name: "Rule for an unknown field", options: dto.ImportOptions{Merge: map[string]dto.MergeRule{"cost": dto.MergeOverwrite}}, expectError: true},
+		{name: "Rule for the ID", options: dto.ImportOptions{Merge: map[string]dto.MergeRule{dto.ImportFieldID: dto.MergeOverwrite}}, expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.options.Validate()
+			if tt.expectError && apperrors.CodeOf(err) != apperrors.CodeValidation {
+				t.Errorf("Expected a validation error, got %v", err)
+			}
+			if !tt.expectError && err != nil {
+				t.Errorf("Unexpected error: %v", err)
+			}
+		})
+	}
+}
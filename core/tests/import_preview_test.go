@@ -0,0 +1,195 @@
+package test
+
+import (
+	"context"
+	"log/slog"
+	"slices"
+	"testing"
+
+	"product-management-app/core/dto"
+	apperrors "product-management-app/core/errors"
+	"product-management-app/core/money"
+	"product-management-app/core/repositories"
+	service "product-management-app/core/services"
+)
+
+// previewCSV has a new row, a changed row, an unchanged row, an invalid row
+// and a row updating the product created two rows above.
+const previewCSV = "SKU,Name,Price,Stock\n" +
+	"CUP-1,Cup,3,1\n" +
+	"LAMP-1,Lamp,25,3\n" +
+	"LAMP-1,Lamp,25,3\n" +
+	"BAD-1,Bad,cheap,1\n" +
+	"CUP-1,Cup,3,8\n"
+
+func TestImportPreview(t *testing.T) {
+	ctx := context.Background()
+	store := seedImportStore(t)
+	importer := service.NewImportExportService(slog.Default(), nil, store)
+
+	result, err := importer.ImportFromCSV(ctx, writeTempFile(t, "products.csv", []byte(previewCSV)), dto.ImportOptions{Mode: dto.ImportUpsert, DryRun: true})
+	if err != nil {
+		t.Fatalf("Preview failed: %v", err)
+	}
+	if result.Preview == nil || result.Preview.ID == "" {
+		t.Fatalf("Expected a preview, got %+v", result)
+	}
+	if len(result.ImportedItems) != 0 {
+		t.Errorf("Expected no imported items in a preview, got %d", len(result.ImportedItems))
+	}
+
+	expected := []struct {
+		row     int
+		status  dto.ImportRowStatus
+		changes []dto.ImportFieldChange
+	}{
+		{row: 2, status: dto.ImportRowNew},
+		{row: 3, status: dto.ImportRowChanged, changes: []dto.ImportFieldChange{{Field: dto.ImportFieldPrice, Before: "19.90", After: "25.00"}}},
+		{row: 4, status: dto.ImportRowUnchanged},
+		{row: 5, status: dto.ImportRowInvalid},
+		{row: 6, status: dto.ImportRowChanged, changes: []dto.ImportFieldChange{{Field: dto.ImportFieldStock, Before: "1", After: "8"}}},
+	}
+	rows := result.Preview.Rows
+	if len(rows) != len(expected) {
+		t.Fatalf("Expected %d preview rows, got %+v", len(expected), rows)
+	}
+	for i, want := range expected {
+		got := rows[i]
+		if got.Row != want.row || got.Status != want.status {
+			t.Errorf("Expected row %d to be %s, got %+v", want.row, want.status, got)
+		}
+		if want.changes != nil && !slices.Equal(got.Changes, want.changes) {
+			t.Errorf("Expected row %d changes %+v, got %+v", want.row, want.changes, got.Changes)
+		}
+	}
+	if rows[0].Changes[0] != (dto.ImportFieldChange{Field: dto.ImportFieldName, After: "Cup"}) {
+		t.Errorf("Expected the new row to list its fields, got %+v", rows[0].Changes)
+	}
+	if len(rows[3].Errors) == 0 {
+		t.Errorf("Expected the invalid row to carry its errors, got %+v", rows[3])
+	}
+
+	// Nothing is written until the preview is committed.
+	if _, err := store.GetBySKU(ctx, "CUP-1"); apperrors.CodeOf(err) != apperrors.CodeNotFound {
+		t.Fatalf("Expected the preview to write nothing, got %v", err)
+	}
+	lamp, _ := store.GetByID(ctx, 1)
+	if lamp.Price != 1990 || lamp.Version != 1 {
+		t.Fatalf("Expected the preview to leave Lamp alone, got %+v", lamp)
+	}
+
+	// A product created meanwhile gives the committed Cup another ID than
+	// the previewed one.
+	if _, err := store.Create(ctx, dto.CreateProductDTO{Name: "Bowl", Price: money.FromUnits(4)}); err != nil {
+		t.Fatalf("Failed to create product: %v", err)
+	}
+
+	committed, err := importer.CommitImport(ctx, result.Preview.ID)
+	if err != nil {
+		t.Fatalf("Commit failed: %v", err)
+	}
+	if committed.CreatedCount != 1 || committed.UpdatedCount != 2 || committed.SkippedCount != 1 || committed.ErrorCount != 1 {
+		t.Fatalf("Expected 1 created, 2 updated, 1 skipped and 1 failed, got %+v", committed)
+	}
+	cup, err := store.GetBySKU(ctx, "CUP-1")
+	if err != nil {
+		t.Fatalf("Failed to fetch the committed product: %v", err)
+	}
+	if cup.Stock != 8 || cup.Version != 2 {
+		t.Errorf("Expected the later row to update the committed Cup, got %+v", cup)
+	}
+	lamp, _ = store.GetByID(ctx, 1)
+	if lamp.Price != 2500 {
+		t.Errorf("Expected Lamp to be updated, got %+v", lamp)
+	}
+
+	if _, err := importer.CommitImport(ctx, result.Preview.ID); apperrors.CodeOf(err) != apperrors.CodeNotFound {
+		t.Errorf("Expected a second commit to find no preview, got %v", err)
+	}
+}
+
+func TestCommitImportChangedSincePreview(t *testing.T) {
+	ctx := context.Background()
+	store := seedImportStore(t)
+	importer := service.NewImportExportService(slog.Default(), nil, store)
+
+	result, err := importer.ImportFromCSV(ctx, writeTempFile(t, "products.csv", []byte("SKU,Stock\nLAMP-1,10\n")), dto.ImportOptions{Mode: dto.ImportUpdateOnly, DryRun: true})
+	if err != nil {
+		t.Fatalf("Preview failed: %v", err)
+	}
+	stock := 5
+	if _, err := store.Update(ctx, 1, dto.UpdateProductDTO{Version: 1, Stock: &stock}); err != nil {
+		t.Fatalf("Failed to update product: %v", err)
+	}
+
+	committed, err := importer.CommitImport(ctx, result.Preview.ID)
+	if err != nil {
+		t.Fatalf("Commit failed: %v", err)
+	}
+	if committed.UpdatedCount != 0 || committed.ErrorCount != 1 {
+		t.Fatalf("Expected the stale row to fail, got %+v", committed)
+	}
+	lamp, _ := store.GetByID(ctx, 1)
+	if lamp.Stock != 5 {
+		t.Errorf("Expected the newer stock to be kept, got %d", lamp.Stock)
+	}
+}
+
+func TestDiscardImportPreview(t *testing.T) {
+	ctx := context.Background()
+	importer := service.NewImportExportService(slog.Default(), nil, repositories.NewMemoryProductStore())
+
+	result, err := importer.ImportFromCSV(ctx, writeTempFile(t, "products.csv", []byte("Name,Price\nCup,3\n")), dto.ImportOptions{DryRun: true})
+	if err != nil {
+		t.Fatalf("Preview failed: %v", err)
+	}
+	importer.DiscardPreview(result.Preview.ID)
+
+	if _, err := importer.CommitImport(ctx, result.Preview.ID); apperrors.CodeOf(err) != apperrors.CodeNotFound {
+		t.Errorf("Expected a discarded preview to be gone, got %v", err)
+	}
+}
+
+// txCountingStore counts the transactions started on a store.
+type txCountingStore struct {
+	repositories.ProductStore
+	txs int
+}
+
+func (s *txCountingStore) WithTx(ctx context.Context, fn func(tx repositories.ProductStore) error) error {
+	s.txs++
+	return s.ProductStore.WithTx(ctx, fn)
+}
+
+func TestLargeImportPreview(t *testing.T) {
+	ctx := context.Background()
+	store := &txCountingStore{ProductStore: repositories.NewMemoryProductStore()}
+	importer := service.NewImportExportService(slog.Default(), nil, store)
+
+	result, err := importer.ImportFromCSV(ctx, writeTempFile(t, "products.csv", largeImportCSV(1200)), dto.ImportOptions{DryRun: true})
+	if err != nil {
+		t.Fatalf("Preview failed: %v", err)
+	}
+	// One rolled back transaction per chunk of 500 rows.
+	if store.txs != 3 {
+		t.Errorf("Expected the preview to run 3 transactions, got %d", store.txs)
+	}
+	preview := result.Preview
+	if len(preview.Rows) != 1000 || preview.TotalRows != 1200 {
+		t.Errorf("Expected the first 1000 of 1200 rows, got %d of %d", len(preview.Rows), preview.TotalRows)
+	}
+	if preview.Counts[dto.ImportRowNew] != 1200 || result.CreatedCount != 1200 {
+		t.Errorf("Expected every row to be counted as new, got %+v and %d created", preview.Counts, result.CreatedCount)
+	}
+	if count := countProducts(t, store); count != 0 {
+		t.Fatalf("Expected the preview to write nothing, got %d products", count)
+	}
+
+	committed, err := importer.CommitImport(ctx, preview.ID)
+	if err != nil {
+		t.Fatalf("Commit failed: %v", err)
+	}
+	if committed.CreatedCount != 1200 || countProducts(t, store) != 1200 {
+		t.Errorf("Expected the whole plan to be committed, got %d created", committed.CreatedCount)
+	}
+}
@@ -0,0 +1,231 @@
+package test
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"fmt"
+	"log/slog"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"product-management-app/core/dto"
+	apperrors "product-management-app/core/errors"
+	"product-management-app/core/repositories"
+	service "product-management-app/core/services"
+)
+
+// largeImportCSV returns a CSV file with rows distinct products.
+func largeImportCSV(rows int) []byte {
+	var b strings.Builder
+	b.WriteString("SKU,Name,Price,Stock\n")
+	for i := 1; i <= rows; i++ {
+		fmt.Fprintf(&b, "SKU-%d,Product %d,%d.50,%d\n", i, i, i%100, i%7)
+	}
+	return []byte(b.String())
+}
+
+// progressEvents returns an emitter collecting the import progress events
+// into events.
+func progressEvents(events *[]dto.ImportProgressDTO) service.EventEmitter {
+	return func(name string, data interface{}) {
+		if name == service.ImportProgressEvent {
+			*events = append(*events, data.(dto.ImportProgressDTO))
+		}
+	}
+}
+
+func TestStreamedImport(t *testing.T) {
+	ctx := context.Background()
+	source := repositories.NewMemoryProductStore()
+	csvPath := writeTempFile(t, "products.csv", largeImportCSV(1200))
+	if _, err := service.NewImportExportService(slog.Default(), nil, source).ImportFromCSV(ctx, csvPath, dto.ImportOptions{}); err != nil {
+		t.Fatalf("Failed to seed products: %v", err)
+	}
+	xlsxData, err := service.NewImportExportService(slog.Default(), nil, source).ExportToXLSX(ctx, dto.ExportRequest{IncludeAll: true})
+	if err != nil {
+		t.Fatalf("XLSX export failed: %v", err)
+	}
+	xlsxPath := writeTempFile(t, "products.xlsx", xlsxData)
+
+	tests := []struct {
+		name string
+		read func(*service.ImportExportService) (*dto.ImportResult, error)
+	}{
+		{name: "CSV", read: func(s *service.ImportExportService) (*dto.ImportResult, error) {
+			return s.ImportFromCSV(ctx, csvPath, dto.ImportOptions{})
+		}},
+		{name: "XLSX", read: func(s *service.ImportExportService) (*dto.ImportResult, error) {
+			return s.ImportFromXLSX(ctx, xlsxPath, dto.ImportOptions{})
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := repositories.NewMemoryProductStore()
+			var events []dto.ImportProgressDTO
+			importer := service.NewImportExportService(slog.Default(), progressEvents(&events), store)
+
+			result, err := tt.read(importer)
+			if err != nil {
+				t.Fatalf("Import failed: %v", err)
+			}
+			if result.CreatedCount != 1200 || result.ErrorCount != 0 {
+				t.Fatalf("Expected 1200 created products, got %d created and %d errors", result.CreatedCount, result.ErrorCount)
+			}
+			if len(result.ImportedItems) != 1000 {
+				t.Errorf("Expected the imported items to be capped at 1000, got %d", len(result.ImportedItems))
+			}
+			if count := countProducts(t, store); count != 1200 {
+				t.Errorf("Expected 1200 products, got %d", count)
+			}
+			batch, err := store.GetImportBatch(ctx, result.BatchID)
+			if err != nil {
+				t.Fatalf("GetImportBatch failed: %v", err)
+			}
+			if batch.CreatedCount != 1200 || len(batch.Items) != 1200 {
+				t.Errorf("Expected one batch holding every chunk, got %d created and %d items", batch.CreatedCount, len(batch.Items))
+			}
+
+			// A start event, one per chunk of 500 rows and a final one.
+			if len(events) != 5 {
+				t.Fatalf("Expected 5 progress events, got %+v", events)
+			}
+			if events[0].Status != dto.JobRunning || events[0].RowsProcessed != 0 {
+				t.Errorf("Expected a start event, got %+v", events[0])
+			}
+			for i, rows := range []int{500, 1000, 1200} {
+				if event := events[i+1]; event.RowsProcessed != rows || event.SuccessCount != rows || event.ID != events[0].ID {
+					t.Errorf("Expected chunk %d to report %d rows, got %+v", i+1, rows, event)
+				}
+			}
+			if last := events[4]; last.Status != dto.JobCompleted || last.Progress != 1 || last.ETASeconds != 0 {
+				t.Errorf("Expected a completed event, got %+v", last)
+			}
+		})
+	}
+}
+
+func TestCancelImport(t *testing.T) {
+	tests := []struct {
+		name        string
+		atomic      bool
+		kept        int
+		expectBatch bool
+	}{
+		{name: "Committed chunks are kept", kept: 500, expectBatch: true},
+		{name: "Atomic import writes nothing", atomic: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			store := repositories.NewMemoryProductStore()
+			var importer *service.ImportExportService
+			var events []dto.ImportProgressDTO
+			collect := progressEvents(&events)
+			importer = service.NewImportExportService(slog.Default(), func(name string, data interface{}) {
+				collect(name, data)
+				// Cancel once the first chunk is reported.
+				if event := data.(dto.ImportProgressDTO); event.Status == dto.JobRunning && event.RowsProcessed == 500 {
+					if err := importer.CancelImport(event.ID); err != nil {
+						t.Errorf("CancelImport failed: %v", err)
+					}
+				}
+			}, store)
+
+			_, err := importer.ImportFromCSV(ctx, writeTempFile(t, "products.csv", largeImportCSV(1200)), dto.ImportOptions{Atomic: tt.atomic})
+			if apperrors.CodeOf(err) != apperrors.CodeCancelled {
+				t.Fatalf("Expected a cancellation error, got %v", err)
+			}
+			if count := countProducts(t, store); count != tt.kept {
+				t.Errorf("Expected %d products to be kept, got %d", tt.kept, count)
+			}
+			batches, err := importer.ListImportBatches(ctx, 10)
+			if err != nil {
+				t.Fatalf("ListImportBatches failed: %v", err)
+			}
+			if (len(batches) == 1) != tt.expectBatch || (tt.expectBatch && batches[0].CreatedCount != tt.kept) {
+				t.Errorf("Expected a batch: %v, got %+v", tt.expectBatch, batches)
+			}
+			if last := events[len(events)-1]; last.Status != dto.JobCancelled {
+				t.Errorf("Expected a cancelled event, got %+v", last)
+			}
+
+			if err := importer.CancelImport(events[0].ID); apperrors.CodeOf(err) != apperrors.CodeNotFound {
+				t.Errorf("Expected a finished import not to be found, got %v", err)
+			}
+		})
+	}
+}
+
+func TestExportAllProductsPastOnePage(t *testing.T) {
+	ctx := context.Background()
+	store := repositories.NewMemoryProductStore()
+	exporter := service.NewImportExportService(slog.Default(), nil, store)
+	total := dto.MaxPageSize + 1
+	if _, err := exporter.ImportFromCSV(ctx, writeTempFile(t, "products.csv", largeImportCSV(total)), dto.ImportOptions{}); err != nil {
+		t.Fatalf("Failed to seed products: %v", err)
+	}
+
+	data, err := exporter.ExportToCSV(ctx, dto.ExportRequest{IncludeAll: true})
+	if err != nil {
+		t.Fatalf("CSV export failed: %v", err)
+	}
+	records, err := csv.NewReader(bytes.NewReader(data)).ReadAll()
+	if err != nil {
+		t.Fatalf("Failed to read export: %v", err)
+	}
+	if len(records) != total+1 {
+		t.Fatalf("Expected a header and %d products, got %d rows", total, len(records))
+	}
+	if last := records[len(records)-1]; last[1] != fmt.Sprintf("SKU-%d", total) {
+		t.Errorf("Expected the last product to be SKU-%d, got %v", total, last)
+	}
+}
+
+func TestImportFromFilePath(t *testing.T) {
+	missing := filepath.Join(t.TempDir(), "missing.csv")
+
+	tests := []struct {
+		name        string
+		read        func(*service.ImportExportService, string) (*dto.ImportResult, error)
+		path        string
+		expectError bool
+		errorCount  int
+	}{
+		{name: "Missing CSV file", read: importCSV, path: missing, expectError: true},
+		{name: "Missing XLSX file", read: importXLSX, path: missing, expectError: true},
+		{name: "Empty CSV file", read: importCSV, path: writeTempFile(t, "empty.csv", nil), errorCount: 1},
+		{name: "Empty XLSX file", read: importXLSX, path: writeTempFile(t, "empty.xlsx", nil), errorCount: 1},
+		{name: "Invalid XLSX file", read: importXLSX, path: writeTempFile(t, "invalid.xlsx", []byte("Name,Price\n")), errorCount: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := repositories.NewMemoryProductStore()
+			result, err := tt.read(service.NewImportExportService(slog.Default(), nil, store), tt.path)
+			if tt.expectError {
+				if err == nil {
+					t.Fatal("Expected error, got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if result.ErrorCount != tt.errorCount || result.SuccessCount != 0 {
+				t.Errorf("Expected %d errors and no products, got %+v", tt.errorCount, result)
+			}
+		})
+	}
+}
+
+func importCSV(s *service.ImportExportService, path string) (*dto.ImportResult, error) {
+	return s.ImportFromCSV(context.Background(), path, dto.ImportOptions{})
+}
+
+func importXLSX(s *service.ImportExportService, path string) (*dto.ImportResult, error) {
+	return s.ImportFromXLSX(context.Background(), path, dto.ImportOptions{})
+}
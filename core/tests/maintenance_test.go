@@ -0,0 +1,190 @@
+package test
+
+import (
+	"context"
+	"log/slog"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"product-management-app/core/config"
+	"product-management-app/core/dto"
+	apperrors "product-management-app/core/errors"
+	service "product-management-app/core/services"
+)
+
+func TestMaintenanceTaskValidate(t *testing.T) {
+	tests := []struct {
+		name        string
+		task        dto.MaintenanceTask
+		expectError bool
+	}{
+		{name: "Integrity check", task: dto.MaintenanceIntegrityCheck},
+		{name: "Quick check", task: dto.MaintenanceQuickCheck},
+		{name: "Vacuum", task: dto.MaintenanceVacuum},
+		{name: "Analyze", task: dto.MaintenanceAnalyze},
+		{name: "Stats", task: dto.MaintenanceStats},
+		{name: "Empty task", task: "", expectError: true},
+		{name: "Unknown task", task: "reindex", expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.task.Validate()
+			if tt.expectError && err == nil {
+				t.Errorf("Expected error but got none")
+			}
+			if !tt.expectError && err != nil {
+				t.Errorf("Unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+// awaitMaintenanceJob polls a job until it finishes and returns its final
+// state.
+func awaitMaintenanceJob(t *testing.T, job func(id string) (*dto.MaintenanceJobDTO, error), id string) *dto.MaintenanceJobDTO {
+	t.Helper()
+	deadline := time.Now().Add(10 * time.Second)
+	for {
+		state, err := job(id)
+		if err != nil {
+			t.Fatalf("Failed to read maintenance job %s: %v", id, err)
+		}
+		if state.Status != dto.JobRunning {
+			return state
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("Maintenance job did not finish, got %+v", state)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestMaintenanceJobs(t *testing.T) {
+	tests := []struct {
+		name  string
+		task  dto.MaintenanceTask
+		check func(t *testing.T, result interface{})
+	}{
+		{name: "Integrity check", task: dto.MaintenanceIntegrityCheck, check: func(t *testing.T, result interface{}) {
+			check, ok := result.(*dto.IntegrityCheckResult)
+			if !ok || !check.OK || len(check.Problems) != 0 {
+				t.Errorf("Expected a clean integrity check, got %+v", result)
+			}
+		}},
+		{name: "Quick check", task: dto.MaintenanceQuickCheck, check: func(t *testing.T, result interface{}) {
+			if check, ok := result.(*dto.IntegrityCheckResult); !ok || !check.OK {
+				t.Errorf("Expected a clean quick check, got %+v", result)
+			}
+		}},
+		{name: "Vacuum", task: dto.MaintenanceVacuum, check: func(t *testing.T, result interface{}) {
+			if result != nil {
+				t.Errorf("Expected no result, got %+v", result)
+			}
+		}},
+		{name: "Analyze", task: dto.MaintenanceAnalyze, check: func(t *testing.T, result interface{}) {
+			if result != nil {
+				t.Errorf("Expected no result, got %+v", result)
+			}
+		}},
+		{name: "Stats", task: dto.MaintenanceStats, check: func(t *testing.T, result interface{}) {
+			stats, ok := result.(*dto.DatabaseStatsDTO)
+			if !ok || stats.PageCount == 0 || stats.FileSize == 0 {
+				t.Fatalf("Expected database stats, got %+v", result)
+			}
+			for _, table := range stats.Tables {
+				if table.Name == "products" && table.Rows == 2 {
+					return
+				}
+			}
+			t.Errorf("Expected 2 rows in products, got %+v", stats.Tables)
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			products, _ := newTestProductService(t, nil)
+			createServiceProduct(t, products, "Keyboard")
+			createServiceProduct(t, products, "Mouse")
+
+			job, err := products.StartMaintenance(tt.task)
+			if err != nil {
+				t.Fatalf("StartMaintenance failed: %v", err)
+			}
+			if job.Status != dto.JobRunning || job.Task != tt.task {
+				t.Errorf("Expected a running %s job, got %+v", tt.task, job)
+			}
+
+			finished := awaitMaintenanceJob(t, products.MaintenanceJob, job.ID)
+			if finished.Status != dto.JobCompleted || finished.Progress != 1 || finished.FinishedAt == "" {
+				t.Errorf("Expected a completed job, got %+v", finished)
+			}
+			tt.check(t, finished.Result)
+
+			if jobs := products.MaintenanceJobs(); len(jobs) != 1 || jobs[0].ID != job.ID {
+				t.Errorf("Expected the job to be listed, got %+v", jobs)
+			}
+			if err := products.CancelMaintenance(job.ID); apperrors.CodeOf(err) != apperrors.CodeConflict {
+				t.Errorf("Expected cancelling a finished job to conflict, got %v", err)
+			}
+		})
+	}
+}
+
+func TestCancelMaintenanceJob(t *testing.T) {
+	cfg := config.DatabaseConfig{
+		Path:         filepath.Join(t.TempDir(), "products.db"),
+		PathSource:   config.SourceFlag,
+		MaxOpenConns: 1,
+	}
+	if err := cfg.ApplyDefaults(); err != nil {
+		t.Fatalf("ApplyDefaults failed: %v", err)
+	}
+	db := service.NewDatabaseService(slog.Default(), cfg)
+	if err := db.InitDatabase(); err != nil {
+		t.Fatalf("Failed to initialize database: %v", err)
+	}
+	t.Cleanup(db.CloseDatabase)
+	maintenance := service.NewMaintenanceService(slog.Default(), nil, db)
+
+	// Holding the only connection keeps the job waiting until it is
+	// cancelled.
+	conn, err := db.DB.Conn(context.Background())
+	if err != nil {
+		t.Fatalf("Failed to take a connection: %v", err)
+	}
+	job, err := maintenance.Start(dto.MaintenanceStats)
+	if err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+	if _, err := maintenance.Start(dto.MaintenanceAnalyze); err == nil {
+		t.Errorf("Expected a second job to be refused while one runs")
+	}
+	if err := maintenance.Cancel(job.ID); err != nil {
+		t.Fatalf("Cancel failed: %v", err)
+	}
+	cancelled := awaitMaintenanceJob(t, maintenance.Job, job.ID)
+	if cancelled.Status != dto.JobCancelled || cancelled.Result != nil {
+		t.Errorf("Expected a cancelled job, got %+v", cancelled)
+	}
+	if err := conn.Close(); err != nil {
+		t.Fatalf("Failed to release the connection: %v", err)
+	}
+
+	// The cancelled job no longer blocks new ones.
+	next, err := maintenance.Start(dto.MaintenanceIntegrityCheck)
+	if err != nil {
+		t.Fatalf("Start after cancelling failed: %v", err)
+	}
+	if next.ID == job.ID {
+		t.Errorf("Expected a new job, got %+v", next)
+	}
+	if finished := awaitMaintenanceJob(t, maintenance.Job, next.ID); finished.Status != dto.JobCompleted {
+		t.Errorf("Expected the next job to complete, got %+v", finished)
+	}
+
+	if err := maintenance.Cancel("missing"); apperrors.CodeOf(err) != apperrors.CodeNotFound {
+		t.Errorf("Expected an unknown job not to be found, got %v", err)
+	}
+}
@@ -0,0 +1,165 @@
+package test
+
+import (
+	"database/sql"
+	"path/filepath"
+	"testing"
+
+	"product-management-app/core/migrations"
+	"product-management-app/core/money"
+	"product-management-app/core/sqlite"
+)
+
+func openTestDatabase(t *testing.T) *sql.DB {
+	t.Helper()
+	db, err := sqlite.Open(filepath.Join(t.TempDir(), "test.db"), sqlite.Options{ForeignKeys: true})
+	if err != nil {
+		t.Fatalf("Failed to open database: %v", err)
+	}
+	t.Cleanup(func() {
+		if err := db.Close(); err != nil {
+			t.Errorf("Failed to close database: %v", err)
+		}
+	})
+	return db
+}
+
+func TestMigratorUpAndDown(t *testing.T) {
+	db := openTestDatabase(t)
+	migrator, err := migrations.NewMigrator(db)
+	if err != nil {
+		t.Fatalf("Failed to create migrator: %v", err)
+	}
+	if err := migrator.Prepare(); err != nil {
+		t.Fatalf("Prepare failed: %v", err)
+	}
+
+	applied, err := migrator.Up()
+	if err != nil {
+		t.Fatalf("Up failed: %v", err)
+	}
+	if len(applied) != migrator.LatestVersion() {
+		t.Errorf("Expected %d migrations applied, got %d", migrator.LatestVersion(), len(applied))
+	}
+
+	if _, err := db.Exec("INSERT INTO products(name, price_cents) VALUES('Test', 100)"); err != nil {
+		t.Fatalf("Failed to insert into migrated schema: %v", err)
+	}
+
+	if _, err := migrator.MigrateTo(0); err != nil {
+		t.Fatalf("MigrateTo(0) failed: %v", err)
+	}
+	version, err := migrator.CurrentVersion()
+	if err != nil || version != 0 {
+		t.Errorf("Expected version 0 after reverting, got %d (%v)", version, err)
+	}
+
+	if _, err := migrator.Up(); err != nil {
+		t.Fatalf("Up after revert failed: %v", err)
+	}
+	pending, err := migrator.Pending()
+	if err != nil || len(pending) != 0 {
+		t.Errorf("Expected no pending migrations, got %d (%v)", len(pending), err)
+	}
+}
+
+func TestMigratorAdoptsLegacySchema(t *testing.T) {
+	db := openTestDatabase(t)
+	legacySchema := `CREATE TABLE products (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		name TEXT NOT NULL,
+		price REAL NOT NULL,
+		category TEXT,
+		stock INTEGER DEFAULT 0,
+		description TEXT,
+		image_url TEXT,
+		created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+		updated_at TIMESTAMP
+	)`
+	if _, err := db.Exec(legacySchema); err != nil {
+		t.Fatalf("Failed to create legacy schema: %v", err)
+	}
+
+	migrator, err := migrations.NewMigrator(db)
+	if err != nil {
+		t.Fatalf("Failed to create migrator: %v", err)
+	}
+	if err := migrator.Prepare(); err != nil {
+		t.Fatalf("Prepare failed: %v", err)
+	}
+
+	version, err := migrator.CurrentVersion()
+	if err != nil || version != 1 {
+		t.Errorf("Expected legacy database to be adopted at version 1, got %d (%v)", version, err)
+	}
+	applied, err := migrator.Up()
+	if err != nil {
+		t.Fatalf("Up on legacy database failed: %v", err)
+	}
+	if len(applied) != migrator.LatestVersion()-1 {
+		t.Errorf("Expected every migration after the baseline to run, got %d", len(applied))
+	}
+	if _, err := db.Exec("UPDATE products SET version = version + 1, deleted_at = CURRENT_TIMESTAMP"); err != nil {
+		t.Errorf("Expected the migrated legacy schema to have the new columns: %v", err)
+	}
+}
+
+func TestMigratorDetectsChecksumMismatch(t *testing.T) {
+	db := openTestDatabase(t)
+	migrator, err := migrations.NewMigrator(db)
+	if err != nil {
+		t.Fatalf("Failed to create migrator: %v", err)
+	}
+	if err := migrator.Prepare(); err != nil {
+		t.Fatalf("Prepare failed: %v", err)
+	}
+	if _, err := migrator.Up(); err != nil {
+		t.Fatalf("Up failed: %v", err)
+	}
+
+	if _, err := db.Exec("UPDATE schema_migrations SET checksum = 'tampered' WHERE version = 1"); err != nil {
+		t.Fatalf("Failed to tamper checksum: %v", err)
+	}
+	if _, err := migrator.Pending(); err == nil {
+		t.Error("Expected checksum mismatch error but got none")
+	}
+}
+
+func TestMigratorConvertsPricesToCents(t *testing.T) {
+	db := openTestDatabase(t)
+	migrator, err := migrations.NewMigrator(db)
+	if err != nil {
+		t.Fatalf("Failed to create migrator: %v", err)
+	}
+	if err := migrator.Prepare(); err != nil {
+		t.Fatalf("Prepare failed: %v", err)
+	}
+	if _, err := migrator.MigrateTo(3); err != nil {
+		t.Fatalf("MigrateTo(3) failed: %v", err)
+	}
+	if _, err := db.Exec("INSERT INTO products(name, price) VALUES('Apple', 0.1), ('Pear', 19.99)"); err != nil {
+		t.Fatalf("Failed to insert products: %v", err)
+	}
+
+	if _, err := migrator.Up(); err != nil {
+		t.Fatalf("Up failed: %v", err)
+	}
+	var apple, pear money.Money
+	err = db.QueryRow(`SELECT
+		(SELECT price_cents FROM products WHERE name = 'Apple'),
+		(SELECT price_cents FROM products WHERE name = 'Pear')`).Scan(&apple, &pear)
+	if err != nil {
+		t.Fatalf("Failed to read prices: %v", err)
+	}
+	if apple != 10 || pear != 1999 {
+		t.Errorf("Unexpected prices after migration: %s, %s", apple, pear)
+	}
+
+	if _, err := migrator.MigrateTo(3); err != nil {
+		t.Fatalf("MigrateTo(3) failed: %v", err)
+	}
+	var price float64
+	if err := db.QueryRow("SELECT price FROM products WHERE name = 'Pear'").Scan(&price); err != nil || price != 19.99 {
+		t.Errorf("Expected the price to be restored to 19.99, got %v (%v)", price, err)
+	}
+}
@@ -0,0 +1,117 @@
+package test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"product-management-app/core/money"
+)
+
+func TestMoneyParse(t *testing.T) {
+	tests := []struct {
+		name        string
+		input       string
+		mode        money.RoundingMode
+		expected    money.Money
+		expectError bool
+	}{
+		{name: "Two decimals", input: "19.99", expected: 1999},
+		{name: "Whole number", input: "5", expected: 500},
+		{name: "One decimal", input: "0.1", expected: 10},
+		{name: "Surrounding spaces", input: " 7.50 ", expected: 750},
+		{name: "Negative", input: "-0.05", expected: -5},
+		{name: "Leading dot", input: ".25", expected: 25},
+		{name: "Half rounded away from zero", input: "0.285", expected: 29},
+		{name: "Negative half rounded away from zero", input: "-0.285", expected: -29},
+		{name: "Half rounded to even", input: "0.285", mode: money.RoundHalfEven, expected: 28},
+		{name: "Rounded up", input: "1.001", mode: money.RoundUp, expected: 101},
+		{name: "Rounded down", input: "1.009", mode: money.RoundDown, expected: 100},
+		{name: "Negative rounded down", input: "-1.001", mode: money.RoundDown, expected: -101},
+		{name: "Empty", input: "", expectError: true},
+		{name: "Letters", input: "abc", expectError: true},
+		{name: "Thousands separator", input: "1,000.00", expectError: true},
+		{name: "Fraction", input: "1/3", expectError: true},
+		{name: "Out of range", input: "999999999999999999999", expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := money.Parse(tt.input, tt.mode)
+			if tt.expectError && err == nil {
+				t.Errorf("Expected error but got none")
+			}
+			if !tt.expectError && err != nil {
+				t.Errorf("Unexpected error: %v", err)
+			}
+			if !tt.expectError && got != tt.expected {
+				t.Errorf("Parse(%q) = %d, expected %d", tt.input, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestMoneyArithmetic(t *testing.T) {
+	tests := []struct {
+		name     string
+		got      money.Money
+		expected money.Money
+	}{
+		{name: "Sum has no drift", got: money.FromFloat(0.1, money.RoundNearest) + money.FromFloat(0.2, money.RoundNearest), expected: 30},
+		{name: "Inventory value", got: money.FromMinorUnits(1999).Times(3), expected: 5997},
+		{name: "Exchange rate", got: money.FromUnits(100).Mul(5.4321, money.Cent, money.RoundNearest), expected: 54321},
+		{name: "Exchange rate rounded", got: money.FromMinorUnits(1999).Mul(0.9137, money.Cent, money.RoundNearest), expected: 1826},
+		{name: "Rounded to whole units", got: money.FromMinorUnits(1999).Mul(151.37, money.FromUnits(1), money.RoundNearest), expected: 302600},
+		{name: "Percent increase", got: money.FromMinorUnits(1999).AddPercent(10, money.Cent, money.RoundNearest), expected: 2199},
+		{name: "Amount added exactly", got: money.FromMinorUnits(10).AddAmount(0.2, money.Cent, money.RoundNearest), expected: 30},
+		{name: "Round to nickel", got: money.FromMinorUnits(121).RoundTo(5, money.RoundUp), expected: 125},
+		{name: "Exact multiple left alone", got: money.FromMinorUnits(125).RoundTo(5, money.RoundUp), expected: 125},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.got != tt.expected {
+				t.Errorf("Got %s, expected %s", tt.got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestMoneyJSON(t *testing.T) {
+	data, err := json.Marshal(struct {
+		Price money.Money `json:"price"`
+	}{Price: -1205})
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+	if string(data) != `{"price":-12.05}` {
+		t.Errorf("Unexpected JSON %s", data)
+	}
+
+	tests := []struct {
+		name        string
+		input       string
+		expected    money.Money
+		expectError bool
+	}{
+		{name: "Number", input: `19.99`, expected: 1999},
+		{name: "Float noise rounded", input: `21.989999999999998`, expected: 2199},
+		{name: "String", input: `"5.10"`, expected: 510},
+		{name: "Exponent", input: `1e2`, expected: 10000},
+		{name: "Boolean", input: `true`, expectError: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got money.Money
+			err := json.Unmarshal([]byte(tt.input), &got)
+			if tt.expectError && err == nil {
+				t.Errorf("Expected error but got none")
+			}
+			if !tt.expectError && err != nil {
+				t.Errorf("Unexpected error: %v", err)
+			}
+			if !tt.expectError && got != tt.expected {
+				t.Errorf("Unmarshal(%s) = %s, expected %s", tt.input, got, tt.expected)
+			}
+		})
+	}
+}
@@ -4,12 +4,13 @@ import (
 	"testing"
 
 	"product-management-app/core/dto"
+	"product-management-app/core/money"
 )
 
 func TestOptimizedDTOs(t *testing.T) {
 	importDTO := dto.NewProductImportDTO(
 		"Test Product",
-		99.99,
+		money.FromMinorUnits(9999),
 		"Test Category",
 		10,
 		"Test description",
@@ -22,8 +23,8 @@ func TestOptimizedDTOs(t *testing.T) {
 		t.Errorf("Incorrect name: expected 'Test Product', got '%s'", createDTO.Name)
 	}
 
-	if createDTO.Price != 99.99 {
-		t.Errorf("Incorrect price: expected 99.99, got %f", createDTO.Price)
+	if createDTO.Price.String() != "99.99" {
+		t.Errorf("Incorrect price: expected 99.99, got %s", createDTO.Price)
 	}
 
 	if createDTO.Stock != 10 {
@@ -0,0 +1,73 @@
+package test
+
+import (
+	"testing"
+
+	"product-management-app/core/dto"
+	"product-management-app/core/money"
+)
+
+func TestPaginationValidate(t *testing.T) {
+	minPrice, maxPrice := money.FromUnits(50), money.FromUnits(10)
+
+	tests := []struct {
+		name        string
+		params      dto.PaginationDTO
+		expectError bool
+	}{
+		{name: "Valid page", params: dto.PaginationDTO{Page: 1, PageSize: 10}},
+		{name: "Zero page", params: dto.PaginationDTO{Page: 0, PageSize: 10}, expectError: true},
+		{name: "Zero page size", params: dto.PaginationDTO{Page: 1, PageSize: 0}, expectError: true},
+		{name: "Page size too large", params: dto.PaginationDTO{Page: 1, PageSize: dto.MaxPageSize + 1}, expectError: true},
+		{
+			name:        "Inverted price range",
+			params:      dto.PaginationDTO{Page: 1, PageSize: 10, Filters: &dto.ProductFilterDTO{MinPrice: &minPrice, MaxPrice: &maxPrice}},
+			expectError: true,
+		},
+		{
+			name:        "Malformed date",
+			params:      dto.PaginationDTO{Page: 1, PageSize: 10, Filters: &dto.ProductFilterDTO{CreatedFrom: "yesterday"}},
+			expectError: true,
+		},
+		{
+			name:   "Valid filter",
+			params: dto.PaginationDTO{Page: 2, PageSize: 25, Filters: &dto.ProductFilterDTO{MaxPrice: &minPrice, CreatedFrom: "2024-01-31"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.params.Validate()
+			if tt.expectError && err == nil {
+				t.Errorf("Expected error but got none")
+			}
+			if !tt.expectError && err != nil {
+				t.Errorf("Unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestParseFilterDate(t *testing.T) {
+	tests := []struct {
+		value      string
+		upperBound bool
+		expected   string
+	}{
+		{value: "", expected: ""},
+		{value: "2024-03-05", expected: "2024-03-05 00:00:00"},
+		{value: "2024-03-05", upperBound: true, expected: "2024-03-05 23:59:59"},
+		{value: "2024-03-05T10:30:00-03:00", expected: "2024-03-05 13:30:00"},
+	}
+
+	for _, tt := range tests {
+		got, err := dto.ParseFilterDate(tt.value, tt.upperBound)
+		if err != nil {
+			t.Errorf("ParseFilterDate(%q) returned error: %v", tt.value, err)
+			continue
+		}
+		if got != tt.expected {
+			t.Errorf("ParseFilterDate(%q, %v) = %q, expected %q", tt.value, tt.upperBound, got, tt.expected)
+		}
+	}
+}
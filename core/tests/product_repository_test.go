@@ -0,0 +1,122 @@
+package test
+
+import (
+	"context"
+	"database/sql"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"product-management-app/core/dto"
+	"product-management-app/core/migrations"
+	"product-management-app/core/models"
+	"product-management-app/core/money"
+	"product-management-app/core/repositories"
+)
+
+// newTestProductRepository returns a repository over a temporary database
+// holding the given products, inserted in order.
+func newTestProductRepository(t *testing.T, products ...dto.CreateProductDTO) *repositories.ProductRepository {
+	t.Helper()
+	db := openProductDatabase(t)
+	insertProducts(t, db, products...)
+	return repositories.NewProductRepository(db, slog.Default())
+}
+
+// openProductDatabase opens a temporary database migrated to the latest
+// schema.
+func openProductDatabase(t *testing.T) *sql.DB {
+	t.Helper()
+	db := openTestDatabase(t)
+	migrator, err := migrations.NewMigrator(db)
+	if err != nil {
+		t.Fatalf("Failed to create migrator: %v", err)
+	}
+	if err := migrator.Prepare(); err != nil {
+		t.Fatalf("Prepare failed: %v", err)
+	}
+	if _, err := migrator.Up(); err != nil {
+		t.Fatalf("Up failed: %v", err)
+	}
+	return db
+}
+
+// insertProducts inserts products directly, bypassing the repository.
+func insertProducts(t *testing.T, db *sql.DB, products ...dto.CreateProductDTO) {
+	t.Helper()
+	for _, product := range products {
+		_, err := db.Exec("INSERT INTO products(name, price_cents, category, stock, description) VALUES(?, ?, ?, ?, ?)", product.Name, product.Price, product.Category, product.Stock, product.Description)
+		if err != nil {
+			t.Fatalf("Failed to insert product: %v", err)
+		}
+	}
+}
+
+func TestProductRepositoryGetAll(t *testing.T) {
+	ctx := context.Background()
+	repo := newTestProductRepository(t,
+		dto.CreateProductDTO{Name: "Apple", Price: money.FromMinorUnits(150), Category: "Fruit", Stock: 100},
+		dto.CreateProductDTO{Name: "Banana", Price: money.FromMinorUnits(50), Category: "Fruit", Stock: 0},
+		dto.CreateProductDTO{Name: "Carrot", Price: money.FromMinorUnits(80), Category: "Vegetable", Stock: 40, Description: "Crunchy 100% organic"},
+		dto.CreateProductDTO{Name: "Apple Pie", Price: money.FromUnits(6), Category: "Bakery", Stock: 4},
+	)
+
+	minPrice, minStock, hasDescription := money.FromMinorUnits(75), 1, true
+
+	tests := []struct {
+		name        string
+		params      dto.PaginationDTO
+		expected    []string
+		totalPages  int
+		expectError bool
+	}{
+		{name: "Default order", params: dto.PaginationDTO{Page: 1, PageSize: 10}, expected: []string{"Apple", "Banana", "Carrot", "Apple Pie"}, totalPages: 1},
+		{name: "Second page", params: dto.PaginationDTO{Page: 2, PageSize: 3}, expected: []string{"Apple Pie"}, totalPages: 2},
+		{name: "Sort by price desc", params: dto.PaginationDTO{Page: 1, PageSize: 10, SortBy: "price", Order: "desc"}, expected: []string{"Apple Pie", "Apple", "Carrot", "Banana"}, totalPages: 1},
+		{name: "Sort by name", params: dto.PaginationDTO{Page: 1, PageSize: 10, SortBy: "name"}, expected: []string{"Apple", "Apple Pie", "Banana", "Carrot"}, totalPages: 1},
+		{name: "Sort order ignores case", params: dto.PaginationDTO{Page: 1, PageSize: 10, SortBy: "stock", Order: "DESC"}, expected: []string{"Apple", "Carrot", "Apple Pie", "Banana"}, totalPages: 1},
+		{name: "Sort ties broken by id", params: dto.PaginationDTO{Page: 1, PageSize: 10, SortBy: "category"}, expected: []string{"Apple Pie", "Apple", "Banana", "Carrot"}, totalPages: 1},
+		{name: "Unknown sort column", params: dto.PaginationDTO{Page: 1, PageSize: 10, SortBy: "name; DROP TABLE products"}, expected: []string{"Apple", "Banana", "Carrot", "Apple Pie"}, totalPages: 1},
+		{name: "Search ignores case", params: dto.PaginationDTO{Page: 1, PageSize: 10, Search: "apple"}, expected: []string{"Apple", "Apple Pie"}, totalPages: 1},
+		{name: "Search matches category", params: dto.PaginationDTO{Page: 1, PageSize: 10, Search: "veget"}, expected: []string{"Carrot"}, totalPages: 1},
+		{name: "Search matches description", params: dto.PaginationDTO{Page: 1, PageSize: 10, Search: "crunchy"}, expected: []string{"Carrot"}, totalPages: 1},
+		{name: "Search treats wildcards literally", params: dto.PaginationDTO{Page: 1, PageSize: 10, Search: "100%"}, expected: []string{"Carrot"}, totalPages: 1},
+		{name: "Search with sort and paging", params: dto.PaginationDTO{Page: 1, PageSize: 1, Search: "fruit", SortBy: "price"}, expected: []string{"Banana"}, totalPages: 2},
+		{name: "Search without matches", params: dto.PaginationDTO{Page: 1, PageSize: 10, Search: "_"}, expected: []string{}, totalPages: 0},
+		{name: "Category filter", params: dto.PaginationDTO{Page: 1, PageSize: 10, Filters: &dto.ProductFilterDTO{Categories: []string{"Fruit", "Bakery"}}}, expected: []string{"Apple", "Banana", "Apple Pie"}, totalPages: 1},
+		{name: "Price and stock filter", params: dto.PaginationDTO{Page: 1, PageSize: 10, Filters: &dto.ProductFilterDTO{MinPrice: &minPrice, MinStock: &minStock}}, expected: []string{"Apple", "Carrot", "Apple Pie"}, totalPages: 1},
+		{name: "Description filter", params: dto.PaginationDTO{Page: 1, PageSize: 10, Filters: &dto.ProductFilterDTO{HasDescription: &hasDescription}}, expected: []string{"Carrot"}, totalPages: 1},
+		{name: "Created date filter", params: dto.PaginationDTO{Page: 1, PageSize: 10, Filters: &dto.ProductFilterDTO{CreatedTo: "2000-01-01"}}, expected: []string{}, totalPages: 0},
+		{name: "Filter combined with search", params: dto.PaginationDTO{Page: 1, PageSize: 10, Search: "apple", Filters: &dto.ProductFilterDTO{Categories: []string{"Bakery"}}}, expected: []string{"Apple Pie"}, totalPages: 1},
+		{name: "Invalid page", params: dto.PaginationDTO{Page: 0, PageSize: 10}, expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			response, err := repo.GetAll(ctx, tt.params)
+			if tt.expectError {
+				if err == nil {
+					t.Errorf("Expected error but got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if names := productNames(response.Products); strings.Join(names, ",") != strings.Join(tt.expected, ",") {
+				t.Errorf("Expected %v, got %v", tt.expected, names)
+			}
+			if response.TotalPages != tt.totalPages {
+				t.Errorf("Expected %d pages, got %d", tt.totalPages, response.TotalPages)
+			}
+		})
+	}
+}
+
+func productNames(products []*models.Product) []string {
+	names := make([]string, len(products))
+	for i, product := range products {
+		names[i] = product.Name
+	}
+	return names
+}
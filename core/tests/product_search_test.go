@@ -0,0 +1,199 @@
+package test
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"product-management-app/core/dto"
+	"product-management-app/core/money"
+	"product-management-app/core/repositories"
+	"product-management-app/core/sqlite"
+)
+
+func TestProductSearchValidate(t *testing.T) {
+	tests := []struct {
+		name        string
+		params      dto.ProductSearchDTO
+		expectError bool
+	}{
+		{name: "Valid search", params: dto.ProductSearchDTO{Query: "blue shoes", Page: 1, PageSize: 20}},
+		{name: "Blank query", params: dto.ProductSearchDTO{Query: "   ", Page: 1, PageSize: 20}, expectError: true},
+		{name: "Zero page", params: dto.ProductSearchDTO{Query: "shoes", Page: 0, PageSize: 20}, expectError: true},
+		{name: "Zero page size", params: dto.ProductSearchDTO{Query: "shoes", Page: 1}, expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.params.Validate()
+			if tt.expectError && err == nil {
+				t.Errorf("Expected error but got none")
+			}
+			if !tt.expectError && err != nil {
+				t.Errorf("Unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+// newSearchTestRepository returns a repository over a temporary database
+// with the full-text search index, skipping the test when SQLite was built
+// without FTS5. The products are inserted after the index so the triggers
+// mirror them.
+func newSearchTestRepository(t *testing.T, products ...dto.CreateProductDTO) *repositories.ProductRepository {
+	t.Helper()
+	db := openProductDatabase(t)
+	fts5Enabled, err := sqlite.HasFTS5(db)
+	if err != nil {
+		t.Fatalf("Failed to inspect SQLite compile options: %v", err)
+	}
+	if !fts5Enabled {
+		t.Skip("SQLite was built without the FTS5 extension")
+	}
+	for _, statement := range repositories.FullTextSearchSchema {
+		if _, err := db.Exec(statement); err != nil {
+			t.Fatalf("Failed to create full-text search index: %v", err)
+		}
+	}
+	insertProducts(t, db, products...)
+	return repositories.NewProductRepository(db, slog.Default())
+}
+
+func TestProductRepositorySearch(t *testing.T) {
+	ctx := context.Background()
+	repo := newSearchTestRepository(t,
+		dto.CreateProductDTO{Name: "Blue Shoes", Price: money.FromUnits(50), Category: "Footwear", Stock: 1},
+		dto.CreateProductDTO{Name: "Sandals", Price: money.FromUnits(30), Category: "Footwear", Stock: 1},
+		dto.CreateProductDTO{Name: "Socks", Price: money.FromUnits(5), Category: "Clothing", Stock: 1, Description: "Warm socks to wear with blue shoes"},
+		dto.CreateProductDTO{Name: "Crème brûlée", Price: money.FromUnits(4), Category: "Dessert", Stock: 1},
+	)
+
+	tests := []struct {
+		name        string
+		params      dto.ProductSearchDTO
+		expected    []string
+		totalCount  int
+		expectError bool
+	}{
+		{name: "Name match ranks above description match", params: dto.ProductSearchDTO{Query: "blue shoes", Page: 1, PageSize: 10}, expected: []string{"Blue Shoes", "Socks"}, totalCount: 2},
+		{name: "Words match as prefixes", params: dto.ProductSearchDTO{Query: "blu sho", Page: 1, PageSize: 10}, expected: []string{"Blue Shoes", "Socks"}, totalCount: 2},
+		{name: "Every word must match", params: dto.ProductSearchDTO{Query: "blue sandals", Page: 1, PageSize: 10}, expected: []string{}, totalCount: 0},
+		{name: "Category match", params: dto.ProductSearchDTO{Query: "foot", Page: 1, PageSize: 10}, expected: []string{"Sandals", "Blue Shoes"}, totalCount: 2},
+		{name: "Second page", params: dto.ProductSearchDTO{Query: "foot", Page: 2, PageSize: 1}, expected: []string{"Blue Shoes"}, totalCount: 2},
+		{name: "Diacritics ignored", params: dto.ProductSearchDTO{Query: "creme brulee", Page: 1, PageSize: 10}, expected: []string{"Crème brûlée"}, totalCount: 1},
+		{name: "Operators treated as words", params: dto.ProductSearchDTO{Query: `sandals OR "socks`, Page: 1, PageSize: 10}, expected: []string{}, totalCount: 0},
+		{name: "Punctuation only", params: dto.ProductSearchDTO{Query: `"*`, Page: 1, PageSize: 10}, expected: []string{}, totalCount: 0},
+		{name: "Blank query", params: dto.ProductSearchDTO{Query: " ", Page: 1, PageSize: 10}, expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			response, err := repo.Search(ctx, tt.params)
+			if tt.expectError {
+				if err == nil {
+					t.Errorf("Expected error but got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			names := make([]string, len(response.Results))
+			for i, result := range response.Results {
+				names[i] = result.Product.Name
+			}
+			if strings.Join(names, ",") != strings.Join(tt.expected, ",") {
+				t.Errorf("Expected %v, got %v", tt.expected, names)
+			}
+			if response.TotalCount != tt.totalCount {
+				t.Errorf("Expected %d results in total, got %d", tt.totalCount, response.TotalCount)
+			}
+		})
+	}
+}
+
+func TestProductRepositorySearchHighlights(t *testing.T) {
+	ctx := context.Background()
+	repo := newSearchTestRepository(t,
+		dto.CreateProductDTO{Name: "Blue Shoes", Price: money.FromUnits(50), Category: "Footwear", Stock: 1},
+		dto.CreateProductDTO{Name: "Socks", Price: money.FromUnits(5), Category: "Clothing", Stock: 1, Description: "Warm socks to wear with blue shoes"},
+	)
+
+	response, err := repo.Search(ctx, dto.ProductSearchDTO{Query: "blue sho", Page: 1, PageSize: 10})
+	if err != nil {
+		t.Fatalf("Search failed: %v", err)
+	}
+	if len(response.Results) != 2 {
+		t.Fatalf("Expected 2 results, got %d", len(response.Results))
+	}
+	first, second := response.Results[0], response.Results[1]
+	if first.Rank <= second.Rank {
+		t.Errorf("Expected the name match to rank higher, got %v and %v", first.Rank, second.Rank)
+	}
+	if first.NameHighlight != "<mark>Blue</mark> <mark>Shoes</mark>" {
+		t.Errorf("Unexpected name highlight %q", first.NameHighlight)
+	}
+	if second.NameHighlight != "Socks" {
+		t.Errorf("Unexpected name highlight %q", second.NameHighlight)
+	}
+	if second.DescriptionSnippet != "Warm socks to wear with <mark>blue</mark> <mark>shoes</mark>" {
+		t.Errorf("Unexpected description snippet %q", second.DescriptionSnippet)
+	}
+
+	response, err = repo.Search(ctx, dto.ProductSearchDTO{Query: "cloth", Page: 1, PageSize: 10})
+	if err != nil {
+		t.Fatalf("Search failed: %v", err)
+	}
+	if len(response.Results) != 1 || response.Results[0].CategoryHighlight != "<mark>Clothing</mark>" {
+		t.Errorf("Unexpected category highlight: %+v", response.Results)
+	}
+}
+
+func TestProductRepositorySearchFollowsWrites(t *testing.T) {
+	ctx := context.Background()
+	repo := newSearchTestRepository(t,
+		dto.CreateProductDTO{Name: "Blue Shoes", Price: money.FromUnits(50), Category: "Footwear", Stock: 1},
+		dto.CreateProductDTO{Name: "Sandals", Price: money.FromUnits(30), Category: "Footwear", Stock: 1},
+	)
+	search := func(query string) []string {
+		t.Helper()
+		response, err := repo.Search(ctx, dto.ProductSearchDTO{Query: query, Page: 1, PageSize: 10})
+		if err != nil {
+			t.Fatalf("Search failed: %v", err)
+		}
+		names := make([]string, len(response.Results))
+		for i, result := range response.Results {
+			names[i] = result.Product.Name
+		}
+		return names
+	}
+
+	name := "Red Boots"
+	if _, err := repo.Update(ctx, 1, dto.UpdateProductDTO{Version: 1, Name: &name}); err != nil {
+		t.Fatalf("Update failed: %v", err)
+	}
+	if names := search("blue"); len(names) != 0 {
+		t.Errorf("Expected the old name to be removed from the index, got %v", names)
+	}
+	if names := search("boots"); len(names) != 1 || names[0] != "Red Boots" {
+		t.Errorf("Expected the new name to be indexed, got %v", names)
+	}
+
+	if err := repo.Delete(ctx, 2, 1); err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+	if names := search("sandals"); len(names) != 0 {
+		t.Errorf("Expected a trashed product to be hidden from search, got %v", names)
+	}
+	if names := search("footwear"); len(names) != 1 || names[0] != "Red Boots" {
+		t.Errorf("Expected only the remaining product, got %v", names)
+	}
+
+	if err := repo.Restore(ctx, 2, 2); err != nil {
+		t.Fatalf("Restore failed: %v", err)
+	}
+	if names := search("sandals"); len(names) != 1 {
+		t.Errorf("Expected a restored product to be found again, got %v", names)
+	}
+}
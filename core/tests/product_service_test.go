@@ -0,0 +1,162 @@
+package test
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"product-management-app/core/config"
+	"product-management-app/core/dto"
+	apperrors "product-management-app/core/errors"
+	"product-management-app/core/money"
+	service "product-management-app/core/services"
+	"product-management-app/core/validation"
+)
+
+// TestProductServiceHeadless runs the service outside a Wails app, with a
+// plain slog logger and a channel standing in for the frontend events.
+func TestProductServiceHeadless(t *testing.T) {
+	ctx := context.Background()
+	var logs bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&logs, nil))
+	events := make(chan dto.MaintenanceJobDTO, 16)
+	emit := func(name string, data interface{}) {
+		if job, ok := data.(dto.MaintenanceJobDTO); ok && name == service.MaintenanceJobEvent {
+			events <- job
+		}
+	}
+
+	scheduled := false
+	cfg := config.DatabaseConfig{
+		Path:       filepath.Join(t.TempDir(), "products.db"),
+		PathSource: config.SourceFlag,
+		Backup:     config.BackupConfig{Scheduled: &scheduled},
+	}
+	products := service.NewProductService(cfg, validation.NewProductValidator(config.ValidationConfig{}), logger, emit)
+	if err := products.InitDatabase(); err != nil {
+		t.Fatalf("Failed to initialize database: %v", err)
+	}
+	defer products.CloseDatabase()
+
+	created, err := products.CreateProduct(ctx, dto.CreateProductDTO{Name: "Keyboard", Price: money.FromMinorUnits(4990), Stock: 3})
+	if err != nil {
+		t.Fatalf("Failed to create product: %v", err)
+	}
+	if _, err := products.CreateProduct(ctx, dto.CreateProductDTO{Name: "Broken", Price: money.FromUnits(-1)}); apperrors.CodeOf(err) != apperrors.CodeValidation {
+		t.Errorf("Expected a validation error for a negative price, got %v", err)
+	}
+	result, err := products.ImportProductsFromCSV(ctx, writeTempFile(t, "products.csv", []byte("Name,Price,Category,Stock,Description,Image URL\nMouse,19.90,Peripherals,5,,\n,5,,1,,\n")), dto.ImportOptions{})
+	if err != nil {
+		t.Fatalf("Failed to import products: %v", err)
+	}
+	if result.SuccessCount != 1 || result.CreatedCount != 1 || result.ErrorCount != 1 {
+		t.Errorf("Expected 1 created product and 1 failed row, got %+v", result)
+	}
+	if len(result.Errors) != 1 || result.Errors[0].Row != 3 || result.Errors[0].Field != "name" {
+		t.Errorf("Expected the nameless row to fail on its name, got %+v", result.Errors)
+	}
+	page, err := products.GetAllProducts(ctx, dto.PaginationDTO{Page: 1, PageSize: 10})
+	if err != nil {
+		t.Fatalf("Failed to list products: %v", err)
+	}
+	if page.TotalCount != 2 {
+		t.Errorf("Expected 2 products, got %d", page.TotalCount)
+	}
+
+	job, err := products.StartMaintenance(dto.MaintenanceStats)
+	if err != nil {
+		t.Fatalf("Failed to start maintenance: %v", err)
+	}
+	timeout := time.After(10 * time.Second)
+	for finished := false; !finished; {
+		select {
+		case event := <-events:
+			if event.ID == job.ID && event.FinishedAt != "" {
+				finished = true
+				if event.Status != dto.JobCompleted {
+					t.Errorf("Expected job to complete, got %s: %s", event.Status, event.Error)
+				}
+			}
+		case <-timeout:
+			t.Fatal("Timed out waiting for the maintenance job event")
+		}
+	}
+
+	products.CloseDatabase()
+	for _, want := range []string{
+		`msg="Product created" id=` + strconv.Itoa(created.ID),
+		`msg="Maintenance job finished"`,
+		`msg="Database connection closed"`,
+	} {
+		if !strings.Contains(logs.String(), want) {
+			t.Errorf("Expected the log to contain %q, got:\n%s", want, logs.String())
+		}
+	}
+}
+
+// TestProductServiceReopen checks that opening the database again closes
+// the connection pool already open, and that a failed open leaves the
+// service closed rather than holding on to the previous services or to the
+// pool it just opened.
+func TestProductServiceReopen(t *testing.T) {
+	ctx := context.Background()
+	var logs bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&logs, nil))
+	scheduled := false
+	cfg := config.DatabaseConfig{
+		Path:       filepath.Join(t.TempDir(), "products.db"),
+		PathSource: config.SourceFlag,
+		Backup:     config.BackupConfig{Scheduled: &scheduled},
+	}
+	products := service.NewProductService(cfg, validation.NewProductValidator(config.ValidationConfig{}), logger, nil)
+	t.Cleanup(products.CloseDatabase)
+
+	if err := products.InitDatabase(); err != nil {
+		t.Fatalf("Failed to initialize database: %v", err)
+	}
+	createServiceProduct(t, products, "Lamp")
+	if err := products.InitDatabase(); err != nil {
+		t.Fatalf("Failed to reopen database: %v", err)
+	}
+	response, err := products.GetAllProducts(ctx, dto.PaginationDTO{Page: 1, PageSize: 10})
+	if err != nil || response.TotalCount != 1 {
+		t.Fatalf("Expected the reopened database to hold 1 product, got %+v (%v)", response, err)
+	}
+
+	// Replace the database file with one that cannot be migrated.
+	products.CloseDatabase()
+	for _, suffix := range []string{"", "-wal", "-shm"} {
+		if err := os.Remove(cfg.Path + suffix); err != nil && !os.IsNotExist(err) {
+			t.Fatalf("Failed to remove %s: %v", cfg.Path+suffix, err)
+		}
+	}
+	if err := os.WriteFile(cfg.Path, []byte("not a database"), 0o600); err != nil {
+		t.Fatalf("Failed to write %s: %v", cfg.Path, err)
+	}
+	if err := products.InitDatabase(); err == nil {
+		t.Fatal("Expected opening a corrupt database to fail")
+	}
+	// Three pools were opened, the last by the failed call.
+	if closed := strings.Count(logs.String(), `msg="Database connection closed"`); closed != 3 {
+		t.Errorf("Expected 3 connection pools to be closed, got %d", closed)
+	}
+
+	if err := products.HealthCheck(ctx); apperrors.CodeOf(err) != apperrors.CodeUnavailable {
+		t.Errorf("Expected the health check to report the service closed, got %v", err)
+	}
+	if _, err := products.GetAllProducts(ctx, dto.PaginationDTO{Page: 1, PageSize: 10}); apperrors.CodeOf(err) != apperrors.CodeUnavailable {
+		t.Errorf("Expected products to be unavailable, got %v", err)
+	}
+	if _, err := products.ListBackups(); apperrors.CodeOf(err) != apperrors.CodeUnavailable {
+		t.Errorf("Expected backups to be unavailable, got %v", err)
+	}
+	if _, err := products.ListImportBatches(ctx, 10); apperrors.CodeOf(err) != apperrors.CodeUnavailable {
+		t.Errorf("Expected import batches to be unavailable, got %v", err)
+	}
+}
@@ -0,0 +1,659 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+
+	"product-management-app/core/dto"
+	apperrors "product-management-app/core/errors"
+	"product-management-app/core/models"
+	"product-management-app/core/money"
+	"product-management-app/core/repositories"
+	"product-management-app/core/sqlite"
+)
+
+func TestSQLiteProductStore(t *testing.T) {
+	// Like the application, only create the full-text search index when
+	// SQLite was built with FTS5, which -tags sqlite_cgo alone leaves out.
+	fullTextSearch, err := sqlite.HasFTS5(openTestDatabase(t))
+	if err != nil {
+		t.Fatalf("Failed to inspect SQLite compile options: %v", err)
+	}
+	runProductStoreSuite(t, fullTextSearch, func(t *testing.T) repositories.ProductStore {
+		db := openProductDatabase(t)
+		if !fullTextSearch {
+			return repositories.NewProductRepository(db, slog.Default())
+		}
+		for _, statement := range repositories.FullTextSearchSchema {
+			if _, err := db.Exec(statement); err != nil {
+				t.Fatalf("Failed to create full-text search index: %v", err)
+			}
+		}
+		return repositories.NewProductRepository(db, slog.Default())
+	})
+}
+
+func TestMemoryProductStore(t *testing.T) {
+	runProductStoreSuite(t, true, func(t *testing.T) repositories.ProductStore {
+		return repositories.NewMemoryProductStore()
+	})
+}
+
+// runProductStoreSuite checks the behaviour every ProductStore must share.
+// Search is only checked when fullTextSearch is set.
+func runProductStoreSuite(t *testing.T, fullTextSearch bool, newStore func(t *testing.T) repositories.ProductStore) {
+	ctx := context.Background()
+
+	t.Run("Create and get", func(t *testing.T) {
+		store := newStore(t)
+		created := createTestProduct(t, store, "Blue Shoes", 49.9, "Footwear", 3)
+		if created.ID < 1 || created.Version != 1 {
+			t.Fatalf("Expected a new product at version 1, got ID %d version %d", created.ID, created.Version)
+		}
+
+		product, err := store.GetByID(ctx, created.ID)
+		if err != nil {
+			t.Fatalf("GetByID failed: %v", err)
+		}
+		if product.Name != "Blue Shoes" || product.Price != money.FromMinorUnits(4990) || product.Stock != 3 || product.Version != 1 {
+			t.Errorf("Unexpected product: %+v", product)
+		}
+		if product.Category == nil || *product.Category != "Footwear" {
+			t.Errorf("Expected category Footwear, got %v", product.Category)
+		}
+		if _, err := time.Parse(time.RFC3339, product.CreatedAt); err != nil {
+			t.Errorf("Expected an RFC 3339 creation time, got %q", product.CreatedAt)
+		}
+
+		if _, err := store.GetByID(ctx, created.ID+100); err == nil {
+			t.Errorf("Expected an error for a missing product")
+		}
+	})
+
+	t.Run("Empty optional fields", func(t *testing.T) {
+		store := newStore(t)
+		created, err := store.Create(ctx, dto.CreateProductDTO{Name: "Plain", Price: money.FromUnits(1)})
+		if err != nil {
+			t.Fatalf("Create failed: %v", err)
+		}
+		product, err := store.GetByID(ctx, created.ID)
+		if err != nil {
+			t.Fatalf("GetByID failed: %v", err)
+		}
+		for _, p := range []*models.Product{created, product} {
+			if p.SKU != nil || p.Category != nil || p.Description != nil || p.ImageURL != nil {
+				t.Errorf("Expected empty optional fields to be nil, got SKU %v category %v description %v image URL %v", p.SKU, p.Category, p.Description, p.ImageURL)
+			}
+		}
+
+		filled, err := store.Create(ctx, dto.CreateProductDTO{Name: "Filled", Price: money.FromUnits(1), Category: "Home", Description: "Lamp", ImageURL: "https://example.com/lamp.png"})
+		if err != nil {
+			t.Fatalf("Create failed: %v", err)
+		}
+		empty := ""
+		updated, err := store.Update(ctx, filled.ID, dto.UpdateProductDTO{Category: &empty, Description: &empty, ImageURL: &empty, Version: filled.Version})
+		if err != nil {
+			t.Fatalf("Update failed: %v", err)
+		}
+		product, err = store.GetByID(ctx, filled.ID)
+		if err != nil {
+			t.Fatalf("GetByID failed: %v", err)
+		}
+		for _, p := range []*models.Product{updated, product} {
+			if p.Category != nil || p.Description != nil || p.ImageURL != nil {
+				t.Errorf("Expected fields updated to empty to be nil, got category %v description %v image URL %v", p.Category, p.Description, p.ImageURL)
+			}
+		}
+	})
+
+	t.Run("Search folds ASCII case only", func(t *testing.T) {
+		store := newStore(t)
+		createTestProduct(t, store, "Crème brûlée", 4, "Dessert", 1)
+		createTestProduct(t, store, "Éclair", 3, "Dessert", 1)
+
+		tests := []struct {
+			search   string
+			expected []string
+		}{
+			{search: "CRème", expected: []string{"Crème brûlée"}},
+			{search: "CRÈME", expected: []string{}},
+			{search: "ÉCLAIR", expected: []string{"Éclair"}},
+			{search: "éclair", expected: []string{}},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.search, func(t *testing.T) {
+				response, err := store.GetAll(ctx, dto.PaginationDTO{Page: 1, PageSize: 10, Search: tt.search})
+				if err != nil {
+					t.Fatalf("GetAll failed: %v", err)
+				}
+				if names := productNames(response.Products); strings.Join(names, ",") != strings.Join(tt.expected, ",") {
+					t.Errorf("Expected %v, got %v", tt.expected, names)
+				}
+			})
+		}
+	})
+
+	t.Run("Update", func(t *testing.T) {
+		store := newStore(t)
+		created := createTestProduct(t, store, "Lamp", 20, "Home", 5)
+
+		name := "Desk Lamp"
+		updated, err := store.Update(ctx, created.ID, dto.UpdateProductDTO{Version: 1, Name: &name, Clear: []string{dto.FieldCategory}})
+		if err != nil {
+			t.Fatalf("Update failed: %v", err)
+		}
+		if updated.Name != name || updated.Category != nil || updated.Price != money.FromUnits(20) || updated.Version != 2 {
+			t.Errorf("Unexpected product after update: %+v", updated)
+		}
+		if updated.UpdatedAt == nil {
+			t.Errorf("Expected an update time")
+		} else if _, err := time.Parse(time.RFC3339, *updated.UpdatedAt); err != nil {
+			t.Errorf("Expected an RFC 3339 update time, got %q", *updated.UpdatedAt)
+		}
+
+		_, err = store.Update(ctx, created.ID, dto.UpdateProductDTO{Version: 1, Name: &name})
+		var conflict *apperrors.ConflictError
+		if !errors.As(err, &conflict) || conflict.CurrentVersion != 2 {
+			t.Errorf("Expected a conflict at version 2, got %v", err)
+		}
+		var notFound *apperrors.NotFoundError
+		if _, err := store.Update(ctx, created.ID+100, dto.UpdateProductDTO{Version: 1, Name: &name}); !errors.As(err, &notFound) {
+			t.Errorf("Expected a not found error, got %v", err)
+		}
+		if _, err := store.Update(ctx, created.ID, dto.UpdateProductDTO{Version: 2}); err == nil {
+			t.Errorf("Expected an error for an empty update")
+		}
+	})
+
+	t.Run("SKU and name lookups", func(t *testing.T) {
+		store := newStore(t)
+		mug, err := store.Create(ctx, dto.CreateProductDTO{Name: "Mug", SKU: "MUG-1", Price: money.FromUnits(5)})
+		if err != nil {
+			t.Fatalf("Create failed: %v", err)
+		}
+		plain := createTestProduct(t, store, "Mug", 6, "", 1)
+		other := createTestProduct(t, store, "Plate", 7, "", 1)
+		if plain.SKU != nil {
+			t.Errorf("Expected no SKU, got %q", *plain.SKU)
+		}
+
+		found, err := store.GetBySKU(ctx, "MUG-1")
+		if err != nil || found.ID != mug.ID || found.SKU == nil || *found.SKU != "MUG-1" {
+			t.Fatalf("Expected product %d by SKU, got %+v (%v)", mug.ID, found, err)
+		}
+		var notFound *apperrors.NotFoundError
+		if _, err := store.GetBySKU(ctx, "mug-1"); !errors.As(err, &notFound) {
+			t.Errorf("Expected SKUs to be case-sensitive, got %v", err)
+		}
+
+		named, err := store.FindByName(ctx, "Mug")
+		if err != nil {
+			t.Fatalf("FindByName failed: %v", err)
+		}
+		if len(named) != 2 || named[0].ID != mug.ID || named[1].ID != plain.ID {
+			t.Errorf("Expected both mugs oldest first, got %v", productNames(named))
+		}
+		if named, _ := store.FindByName(ctx, "mug"); len(named) != 0 {
+			t.Errorf("Expected names to match exactly, got %v", productNames(named))
+		}
+
+		var conflict *apperrors.ConflictError
+		if _, err := store.Create(ctx, dto.CreateProductDTO{Name: "Copy", SKU: "MUG-1", Price: 1}); !errors.As(err, &conflict) {
+			t.Errorf("Expected a conflict for a duplicate SKU, got %v", err)
+		}
+		taken := "MUG-1"
+		if _, err := store.Update(ctx, other.ID, dto.UpdateProductDTO{Version: 1, SKU: &taken}); !errors.As(err, &conflict) {
+			t.Errorf("Expected a conflict for a taken SKU, got %v", err)
+		}
+		if _, err := store.Update(ctx, mug.ID, dto.UpdateProductDTO{Version: 1, SKU: &taken}); err != nil {
+			t.Errorf("Expected a product to keep its own SKU, got %v", err)
+		}
+
+		if err := store.Delete(ctx, mug.ID, 2); err != nil {
+			t.Fatalf("Delete failed: %v", err)
+		}
+		if _, err := store.GetBySKU(ctx, "MUG-1"); !errors.As(err, &notFound) {
+			t.Errorf("Expected a trashed product not to be found by SKU, got %v", err)
+		}
+		if _, err := store.Create(ctx, dto.CreateProductDTO{Name: "Copy", SKU: "MUG-1", Price: 1}); !errors.As(err, &conflict) {
+			t.Errorf("Expected a trashed product to keep its SKU, got %v", err)
+		}
+
+		updated, err := store.Update(ctx, other.ID, dto.UpdateProductDTO{Version: 1, SKU: stringPtr("PLATE-1")})
+		if err != nil || updated.SKU == nil || *updated.SKU != "PLATE-1" {
+			t.Fatalf("Expected SKU PLATE-1, got %+v (%v)", updated, err)
+		}
+		cleared, err := store.Update(ctx, other.ID, dto.UpdateProductDTO{Version: 2, Clear: []string{dto.FieldSKU}})
+		if err != nil || cleared.SKU != nil {
+			t.Errorf("Expected the SKU to be cleared, got %+v (%v)", cleared, err)
+		}
+	})
+
+	t.Run("Trash", func(t *testing.T) {
+		store := newStore(t)
+		kept := createTestProduct(t, store, "Kept", 1, "", 1)
+		trashed := createTestProduct(t, store, "Trashed", 1, "", 1)
+
+		var conflict *apperrors.ConflictError
+		if err := store.Delete(ctx, trashed.ID, 2); !errors.As(err, &conflict) {
+			t.Errorf("Expected a conflict for a stale delete, got %v", err)
+		}
+		if err := store.Delete(ctx, trashed.ID, 1); err != nil {
+			t.Fatalf("Delete failed: %v", err)
+		}
+		if _, err := store.GetByID(ctx, trashed.ID); err == nil {
+			t.Errorf("Expected a trashed product to be hidden")
+		}
+		var notFound *apperrors.NotFoundError
+		if err := store.Delete(ctx, trashed.ID, 2); !errors.As(err, &notFound) {
+			t.Errorf("Expected a not found error for a trashed product, got %v", err)
+		}
+
+		trash, err := store.GetTrash(ctx, dto.PaginationDTO{Page: 1, PageSize: 10})
+		if err != nil {
+			t.Fatalf("GetTrash failed: %v", err)
+		}
+		if trash.TotalCount != 1 || trash.Products[0].ID != trashed.ID || trash.Products[0].DeletedAt == nil {
+			t.Fatalf("Unexpected trash: %+v", trash)
+		}
+
+		if err := store.Restore(ctx, kept.ID, 1); err == nil {
+			t.Errorf("Expected an error restoring a live product")
+		}
+		if err := store.Purge(ctx, kept.ID); err == nil {
+			t.Errorf("Expected an error purging a live product")
+		}
+		if err := store.Restore(ctx, trashed.ID, 2); err != nil {
+			t.Fatalf("Restore failed: %v", err)
+		}
+		restored, err := store.GetByID(ctx, trashed.ID)
+		if err != nil {
+			t.Fatalf("GetByID after restore failed: %v", err)
+		}
+		if restored.Version != 3 || restored.DeletedAt != nil {
+			t.Errorf("Unexpected product after restore: %+v", restored)
+		}
+
+		if err := store.Delete(ctx, trashed.ID, 3); err != nil {
+			t.Fatalf("Delete failed: %v", err)
+		}
+		if err := store.Delete(ctx, kept.ID, 1); err != nil {
+			t.Fatalf("Delete failed: %v", err)
+		}
+		if err := store.Purge(ctx, trashed.ID); err != nil {
+			t.Fatalf("Purge failed: %v", err)
+		}
+		if err := store.Restore(ctx, trashed.ID, 4); err == nil {
+			t.Errorf("Expected an error restoring a purged product")
+		}
+		if _, err := store.PurgeOlderThan(ctx, -1); err == nil {
+			t.Errorf("Expected an error for a negative retention")
+		}
+		purged, err := store.PurgeOlderThan(ctx, 0)
+		if err != nil {
+			t.Fatalf("PurgeOlderThan failed: %v", err)
+		}
+		if purged != 1 {
+			t.Errorf("Expected 1 product purged, got %d", purged)
+		}
+
+		next := createTestProduct(t, store, "Next", 1, "", 1)
+		if next.ID <= kept.ID || next.ID <= trashed.ID {
+			t.Errorf("Expected purged IDs not to be reused, got %d", next.ID)
+		}
+	})
+
+	t.Run("List", func(t *testing.T) {
+		store := newStore(t)
+		createTestProduct(t, store, "Apple", 1.5, "Fruit", 100)
+		createTestProduct(t, store, "Banana", 0.5, "Fruit", 0)
+		createTestProduct(t, store, "Carrot", 0.8, "Vegetable", 40)
+		createTestProduct(t, store, "Apple Pie", 6, "Bakery", 4)
+
+		minPrice, minStock := money.FromMinorUnits(75), 1
+		tests := []struct {
+			name        string
+			params      dto.PaginationDTO
+			expected    []string
+			totalPages  int
+			expectError bool
+		}{
+			{name: "Default order", params: dto.PaginationDTO{Page: 1, PageSize: 10}, expected: []string{"Apple", "Banana", "Carrot", "Apple Pie"}, totalPages: 1},
+			{name: "Second page", params: dto.PaginationDTO{Page: 2, PageSize: 3}, expected: []string{"Apple Pie"}, totalPages: 2},
+			{name: "Sort by price desc", params: dto.PaginationDTO{Page: 1, PageSize: 10, SortBy: "price", Order: "desc"}, expected: []string{"Apple Pie", "Apple", "Carrot", "Banana"}, totalPages: 1},
+			{name: "Sort by name", params: dto.PaginationDTO{Page: 1, PageSize: 10, SortBy: "name"}, expected: []string{"Apple", "Apple Pie", "Banana", "Carrot"}, totalPages: 1},
+			{name: "Unknown sort column", params: dto.PaginationDTO{Page: 1, PageSize: 10, SortBy: "secret"}, expected: []string{"Apple", "Banana", "Carrot", "Apple Pie"}, totalPages: 1},
+			{name: "Search ignores case", params: dto.PaginationDTO{Page: 1, PageSize: 10, Search: "apple"}, expected: []string{"Apple", "Apple Pie"}, totalPages: 1},
+			{name: "Search matches category", params: dto.PaginationDTO{Page: 1, PageSize: 10, Search: "veget"}, expected: []string{"Carrot"}, totalPages: 1},
+			{name: "Search treats wildcards literally", params: dto.PaginationDTO{Page: 1, PageSize: 10, Search: "%"}, expected: []string{}, totalPages: 0},
+			{name: "Category filter", params: dto.PaginationDTO{Page: 1, PageSize: 10, Filters: &dto.ProductFilterDTO{Categories: []string{"Fruit", "Bakery"}}}, expected: []string{"Apple", "Banana", "Apple Pie"}, totalPages: 1},
+			{name: "Price and stock filter", params: dto.PaginationDTO{Page: 1, PageSize: 10, Filters: &dto.ProductFilterDTO{MinPrice: &minPrice, MinStock: &minStock}}, expected: []string{"Apple", "Carrot", "Apple Pie"}, totalPages: 1},
+			{name: "Created date filter", params: dto.PaginationDTO{Page: 1, PageSize: 10, Filters: &dto.ProductFilterDTO{CreatedTo: "2000-01-01"}}, expected: []string{}, totalPages: 0},
+			{name: "Invalid page", params: dto.PaginationDTO{Page: 0, PageSize: 10}, expectError: true},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				response, err := store.GetAll(ctx, tt.params)
+				if tt.expectError {
+					if err == nil {
+						t.Errorf("Expected error but got none")
+					}
+					return
+				}
+				if err != nil {
+					t.Fatalf("Unexpected error: %v", err)
+				}
+				if names := productNames(response.Products); strings.Join(names, ",") != strings.Join(tt.expected, ",") {
+					t.Errorf("Expected %v, got %v", tt.expected, names)
+				}
+				if response.TotalPages != tt.totalPages {
+					t.Errorf("Expected %d pages, got %d", tt.totalPages, response.TotalPages)
+				}
+			})
+		}
+	})
+
+	t.Run("Search", func(t *testing.T) {
+		if !fullTextSearch {
+			t.Skip("SQLite was built without the FTS5 extension")
+		}
+		store := newStore(t)
+		shoes := createTestProduct(t, store, "Blue Shoes", 50, "Footwear", 1)
+		createTestProduct(t, store, "Sandals", 30, "Footwear", 1)
+		socks := createTestProduct(t, store, "Socks", 5, "Clothing", 1)
+		if _, err := store.Update(ctx, socks.ID, dto.UpdateProductDTO{Version: 1, Description: stringPtr("Warm socks to wear with blue shoes")}); err != nil {
+			t.Fatalf("Update failed: %v", err)
+		}
+		hidden := createTestProduct(t, store, "Blue Shoes Old", 10, "Footwear", 1)
+		if err := store.Delete(ctx, hidden.ID, 1); err != nil {
+			t.Fatalf("Delete failed: %v", err)
+		}
+
+		response, err := store.Search(ctx, dto.ProductSearchDTO{Query: "blue sho", Page: 1, PageSize: 10})
+		if err != nil {
+			t.Fatalf("Search failed: %v", err)
+		}
+		if response.TotalCount != 2 || len(response.Results) != 2 {
+			t.Fatalf("Expected 2 results, got %d", response.TotalCount)
+		}
+		first := response.Results[0]
+		if first.Product.ID != shoes.ID {
+			t.Errorf("Expected the name match first, got %s", first.Product.Name)
+		}
+		if first.Rank <= response.Results[1].Rank {
+			t.Errorf("Expected the name match to rank higher")
+		}
+		if first.NameHighlight != "<mark>Blue</mark> <mark>Shoes</mark>" {
+			t.Errorf("Unexpected name highlight %q", first.NameHighlight)
+		}
+		if !strings.Contains(response.Results[1].DescriptionSnippet, "<mark>blue</mark>") {
+			t.Errorf("Unexpected description snippet %q", response.Results[1].DescriptionSnippet)
+		}
+
+		response, err = store.Search(ctx, dto.ProductSearchDTO{Query: "foot", Page: 2, PageSize: 1})
+		if err != nil {
+			t.Fatalf("Search failed: %v", err)
+		}
+		if response.TotalCount != 2 || response.TotalPages != 2 || len(response.Results) != 1 {
+			t.Errorf("Unexpected second page: %+v", response)
+		}
+
+		if _, err := store.Search(ctx, dto.ProductSearchDTO{Query: " ", Page: 1, PageSize: 10}); err == nil {
+			t.Errorf("Expected an error for a blank query")
+		}
+	})
+
+	t.Run("Search escapes highlights", func(t *testing.T) {
+		if !fullTextSearch {
+			t.Skip("SQLite was built without the FTS5 extension")
+		}
+		store := newStore(t)
+		_, err := store.Create(ctx, dto.CreateProductDTO{
+			Name:        `<script>alert("x")</script> Shoes`,
+			Price:       money.FromUnits(1),
+			Category:    "Foot & <b>wear</b>",
+			Description: "Use <mark>only</mark> with shoes & socks",
+		})
+		if err != nil {
+			t.Fatalf("Create failed: %v", err)
+		}
+
+		response, err := store.Search(ctx, dto.ProductSearchDTO{Query: "shoes", Page: 1, PageSize: 10})
+		if err != nil {
+			t.Fatalf("Search failed: %v", err)
+		}
+		if len(response.Results) != 1 {
+			t.Fatalf("Expected 1 result, got %d", len(response.Results))
+		}
+		result := response.Results[0]
+		if want := "&lt;script&gt;alert(&#34;x&#34;)&lt;/script&gt; <mark>Shoes</mark>"; result.NameHighlight != want {
+			t.Errorf("Expected name highlight %q, got %q", want, result.NameHighlight)
+		}
+		if want := "Foot &amp; &lt;b&gt;wear&lt;/b&gt;"; result.CategoryHighlight != want {
+			t.Errorf("Expected category highlight %q, got %q", want, result.CategoryHighlight)
+		}
+		if want := "Use &lt;mark&gt;only&lt;/mark&gt; with <mark>shoes</mark> &amp; socks"; result.DescriptionSnippet != want {
+			t.Errorf("Expected description snippet %q, got %q", want, result.DescriptionSnippet)
+		}
+	})
+
+	t.Run("Transactions", func(t *testing.T) {
+		store := newStore(t)
+		errRollback := errors.New("roll back")
+
+		err := store.WithTx(ctx, func(tx repositories.ProductStore) error {
+			createTestProduct(t, tx, "Discarded", 1, "", 1)
+			return errRollback
+		})
+		if !errors.Is(err, errRollback) {
+			t.Fatalf("Expected the error from fn, got %v", err)
+		}
+		if count := countProducts(t, store); count != 0 {
+			t.Errorf("Expected the rolled back product to be gone, got %d products", count)
+		}
+
+		err = store.WithTx(ctx, func(tx repositories.ProductStore) error {
+			product := createTestProduct(t, tx, "Kept", 1, "", 1)
+			return tx.WithTx(ctx, func(nested repositories.ProductStore) error {
+				stock := 9
+				_, err := nested.Update(ctx, product.ID, dto.UpdateProductDTO{Version: 1, Stock: &stock})
+				return err
+			})
+		})
+		if err != nil {
+			t.Fatalf("WithTx failed: %v", err)
+		}
+		response, err := store.GetAll(ctx, dto.PaginationDTO{Page: 1, PageSize: 10})
+		if err != nil {
+			t.Fatalf("GetAll failed: %v", err)
+		}
+		if response.TotalCount != 1 || response.Products[0].Stock != 9 || response.Products[0].Version != 2 {
+			t.Errorf("Unexpected products after commit: %+v", response.Products)
+		}
+	})
+
+	t.Run("Cancellation", func(t *testing.T) {
+		store := newStore(t)
+		product := createTestProduct(t, store, "Lamp", 20, "Home", 5)
+
+		cancelled, cancel := context.WithCancel(ctx)
+		cancel()
+		if _, err := store.GetByID(cancelled, product.ID); !errors.Is(err, context.Canceled) {
+			t.Errorf("Expected GetByID to be cancelled, got %v", err)
+		}
+		if _, err := store.GetAll(cancelled, dto.PaginationDTO{Page: 1, PageSize: 10}); !errors.Is(err, context.Canceled) {
+			t.Errorf("Expected GetAll to be cancelled, got %v", err)
+		}
+		if err := store.Delete(cancelled, product.ID, 1); !errors.Is(err, context.Canceled) {
+			t.Errorf("Expected Delete to be cancelled, got %v", err)
+		}
+
+		txCtx, cancelTx := context.WithCancel(ctx)
+		err := store.WithTx(txCtx, func(tx repositories.ProductStore) error {
+			stock := 9
+			if _, err := tx.Update(txCtx, product.ID, dto.UpdateProductDTO{Version: 1, Stock: &stock}); err != nil {
+				return err
+			}
+			cancelTx()
+			_, err := tx.GetByID(txCtx, product.ID)
+			return err
+		})
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("Expected the transaction to be cancelled, got %v", err)
+		}
+
+		stored, err := store.GetByID(ctx, product.ID)
+		if err != nil {
+			t.Fatalf("GetByID failed: %v", err)
+		}
+		if stored.Stock != 5 || stored.Version != 1 {
+			t.Errorf("Expected the cancelled transaction to be rolled back, got %+v", stored)
+		}
+	})
+
+	t.Run("Bulk apply", func(t *testing.T) {
+		store := newStore(t)
+		first := createTestProduct(t, store, "First", 10, "Old", 5)
+		second := createTestProduct(t, store, "Second", 10, "Old", 1)
+
+		result, err := store.BulkApply(ctx, dto.BulkOperationDTO{Operation: dto.BulkAdjustStock, ProductIDs: []int{first.ID, second.ID}, StockDelta: -2})
+		if err != nil {
+			t.Fatalf("BulkApply failed: %v", err)
+		}
+		if result.Committed || result.ErrorCount != 1 || result.SuccessCount != 1 {
+			t.Errorf("Expected a rolled back operation with one failure, got %+v", result)
+		}
+		if product, _ := store.GetByID(ctx, first.ID); product == nil || product.Stock != 5 {
+			t.Errorf("Expected the stock change to be rolled back, got %+v", product)
+		}
+
+		result, err = store.BulkApply(ctx, dto.BulkOperationDTO{Operation: dto.BulkSetCategory, Category: "New", Filters: &dto.ProductFilterDTO{Categories: []string{"Old"}}})
+		if err != nil {
+			t.Fatalf("BulkApply failed: %v", err)
+		}
+		if !result.Committed || result.SuccessCount != 2 {
+			t.Errorf("Expected both products updated, got %+v", result)
+		}
+		product, err := store.GetByID(ctx, second.ID)
+		if err != nil {
+			t.Fatalf("GetByID failed: %v", err)
+		}
+		if product.Category == nil || *product.Category != "New" || product.Version != 2 {
+			t.Errorf("Unexpected product after bulk update: %+v", product)
+		}
+	})
+
+	t.Run("Import batches", func(t *testing.T) {
+		store := newStore(t)
+		before := createTestProduct(t, store, "Lamp", 19.9, "Home", 3)
+		batch, err := store.CreateImportBatch(ctx, models.ImportBatch{
+			Source:       "csv",
+			Mode:         "upsert",
+			CreatedCount: 1,
+			UpdatedCount: 1,
+			Items: []models.ImportBatchItem{
+				{ProductID: 7, Version: 1},
+				{ProductID: before.ID, Before: before, Version: 2},
+			},
+		})
+		if err != nil {
+			t.Fatalf("CreateImportBatch failed: %v", err)
+		}
+		if batch.ID == 0 || batch.CreatedAt == "" || batch.RolledBackAt != nil {
+			t.Errorf("Unexpected new batch: %+v", batch)
+		}
+		if _, err := store.CreateImportBatch(ctx, models.ImportBatch{Source: "xlsx", Mode: "insert"}); err != nil {
+			t.Fatalf("CreateImportBatch failed: %v", err)
+		}
+
+		stored, err := store.GetImportBatch(ctx, batch.ID)
+		if err != nil {
+			t.Fatalf("GetImportBatch failed: %v", err)
+		}
+		if len(stored.Items) != 2 || stored.Items[0].Before != nil || stored.Items[0].Version != 1 {
+			t.Fatalf("Unexpected batch items: %+v", stored.Items)
+		}
+		if restored := stored.Items[1].Before; restored == nil || restored.Name != "Lamp" || restored.Price != 1990 || value(restored.Category) != "Home" {
+			t.Errorf("Expected the product before the import, got %+v", restored)
+		}
+
+		// A product already in the batch keeps its earlier state and only
+		// takes the new version.
+		if err := store.AddImportBatchItems(ctx, batch.ID, []models.ImportBatchItem{
+			{ProductID: before.ID, Before: &models.Product{Name: "Later"}, Version: 3},
+			{ProductID: 8, Version: 1},
+		}); err != nil {
+			t.Fatalf("AddImportBatchItems failed: %v", err)
+		}
+		stored, err = store.GetImportBatch(ctx, batch.ID)
+		if err != nil {
+			t.Fatalf("GetImportBatch failed: %v", err)
+		}
+		if len(stored.Items) != 3 || stored.Items[1].Version != 3 || stored.Items[1].Before.Name != "Lamp" || stored.Items[2].ProductID != 8 {
+			t.Errorf("Unexpected batch items after adding: %+v", stored.Items)
+		}
+		if stored.CreatedCount != 2 || stored.UpdatedCount != 1 {
+			t.Errorf("Expected 2 created and 1 updated products, got %+v", stored)
+		}
+		if err := store.AddImportBatchItems(ctx, 99, []models.ImportBatchItem{{ProductID: 9, Version: 1}}); apperrors.CodeOf(err) != apperrors.CodeNotFound {
+			t.Errorf("Expected an unknown batch not to be found, got %v", err)
+		}
+
+		batches, err := store.ListImportBatches(ctx, 10)
+		if err != nil {
+			t.Fatalf("ListImportBatches failed: %v", err)
+		}
+		if len(batches) != 2 || batches[0].Source != "xlsx" || batches[1].ID != batch.ID || len(batches[1].Items) != 0 {
+			t.Errorf("Expected both batches newest first without items, got %+v", batches)
+		}
+		if batches, _ := store.ListImportBatches(ctx, 1); len(batches) != 1 {
+			t.Errorf("Expected the limit to apply, got %d batches", len(batches))
+		}
+
+		if err := store.MarkImportBatchRolledBack(ctx, batch.ID); err != nil {
+			t.Fatalf("MarkImportBatchRolledBack failed: %v", err)
+		}
+		if stored, _ := store.GetImportBatch(ctx, batch.ID); stored == nil || stored.RolledBackAt == nil {
+			t.Errorf("Expected the batch to be rolled back, got %+v", stored)
+		}
+		var conflict *apperrors.ConflictError
+		if err := store.MarkImportBatchRolledBack(ctx, batch.ID); !errors.As(err, &conflict) {
+			t.Errorf("Expected a conflict for a second rollback, got %v", err)
+		}
+		var notFound *apperrors.NotFoundError
+		if err := store.MarkImportBatchRolledBack(ctx, 99); !errors.As(err, &notFound) {
+			t.Errorf("Expected an unknown batch not to be found, got %v", err)
+		}
+		if _, err := store.GetImportBatch(ctx, 99); !errors.As(err, &notFound) {
+			t.Errorf("Expected an unknown batch not to be found, got %v", err)
+		}
+	})
+}
+
+func createTestProduct(t *testing.T, store repositories.ProductStore, name string, price float64, category string, stock int) *models.Product {
+	t.Helper()
+	product, err := store.Create(context.Background(), dto.CreateProductDTO{Name: name, Price: money.FromFloat(price, money.RoundNearest), Category: category, Stock: stock})
+	if err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+	return product
+}
+
+func countProducts(t *testing.T, store repositories.ProductStore) int {
+	t.Helper()
+	response, err := store.GetAll(context.Background(), dto.PaginationDTO{Page: 1, PageSize: 1})
+	if err != nil {
+		t.Fatalf("GetAll failed: %v", err)
+	}
+	return response.TotalCount
+}
+
+func stringPtr(value string) *string {
+	return &value
+}
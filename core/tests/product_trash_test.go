@@ -0,0 +1,99 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"product-management-app/core/dto"
+	apperrors "product-management-app/core/errors"
+	"product-management-app/core/money"
+)
+
+func TestProductRepositoryTrash(t *testing.T) {
+	ctx := context.Background()
+	repo := newTestProductRepository(t,
+		dto.CreateProductDTO{Name: "Kept", Price: money.FromUnits(1), Stock: 1},
+		dto.CreateProductDTO{Name: "Trashed", Price: money.FromUnits(1), Stock: 1},
+	)
+	const kept, trashed = 1, 2
+
+	var conflict *apperrors.ConflictError
+	if err := repo.Delete(ctx, trashed, 2); !errors.As(err, &conflict) {
+		t.Errorf("Expected a conflict for a stale delete, got %v", err)
+	}
+	if err := repo.Delete(ctx, trashed, 1); err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+	if _, err := repo.GetByID(ctx, trashed); err == nil {
+		t.Errorf("Expected a trashed product to be hidden")
+	}
+	var notFound *apperrors.NotFoundError
+	if err := repo.Delete(ctx, trashed, 2); !errors.As(err, &notFound) {
+		t.Errorf("Expected a not found error deleting a trashed product, got %v", err)
+	}
+
+	listing, err := repo.GetAll(ctx, dto.PaginationDTO{Page: 1, PageSize: 10})
+	if err != nil {
+		t.Fatalf("GetAll failed: %v", err)
+	}
+	if names := productNames(listing.Products); len(names) != 1 || names[0] != "Kept" {
+		t.Errorf("Expected only the kept product to be listed, got %v", names)
+	}
+	trash, err := repo.GetTrash(ctx, dto.PaginationDTO{Page: 1, PageSize: 10})
+	if err != nil {
+		t.Fatalf("GetTrash failed: %v", err)
+	}
+	if trash.TotalCount != 1 || trash.Products[0].ID != trashed || trash.Products[0].DeletedAt == nil {
+		t.Fatalf("Unexpected trash: %+v", trash)
+	}
+
+	if err := repo.Restore(ctx, kept, 1); err == nil {
+		t.Errorf("Expected an error restoring a live product")
+	}
+	if err := repo.Purge(ctx, kept); err == nil {
+		t.Errorf("Expected an error purging a live product")
+	}
+	if err := repo.Restore(ctx, trashed, 1); !errors.As(err, &conflict) {
+		t.Errorf("Expected a conflict for a stale restore, got %v", err)
+	}
+	if err := repo.Restore(ctx, trashed, 2); err != nil {
+		t.Fatalf("Restore failed: %v", err)
+	}
+	restored, err := repo.GetByID(ctx, trashed)
+	if err != nil {
+		t.Fatalf("GetByID after restore failed: %v", err)
+	}
+	if restored.Version != 3 || restored.DeletedAt != nil {
+		t.Errorf("Unexpected product after restore: %+v", restored)
+	}
+
+	if err := repo.Delete(ctx, trashed, 3); err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+	if err := repo.Delete(ctx, kept, 1); err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+	if err := repo.Purge(ctx, trashed); err != nil {
+		t.Fatalf("Purge failed: %v", err)
+	}
+	if err := repo.Restore(ctx, trashed, 4); err == nil {
+		t.Errorf("Expected an error restoring a purged product")
+	}
+	if _, err := repo.PurgeOlderThan(ctx, -1); err == nil {
+		t.Errorf("Expected an error for a negative retention")
+	}
+	if purged, err := repo.PurgeOlderThan(ctx, 1); err != nil || purged != 0 {
+		t.Errorf("Expected a product trashed today to be kept, got %d, %v", purged, err)
+	}
+	purged, err := repo.PurgeOlderThan(ctx, 0)
+	if err != nil {
+		t.Fatalf("PurgeOlderThan failed: %v", err)
+	}
+	if purged != 1 {
+		t.Errorf("Expected 1 product purged, got %d", purged)
+	}
+	if trash, err := repo.GetTrash(ctx, dto.PaginationDTO{Page: 1, PageSize: 10}); err != nil || trash.TotalCount != 0 {
+		t.Errorf("Expected an empty trash, got %+v, %v", trash, err)
+	}
+}
@@ -0,0 +1,168 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"product-management-app/core/config"
+	"product-management-app/core/dto"
+	apperrors "product-management-app/core/errors"
+	"product-management-app/core/money"
+	"product-management-app/core/repositories"
+	"product-management-app/core/validation"
+)
+
+func TestProductValidatorValidateCreate(t *testing.T) {
+	validator := validation.NewProductValidator(config.ValidationConfig{
+		NameMinLength: 3,
+		MaxPrice:      money.FromUnits(1000),
+		MaxStock:      500,
+		Categories:    []string{"Electronics", "Books"},
+	})
+
+	tests := []struct {
+		name        string
+		product     dto.CreateProductDTO
+		expectError bool
+		fields      []string
+	}{
+		{name: "Valid product", product: dto.CreateProductDTO{Name: "Laptop", Price: money.FromUnits(999), Stock: 5, Category: "Electronics", ImageURL: "https://example.com/laptop.png"}},
+		{name: "Category is case-insensitive", product: dto.CreateProductDTO{Name: "Novel", Price: money.FromUnits(10), Category: "books"}},
+		{name: "Empty category allowed", product: dto.CreateProductDTO{Name: "Widget", Price: money.FromUnits(1)}},
+		{name: "Missing name", product: dto.CreateProductDTO{Name: "  ", Price: money.FromUnits(1)}, expectError: true, fields: []string{"name"}},
+		{name: "Name too short", product: dto.CreateProductDTO{Name: "TV", Price: money.FromUnits(1)}, expectError: true, fields: []string{"name"}},
+		{name: "Name too long", product: dto.CreateProductDTO{Name: strings.Repeat("a", 201), Price: money.FromUnits(1)}, expectError: true, fields: []string{"name"}},
+		{name: "Negative price", product: dto.CreateProductDTO{Name: "Laptop", Price: money.FromUnits(-1)}, expectError: true, fields: []string{"price"}},
+		{name: "Price above maximum", product: dto.CreateProductDTO{Name: "Laptop", Price: money.FromMinorUnits(100001)}, expectError: true, fields: []string{"price"}},
+		{name: "Stock above maximum", product: dto.CreateProductDTO{Name: "Laptop", Price: money.FromUnits(1), Stock: 501}, expectError: true, fields: []string{"stock"}},
+		{name: "Category not allowed", product: dto.CreateProductDTO{Name: "Hammer", Price: money.FromUnits(1), Category: "Tools"}, expectError: true, fields: []string{"category"}},
+		{name: "Relative image URL", product: dto.CreateProductDTO{Name: "Laptop", Price: money.FromUnits(1), ImageURL: "images/laptop.png"}, expectError: true, fields: []string{"imageUrl"}},
+		{name: "Unsupported image scheme", product: dto.CreateProductDTO{Name: "Laptop", Price: money.FromUnits(1), ImageURL: "ftp://example.com/laptop.png"}, expectError: true, fields: []string{"imageUrl"}},
+		{name: "Every problem reported", product: dto.CreateProductDTO{Name: "", Price: money.FromUnits(-5), Stock: -1, Description: strings.Repeat("d", 2001)}, expectError: true, fields: []string{"name", "price", "stock", "description"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validator.ValidateCreate(tt.product)
+			if tt.expectError && err == nil {
+				t.Fatalf("Expected error but got none")
+			}
+			if !tt.expectError && err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if err == nil {
+				return
+			}
+
+			var validationErr *apperrors.ValidationError
+			if !errors.As(err, &validationErr) {
+				t.Fatalf("Expected a validation error, got %T", err)
+			}
+			if len(validationErr.Fields) != len(tt.fields) {
+				t.Fatalf("Expected fields %v, got %+v", tt.fields, validationErr.Fields)
+			}
+			for i, field := range tt.fields {
+				if validationErr.Fields[i].Field != field {
+					t.Errorf("Expected field %q at %d, got %q", field, i, validationErr.Fields[i].Field)
+				}
+			}
+		})
+	}
+}
+
+func TestProductValidatorValidateUpdate(t *testing.T) {
+	validator := validation.NewProductValidator(config.ValidationConfig{Categories: []string{"Electronics"}})
+	name := "Laptop"
+	empty := ""
+	price := money.FromUnits(-1)
+	category := "Tools"
+	imageURL := "not a url"
+
+	tests := []struct {
+		name        string
+		update      dto.UpdateProductDTO
+		expectError bool
+	}{
+		{name: "Valid name", update: dto.UpdateProductDTO{Version: 1, Name: &name}},
+		{name: "Clear category", update: dto.UpdateProductDTO{Version: 1, Clear: []string{dto.FieldCategory}}},
+		{name: "Missing version", update: dto.UpdateProductDTO{Name: &name}, expectError: true},
+		{name: "Empty name", update: dto.UpdateProductDTO{Version: 1, Name: &empty}, expectError: true},
+		{name: "Negative price", update: dto.UpdateProductDTO{Version: 1, Price: &price}, expectError: true},
+		{name: "Category not allowed", update: dto.UpdateProductDTO{Version: 1, Category: &category}, expectError: true},
+		{name: "Invalid image URL", update: dto.UpdateProductDTO{Version: 1, ImageURL: &imageURL}, expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validator.ValidateUpdate(tt.update)
+			if tt.expectError && err == nil {
+				t.Errorf("Expected error but got none")
+			}
+			if !tt.expectError && err != nil {
+				t.Errorf("Unexpected error: %v", err)
+			}
+			if err != nil && apperrors.CodeOf(err) != apperrors.CodeValidation {
+				t.Errorf("Expected a validation error, got %v", err)
+			}
+		})
+	}
+}
+
+func TestValidationConfigApplyDefaults(t *testing.T) {
+	rules := config.ValidationConfig{NameMinLength: 10, NameMaxLength: 5, MinPrice: money.FromUnits(-1), MinStock: 10, MaxStock: 5}
+	if err := rules.ApplyDefaults(); err == nil {
+		t.Error("Expected contradictory rules to be reported")
+	}
+	if rules.NameMinLength != 10 || rules.NameMaxLength != config.DefaultNameMaxLength {
+		t.Errorf("Expected name lengths 10..%d, got %d..%d", config.DefaultNameMaxLength, rules.NameMinLength, rules.NameMaxLength)
+	}
+	if rules.MinPrice != 0 || rules.MaxStock != 0 {
+		t.Errorf("Expected invalid bounds to be reset, got price >= %v and stock <= %d", rules.MinPrice, rules.MaxStock)
+	}
+}
+
+func TestValidatingStoreGuardsEveryWritePath(t *testing.T) {
+	ctx := context.Background()
+	store := validation.NewValidatingStore(
+		repositories.NewMemoryProductStore(),
+		validation.NewProductValidator(config.ValidationConfig{MaxPrice: money.FromUnits(100)}),
+	)
+
+	if _, err := store.Create(ctx, dto.CreateProductDTO{Name: "Too expensive", Price: money.FromUnits(150)}); apperrors.CodeOf(err) != apperrors.CodeValidation {
+		t.Fatalf("Expected create to be rejected, got %v", err)
+	}
+	cheap, err := store.Create(ctx, dto.CreateProductDTO{Name: "Cheap", Price: money.FromUnits(50)})
+	if err != nil {
+		t.Fatalf("Failed to create product: %v", err)
+	}
+	pricey, err := store.Create(ctx, dto.CreateProductDTO{Name: "Pricey", Price: money.FromUnits(90)})
+	if err != nil {
+		t.Fatalf("Failed to create product: %v", err)
+	}
+
+	price := money.FromUnits(101)
+	if _, err := store.Update(ctx, cheap.ID, dto.UpdateProductDTO{Version: cheap.Version, Price: &price}); apperrors.CodeOf(err) != apperrors.CodeValidation {
+		t.Errorf("Expected update to be rejected, got %v", err)
+	}
+
+	result, err := store.BulkApply(ctx, dto.BulkOperationDTO{
+		Operation:       dto.BulkAdjustPrice,
+		ProductIDs:      []int{cheap.ID, pricey.ID},
+		PriceAdjustment: &dto.PriceAdjustmentDTO{Type: dto.AdjustByPercent, Amount: 20},
+	})
+	if err != nil {
+		t.Fatalf("Bulk operation failed: %v", err)
+	}
+	if result.Committed || result.ErrorCount != 1 {
+		t.Errorf("Expected the bulk operation to fail for one product and roll back, got %+v", result)
+	}
+	unchanged, err := store.GetByID(ctx, cheap.ID)
+	if err != nil {
+		t.Fatalf("Failed to fetch product: %v", err)
+	}
+	if unchanged.Price != money.FromUnits(50) {
+		t.Errorf("Expected the rolled back price to stay 50, got %v", unchanged.Price)
+	}
+}
@@ -0,0 +1,65 @@
+package test
+
+import (
+	"path/filepath"
+	"testing"
+
+	"product-management-app/core/sqlite"
+)
+
+func TestSQLiteOpenAppliesOptions(t *testing.T) {
+	// Characters that are URI syntax must not end up truncating the path.
+	path := filepath.Join(t.TempDir(), "catalog #1?.db")
+	db, err := sqlite.Open(path, sqlite.Options{
+		JournalMode:   "WAL",
+		Synchronous:   "NORMAL",
+		BusyTimeoutMs: 1234,
+		ForeignKeys:   true,
+		ImmediateTx:   true,
+	})
+	if err != nil {
+		t.Fatalf("Failed to open database: %v", err)
+	}
+	defer func() {
+		_ = db.Close()
+	}()
+	if _, err := db.Exec("CREATE TABLE items (id INTEGER PRIMARY KEY)"); err != nil {
+		t.Fatalf("Failed to create table: %v", err)
+	}
+
+	tests := []struct {
+		pragma   string
+		expected string
+	}{
+		{pragma: "journal_mode", expected: "wal"},
+		{pragma: "synchronous", expected: "1"},
+		{pragma: "busy_timeout", expected: "1234"},
+		{pragma: "foreign_keys", expected: "1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.pragma, func(t *testing.T) {
+			var value string
+			if err := db.QueryRow("PRAGMA " + tt.pragma).Scan(&value); err != nil {
+				t.Fatalf("Failed to read PRAGMA %s: %v", tt.pragma, err)
+			}
+			if value != tt.expected {
+				t.Errorf("Expected %s = %s, got %s (driver %s)", tt.pragma, tt.expected, value, sqlite.Driver)
+			}
+		})
+	}
+
+	readOnly, err := sqlite.Open(path, sqlite.Options{ReadOnly: true})
+	if err != nil {
+		t.Fatalf("Failed to open database read-only: %v", err)
+	}
+	defer func() {
+		_ = readOnly.Close()
+	}()
+	var count int
+	if err := readOnly.QueryRow("SELECT COUNT(*) FROM items").Scan(&count); err != nil {
+		t.Fatalf("Failed to read the same file read-only: %v", err)
+	}
+	if _, err := readOnly.Exec("INSERT INTO items DEFAULT VALUES"); err == nil {
+		t.Errorf("Expected a write to a read-only database to fail")
+	}
+}
@@ -0,0 +1,41 @@
+package test
+
+import (
+	"testing"
+
+	"product-management-app/core/dto"
+)
+
+func TestUpdateProductValidate(t *testing.T) {
+	name := "Renamed"
+	category := "Tools"
+
+	tests := []struct {
+		name        string
+		update      dto.UpdateProductDTO
+		expectError bool
+	}{
+		{name: "Empty update", update: dto.UpdateProductDTO{Version: 1}, expectError: true},
+		{name: "Missing version", update: dto.UpdateProductDTO{Name: &name}, expectError: true},
+		{name: "Name only", update: dto.UpdateProductDTO{Version: 1, Name: &name}},
+		{name: "Clear description", update: dto.UpdateProductDTO{Version: 3, Clear: []string{dto.FieldDescription}}},
+		{name: "Clear unknown field", update: dto.UpdateProductDTO{Version: 1, Clear: []string{"name"}}, expectError: true},
+		{
+			name:        "Set and clear same field",
+			update:      dto.UpdateProductDTO{Version: 1, Category: &category, Clear: []string{dto.FieldCategory}},
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.update.Validate()
+			if tt.expectError && err == nil {
+				t.Errorf("Expected error but got none")
+			}
+			if !tt.expectError && err != nil {
+				t.Errorf("Unexpected error: %v", err)
+			}
+		})
+	}
+}
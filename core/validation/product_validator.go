@@ -0,0 +1,163 @@
+// Package validation holds the rules products must satisfy before they are
+// written, shared by every write path: create, update, bulk operations and
+// imports.
+package validation
+
+import (
+	"net/url"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"product-management-app/core/config"
+	"product-management-app/core/dto"
+	apperrors "product-management-app/core/errors"
+	"product-management-app/core/money"
+)
+
+// ProductValidator checks product fields against configurable rules. Every
+// problem is reported as a field of one *apperrors.ValidationError, so
+// callers see all of them at once.
+type ProductValidator struct {
+	rules config.ValidationConfig
+	// categories holds the lower-cased whitelist, nil when any category is
+	// allowed.
+	categories map[string]bool
+}
+
+// NewProductValidator returns a validator enforcing rules. Unset rules take
+// their defaults.
+func NewProductValidator(rules config.ValidationConfig) *ProductValidator {
+	_ = rules.ApplyDefaults()
+	v := &ProductValidator{rules: rules}
+	if len(rules.Categories) > 0 {
+		v.categories = make(map[string]bool, len(rules.Categories))
+		for _, category := range rules.Categories {
+			v.categories[strings.ToLower(strings.TrimSpace(category))] = true
+		}
+	}
+	return v
+}
+
+// Rules returns the rules the validator enforces, with defaults applied.
+func (v *ProductValidator) Rules() config.ValidationConfig {
+	return v.rules
+}
+
+// ValidateCreate checks every field of a new product.
+func (v *ProductValidator) ValidateCreate(product dto.CreateProductDTO) error {
+	errs := new(apperrors.ValidationError)
+	v.checkName(errs, product.Name)
+	v.checkSKU(errs, product.SKU)
+	v.checkPrice(errs, product.Price)
+	v.checkStock(errs, product.Stock)
+	v.checkCategory(errs, product.Category)
+	v.checkDescription(errs, product.Description)
+	v.checkImageURL(errs, product.ImageURL)
+	return errs.OrNil()
+}
+
+// ValidateUpdate checks the shape of the update and every field it sets.
+func (v *ProductValidator) ValidateUpdate(update dto.UpdateProductDTO) error {
+	if err := update.Validate(); err != nil {
+		return err
+	}
+
+	errs := new(apperrors.ValidationError)
+	if update.Name != nil {
+		v.checkName(errs, *update.Name)
+	}
+	if update.SKU != nil {
+		v.checkSKU(errs, *update.SKU)
+	}
+	if update.Price != nil {
+		v.checkPrice(errs, *update.Price)
+	}
+	if update.Stock != nil {
+		v.checkStock(errs, *update.Stock)
+	}
+	if update.Category != nil {
+		v.checkCategory(errs, *update.Category)
+	}
+	if update.Description != nil {
+		v.checkDescription(errs, *update.Description)
+	}
+	if update.ImageURL != nil {
+		v.checkImageURL(errs, *update.ImageURL)
+	}
+	return errs.OrNil()
+}
+
+func (v *ProductValidator) checkName(errs *apperrors.ValidationError, name string) {
+	length := utf8.RuneCountInString(strings.TrimSpace(name))
+	switch {
+	case length == 0:
+		errs.Add("name", "name is required")
+	case length < v.rules.NameMinLength:
+		errs.Add("name", "name must be at least %d characters long", v.rules.NameMinLength)
+	case length > v.rules.NameMaxLength:
+		errs.Add("name", "name must be at most %d characters long", v.rules.NameMaxLength)
+	}
+}
+
+// maxSKULength bounds SKUs, which are codes rather than free text.
+const maxSKULength = 64
+
+// checkSKU accepts an empty SKU or a code of at most maxSKULength characters
+// without spaces.
+func (v *ProductValidator) checkSKU(errs *apperrors.ValidationError, sku string) {
+	switch {
+	case strings.IndexFunc(sku, unicode.IsSpace) >= 0:
+		errs.Add("sku", "SKU must not contain spaces")
+	case utf8.RuneCountInString(sku) > maxSKULength:
+		errs.Add("sku", "SKU must be at most %d characters long", maxSKULength)
+	}
+}
+
+func (v *ProductValidator) checkPrice(errs *apperrors.ValidationError, price money.Money) {
+	switch {
+	case price < v.rules.MinPrice:
+		errs.Add("price", "price must be at least %s", v.rules.MinPrice)
+	case v.rules.MaxPrice > 0 && price > v.rules.MaxPrice:
+		errs.Add("price", "price must be at most %s", v.rules.MaxPrice)
+	}
+}
+
+func (v *ProductValidator) checkStock(errs *apperrors.ValidationError, stock int) {
+	switch {
+	case stock < v.rules.MinStock:
+		errs.Add("stock", "stock must be at least %d", v.rules.MinStock)
+	case v.rules.MaxStock > 0 && stock > v.rules.MaxStock:
+		errs.Add("stock", "stock must be at most %d", v.rules.MaxStock)
+	}
+}
+
+// checkCategory enforces the whitelist. An empty category is always allowed,
+// since the category is optional.
+func (v *ProductValidator) checkCategory(errs *apperrors.ValidationError, category string) {
+	category = strings.TrimSpace(category)
+	if category == "" || v.categories == nil {
+		return
+	}
+	if !v.categories[strings.ToLower(category)] {
+		errs.Add("category", "category %q is not allowed: must be one of %s", category, strings.Join(v.rules.Categories, ", "))
+	}
+}
+
+func (v *ProductValidator) checkDescription(errs *apperrors.ValidationError, description string) {
+	if utf8.RuneCountInString(description) > v.rules.DescriptionMaxLength {
+		errs.Add("description", "description must be at most %d characters long", v.rules.DescriptionMaxLength)
+	}
+}
+
+// checkImageURL accepts an empty URL or an absolute http or https URL.
+func (v *ProductValidator) checkImageURL(errs *apperrors.ValidationError, imageURL string) {
+	imageURL = strings.TrimSpace(imageURL)
+	if imageURL == "" {
+		return
+	}
+	parsed, err := url.Parse(imageURL)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		errs.Add("imageUrl", "image URL must be an absolute http or https URL")
+	}
+}
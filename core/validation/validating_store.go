@@ -0,0 +1,54 @@
+package validation
+
+import (
+	"context"
+
+	"product-management-app/core/dto"
+	"product-management-app/core/models"
+	"product-management-app/core/repositories"
+)
+
+// ValidatingStore wraps a ProductStore so that every product it creates or
+// updates passes the validator first. Transactions and bulk operations run
+// through the wrapper too, so a bulk change that would leave a product
+// invalid fails for that product and rolls the operation back.
+type ValidatingStore struct {
+	repositories.ProductStore
+	validator *ProductValidator
+}
+
+var _ repositories.ProductStore = (*ValidatingStore)(nil)
+
+// NewValidatingStore returns store guarded by validator.
+func NewValidatingStore(store repositories.ProductStore, validator *ProductValidator) *ValidatingStore {
+	return &ValidatingStore{ProductStore: store, validator: validator}
+}
+
+// Create validates the product before creating it.
+func (s *ValidatingStore) Create(ctx context.Context, createProductDTO dto.CreateProductDTO) (*models.Product, error) {
+	if err := s.validator.ValidateCreate(createProductDTO); err != nil {
+		return nil, err
+	}
+	return s.ProductStore.Create(ctx, createProductDTO)
+}
+
+// Update validates the fields the update sets before applying it.
+func (s *ValidatingStore) Update(ctx context.Context, id int, update dto.UpdateProductDTO) (*models.Product, error) {
+	if err := s.validator.ValidateUpdate(update); err != nil {
+		return nil, err
+	}
+	return s.ProductStore.Update(ctx, id, update)
+}
+
+// BulkApply applies op through the wrapper, so each product's update is
+// validated.
+func (s *ValidatingStore) BulkApply(ctx context.Context, op dto.BulkOperationDTO) (*dto.BulkOperationResult, error) {
+	return repositories.ApplyBulkOperation(ctx, s, op)
+}
+
+// WithTx hands fn the transaction wrapped in the same validator.
+func (s *ValidatingStore) WithTx(ctx context.Context, fn func(tx repositories.ProductStore) error) error {
+	return s.ProductStore.WithTx(ctx, func(tx repositories.ProductStore) error {
+		return fn(NewValidatingStore(tx, s.validator))
+	})
+}
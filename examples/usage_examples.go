@@ -1,11 +1,12 @@
 package main
 
 import (
-	"context"
 	"fmt"
 	"log"
+	"log/slog"
 
 	"product-management-app/core/dto"
+	"product-management-app/core/money"
 	service "product-management-app/core/services"
 )
 
@@ -31,10 +32,13 @@ func ExampleUsage() {
 	fmt.Println()
 
 	fmt.Println("4. IMPORT PRODUCTS FROM CSV:")
-	fmt.Println("Frontend reads file and calls: window.go.main.App.ImportProductsFromCSV(csvData)")
+	fmt.Println("Frontend calls: window.go.main.App.ImportProductsFromCSV({mode: \"upsert\", matchBy: \"sku\"})")
 	fmt.Println("Returns result with successes, errors and details:")
 	fmt.Println(`{
   "successCount": 2,
+  "createdCount": 1,
+  "updatedCount": 1,
+  "skippedCount": 0,
   "errorCount": 1,
   "errors": [
     {
@@ -45,7 +49,7 @@ func ExampleUsage() {
     }
   ],
   "importedItems": [
-    // successfully created products
+    // successfully created and updated products
   ]
 }`)
 	fmt.Println()
@@ -66,7 +70,7 @@ Wireless Mouse,89.90,Accessories,100,Optical wireless mouse,`)
 }
 
 func demonstrateCurrencyService() {
-	currencyService := service.NewCurrencyService(context.TODO())
+	currencyService := service.NewCurrencyService(slog.Default())
 
 	fmt.Println("\n1. Supported Currencies:")
 	supportedCurrencies := currencyService.GetSupportedCurrencies()
@@ -77,20 +81,20 @@ func demonstrateCurrencyService() {
 	fmt.Println("\n2. Conversion Examples:")
 
 	conversions := []dto.CurrencyConversionRequest{
-		{Amount: 100, FromCurrency: "USD", ToCurrency: "BRL"},
-		{Amount: 50, FromCurrency: "EUR", ToCurrency: "USD"},
-		{Amount: 1000, FromCurrency: "BRL", ToCurrency: "EUR"},
+		{Amount: money.FromUnits(100), FromCurrency: "USD", ToCurrency: "BRL"},
+		{Amount: money.FromUnits(50), FromCurrency: "EUR", ToCurrency: "USD"},
+		{Amount: money.FromUnits(1000), FromCurrency: "BRL", ToCurrency: "EUR"},
 	}
 
 	for _, conversion := range conversions {
 		result, err := currencyService.ConvertCurrency(conversion)
 		if err != nil {
-			log.Printf("   Error converting %.2f %s to %s: %v\n",
+			log.Printf("   Error converting %s %s to %s: %v\n",
 				conversion.Amount, conversion.FromCurrency, conversion.ToCurrency, err)
 			continue
 		}
 
-		fmt.Printf("   %.2f %s = %.2f %s (Rate: %.6f)\n",
+		fmt.Printf("   %s %s = %s %s (Rate: %.6f)\n",
 			result.Amount, result.FromCurrency,
 			result.ConvertedAmount, result.ToCurrency,
 			result.ExchangeRate)
@@ -98,12 +102,12 @@ func demonstrateCurrencyService() {
 
 	fmt.Println("\n3. Same Currency Conversion (should return rate 1.0):")
 	sameCurrencyTest, err := currencyService.ConvertCurrency(dto.CurrencyConversionRequest{
-		Amount: 100, FromCurrency: "USD", ToCurrency: "USD",
+		Amount: money.FromUnits(100), FromCurrency: "USD", ToCurrency: "USD",
 	})
 	if err != nil {
 		log.Printf("   Error: %v\n", err)
 	} else {
-		fmt.Printf("   %.2f %s = %.2f %s (Rate: %.1f)\n",
+		fmt.Printf("   %s %s = %s %s (Rate: %.1f)\n",
 			sameCurrencyTest.Amount, sameCurrencyTest.FromCurrency,
 			sameCurrencyTest.ConvertedAmount, sameCurrencyTest.ToCurrency,
 			sameCurrencyTest.ExchangeRate)
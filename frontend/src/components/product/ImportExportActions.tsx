@@ -1,4 +1,4 @@
-import React, { useState, useRef } from "react";
+import React, { useState, useRef, useEffect } from "react";
 import { useTranslation } from "react-i18next";
 import {
   Upload,
@@ -10,8 +10,14 @@ import {
   AlertCircle,
   CheckCircle,
   Loader2,
+  XCircle,
+  Eye,
+  Undo2,
 } from "lucide-react";
 import { Button } from "../ui/button";
+import { errorMessage, isCancelled } from "../../lib/errors";
+import { dto, models } from "../../../wailsjs/go/models";
+import { EventsOn } from "../../../wailsjs/runtime/runtime";
 import {
   Dialog,
   DialogContent,
@@ -41,11 +47,21 @@ interface ImportError {
 
 interface ImportResultData {
   successCount: number;
+  createdCount: number;
+  updatedCount: number;
+  skippedCount: number;
   errorCount: number;
   errors?: ImportError[];
   importedItems?: Product[];
+  preview?: dto.ImportPreview;
+  batchId?: number;
+  rolledBack?: boolean;
 }
 
+type ImportMode = "insert" | "update" | "upsert";
+type ImportMatchKey = "sku" | "id" | "name";
+type MergeRule = "overwrite" | "fillBlanks";
+
 interface ImportExportActionsProps {
   products: Product[];
   onImportSuccess: () => void;
@@ -64,7 +80,9 @@ export function ImportExportActions({
   disabled = false,
 }: ImportExportActionsProps) {
   const { t } = useTranslation();
-  const fileInputRef = useRef<HTMLInputElement>(null);
+  // cancelRequested tells an import the user stopped apart from a file
+  // dialog the user dismissed; both fail as cancelled.
+  const cancelRequested = useRef(false);
 
   const [importDialogOpen, setImportDialogOpen] = useState(false);
   const [exportDialogOpen, setExportDialogOpen] = useState(false);
@@ -78,7 +96,46 @@ export function ImportExportActions({
   const [isExporting, setIsExporting] = useState(false);
   const [importResult, setImportResult] = useState<ImportResult | null>(null);
 
-  const [selectedFile, setSelectedFile] = useState<File | null>(null);
+  const [importFormat, setImportFormat] = useState<"csv" | "xlsx">("csv");
+  const [importMode, setImportMode] = useState<ImportMode>("insert");
+  const [matchBy, setMatchBy] = useState<ImportMatchKey>("sku");
+  const [defaultMerge, setDefaultMerge] = useState<MergeRule>("overwrite");
+  const [preview, setPreview] = useState<dto.ImportPreview | null>(null);
+  const [atomic, setAtomic] = useState(false);
+  const [batches, setBatches] = useState<models.ImportBatch[]>([]);
+  const [rollingBack, setRollingBack] = useState<number | null>(null);
+  const [progress, setProgress] = useState<dto.ImportProgressDTO | null>(
+    null
+  );
+
+  const loadBatches = async () => {
+    try {
+      const { GetImportBatches } = await import(
+        "../../../wailsjs/go/main/App"
+      );
+
+      setBatches(await GetImportBatches(5));
+    } catch (error) {
+      console.error("Import history error:", error);
+    }
+  };
+
+  useEffect(() => {
+    if (importDialogOpen) {
+      loadBatches();
+    }
+  }, [importDialogOpen]);
+
+  // The backend reports every running import, and every chunk of rows it
+  // commits, through import:progress events.
+  useEffect(() => {
+    if (!importDialogOpen) {
+      return;
+    }
+    return EventsOn("import:progress", (data: any) =>
+      setProgress(new dto.ImportProgressDTO(data))
+    );
+  }, [importDialogOpen]);
 
   const handleDownloadTemplate = async () => {
     try {
@@ -97,105 +154,194 @@ export function ImportExportActions({
       });
     } catch (error) {
       console.error("Erro ao salvar template:", error);
-      setImportResult({
-        success: false,
-        message: `Erro ao salvar template: ${error}`,
-      });
+      setImportResult(
+        isCancelled(error)
+          ? null
+          : { success: false, message: errorMessage(error, t) }
+      );
     }
   };
 
-  const handleFileSelect = (event: React.ChangeEvent<HTMLInputElement>) => {
-    const file = event.target.files?.[0];
-    if (file) {
-      const isCSV = file.type === "text/csv" || file.name.endsWith(".csv");
-      const isExcel =
-        file.type ===
-          "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet" ||
-        file.name.endsWith(".xlsx");
-
-      if (isCSV || isExcel) {
-        setSelectedFile(file);
-        setImportResult(null);
+  // handleCancel stops the running import, or every call in progress when
+  // no import has reported yet, such as an export.
+  const handleCancel = async () => {
+    cancelRequested.current = true;
+    try {
+      const { CancelImport, CancelOperations } = await import(
+        "../../../wailsjs/go/main/App"
+      );
+
+      if (isImporting && progress?.status === "running") {
+        await CancelImport(progress.id);
       } else {
-        setImportResult({
-          success: false,
-          message: t("importExport.invalidFileType"),
-        });
+        await CancelOperations();
       }
+    } catch (error) {
+      console.error("Cancel error:", error);
+    }
+  };
+
+  const showImportResult = (result: ImportResultData) => {
+    let message: string;
+    if (result.rolledBack) {
+      message = t("importExport.importRolledBack", {
+        count: result.errorCount,
+      });
+    } else if (result.errorCount === 0) {
+      message = t("importExport.importSummary", {
+        created: result.createdCount,
+        updated: result.updatedCount,
+        skipped: result.skippedCount,
+      });
+    } else {
+      message = t("importExport.importErrors", { count: result.errorCount });
+    }
+    setImportResult({
+      success: result.errorCount === 0,
+      data: result,
+      message,
+    });
+
+    if (result.successCount > 0) {
+      onImportSuccess();
+    }
+    if (result.batchId) {
+      loadBatches();
     }
   };
 
-  const handleImport = async () => {
-    if (!selectedFile) {
+  const handleRollback = async (batchId: number) => {
+    setRollingBack(batchId);
+
+    try {
+      const { RollbackImport } = await import("../../../wailsjs/go/main/App");
+
+      const batch = await RollbackImport(batchId);
+      setImportResult({
+        success: true,
+        message: t("importExport.importUndone", {
+          created: batch.createdCount,
+          updated: batch.updatedCount,
+        }),
+      });
+      onImportSuccess();
+      loadBatches();
+    } catch (error) {
+      console.error("Rollback error:", error);
       setImportResult({
         success: false,
-        message: t("importExport.fileRequired"),
+        message: errorMessage(error, t),
       });
-      return;
+    } finally {
+      setRollingBack(null);
     }
+  };
 
+  // handleImport asks the backend to import a file of the chosen format,
+  // which it picks in a file dialog, or with dryRun only previews what the
+  // import would do.
+  const handleImport = async (dryRun: boolean) => {
+    cancelRequested.current = false;
     setIsImporting(true);
     setImportResult(null);
+    setProgress(null);
+    discardPreview();
 
     try {
       const { ImportProductsFromCSV, ImportProductsFromXLSX } = await import(
         "../../../wailsjs/go/main/App"
       );
 
+      // Columns are matched by header; no custom mapping is needed for
+      // files exported by the app or built from the template.
+      const options = new dto.ImportOptions({
+        mode: importMode,
+        matchBy,
+        defaultMerge,
+        dryRun,
+        atomic,
+      });
       let result: ImportResultData;
 
-      if (selectedFile.name.endsWith(".csv")) {
-        const fileContent = await readFileAsText(selectedFile);
-        result = await ImportProductsFromCSV(fileContent);
+      if (importFormat === "csv") {
+        result = await ImportProductsFromCSV(options);
       } else {
-        const fileContent = await readFileAsBase64(selectedFile);
-        result = await ImportProductsFromXLSX(fileContent);
+        result = await ImportProductsFromXLSX(options);
       }
 
-      setImportResult({
-        success: result.errorCount === 0,
-        data: result,
-        message:
-          result.errorCount === 0
-            ? t("importExport.importSuccess", { count: result.successCount })
-            : t("importExport.importErrors", { count: result.errorCount }),
-      });
-
-      if (result.successCount > 0) {
-        onImportSuccess();
+      if (result.preview) {
+        setPreview(result.preview);
+      } else {
+        showImportResult(result);
       }
     } catch (error) {
       console.error("Import error:", error);
-      setImportResult({
-        success: false,
-        message: `Import error: ${error}`,
-      });
+      showImportFailure(error);
     } finally {
       setIsImporting(false);
     }
   };
 
-  const readFileAsText = (file: File): Promise<string> => {
-    return new Promise((resolve, reject) => {
-      const reader = new FileReader();
-      reader.onload = e => resolve(e.target?.result as string);
-      reader.onerror = reject;
-      reader.readAsText(file);
+  // showImportFailure reports a failed or cancelled import. The chunks a
+  // cancelled import committed are kept, so the product list and the recent
+  // imports are reloaded. A dismissed file dialog reports nothing.
+  const showImportFailure = (error: unknown) => {
+    if (isCancelled(error) && !cancelRequested.current) {
+      setImportResult(null);
+      return;
+    }
+    if (!isCancelled(error)) {
+      setImportResult({
+        success: false,
+        message: errorMessage(error, t),
+      });
+      return;
+    }
+    setImportResult({
+      success: false,
+      message: atomic
+        ? t("importExport.importCancelledAtomic")
+        : t("importExport.importCancelled"),
     });
+    onImportSuccess();
+    loadBatches();
   };
 
-  const readFileAsBase64 = (file: File): Promise<string> => {
-    return new Promise((resolve, reject) => {
-      const reader = new FileReader();
-      reader.onload = e => {
-        const result = e.target?.result as string;
-        // Remove the data URL prefix to get just the base64 content
-        const base64 = result.split(",")[1];
-        resolve(base64);
-      };
-      reader.onerror = reject;
-      reader.readAsDataURL(file);
-    });
+  const handleCommitPreview = async () => {
+    if (!preview) {
+      return;
+    }
+
+    setIsImporting(true);
+    setImportResult(null);
+    setProgress(null);
+
+    try {
+      const { CommitImport } = await import("../../../wailsjs/go/main/App");
+
+      const result = await CommitImport(preview.id);
+      setPreview(null);
+      showImportResult(result);
+    } catch (error) {
+      console.error("Import commit error:", error);
+      setPreview(null);
+      showImportFailure(error);
+    } finally {
+      setIsImporting(false);
+    }
+  };
+
+  // discardPreview drops the current preview so the backend can forget its
+  // plan.
+  const discardPreview = () => {
+    if (!preview) {
+      return;
+    }
+    const id = preview.id;
+    setPreview(null);
+    import("../../../wailsjs/go/main/App")
+      .then(({ DiscardImportPreview }) => DiscardImportPreview(id))
+      .catch(error => console.error("Discard preview error:", error));
   };
 
   const handleExport = async (format: "csv" | "xlsx") => {
@@ -226,16 +372,12 @@ export function ImportExportActions({
     } catch (error) {
       console.error("Erro na exportação:", error);
 
-      const errorMessage = String(error);
-      if (
-        errorMessage.includes("cancelada pelo usuário") ||
-        errorMessage.includes("canceled")
-      ) {
+      if (isCancelled(error)) {
         setImportResult(null);
       } else {
         setImportResult({
           success: false,
-          message: `Erro na exportação: ${error}`,
+          message: errorMessage(error, t),
         });
       }
     } finally {
@@ -263,7 +405,15 @@ export function ImportExportActions({
 
   return (
     <div className="flex gap-2">
-      <Dialog open={importDialogOpen} onOpenChange={setImportDialogOpen}>
+      <Dialog
+        open={importDialogOpen}
+        onOpenChange={open => {
+          setImportDialogOpen(open);
+          if (!open) {
+            discardPreview();
+          }
+        }}
+      >
         <DialogTrigger asChild>
           <Button
             variant="outline"
@@ -275,7 +425,7 @@ export function ImportExportActions({
             {t("importExport.import")}
           </Button>
         </DialogTrigger>
-        <DialogContent className="max-w-md">
+        <DialogContent className={preview ? "max-w-3xl" : "max-w-md"}>
           <DialogHeader>
             <DialogTitle>{t("importExport.importProducts")}</DialogTitle>
           </DialogHeader>
@@ -292,40 +442,138 @@ export function ImportExportActions({
               </Button>
             </div>
 
-            <div>
-              <input
-                ref={fileInputRef}
-                type="file"
-                accept=".csv,.xlsx"
-                onChange={handleFileSelect}
-                className="hidden"
-              />
+            <div className="flex gap-2">
               <Button
-                variant="outline"
-                onClick={() => fileInputRef.current?.click()}
-                className="w-full flex items-center gap-2 file-upload-area"
+                variant={importFormat === "csv" ? "default" : "outline"}
+                onClick={() => setImportFormat("csv")}
+                className="flex-1 flex items-center gap-2"
               >
                 <FileText className="w-4 h-4" />
-                {selectedFile
-                  ? selectedFile.name
-                  : t("importExport.selectFile")}
+                {t("importExport.csvFormat")}
+              </Button>
+              <Button
+                variant={importFormat === "xlsx" ? "default" : "outline"}
+                onClick={() => setImportFormat("xlsx")}
+                className="flex-1 flex items-center gap-2"
+              >
+                <FileSpreadsheet className="w-4 h-4" />
+                {t("importExport.xlsxFormat")}
               </Button>
             </div>
 
-            <Button
-              onClick={handleImport}
-              disabled={!selectedFile || isImporting}
-              className="w-full flex items-center gap-2"
-            >
-              {isImporting ? (
-                <Loader2 className="w-4 h-4 animate-spin" />
-              ) : (
-                <Upload className="w-4 h-4" />
+            <div className="grid grid-cols-2 gap-2 text-sm">
+              <label htmlFor="importMode" className="self-center">
+                {t("importExport.mode")}
+              </label>
+              <select
+                id="importMode"
+                className="border border-gray-300 rounded px-2 py-1 focus:outline-none focus:ring-2 focus:ring-blue-400"
+                value={importMode}
+                onChange={e => setImportMode(e.target.value as ImportMode)}
+              >
+                <option value="insert">{t("importExport.modeInsert")}</option>
+                <option value="update">{t("importExport.modeUpdate")}</option>
+                <option value="upsert">{t("importExport.modeUpsert")}</option>
+              </select>
+
+              <label htmlFor="matchBy" className="self-center">
+                {t("importExport.matchBy")}
+              </label>
+              <select
+                id="matchBy"
+                className="border border-gray-300 rounded px-2 py-1 focus:outline-none focus:ring-2 focus:ring-blue-400"
+                value={matchBy}
+                onChange={e => setMatchBy(e.target.value as ImportMatchKey)}
+              >
+                <option value="sku">{t("importExport.matchBySku")}</option>
+                <option value="id">{t("importExport.matchById")}</option>
+                <option value="name">{t("importExport.matchByName")}</option>
+              </select>
+
+              {importMode !== "insert" && (
+                <>
+                  <label htmlFor="defaultMerge" className="self-center">
+                    {t("importExport.merge")}
+                  </label>
+                  <select
+                    id="defaultMerge"
+                    className="border border-gray-300 rounded px-2 py-1 focus:outline-none focus:ring-2 focus:ring-blue-400"
+                    value={defaultMerge}
+                    onChange={e => setDefaultMerge(e.target.value as MergeRule)}
+                  >
+                    <option value="overwrite">
+                      {t("importExport.mergeOverwrite")}
+                    </option>
+                    <option value="fillBlanks">
+                      {t("importExport.mergeFillBlanks")}
+                    </option>
+                  </select>
+                </>
               )}
-              {isImporting
-                ? t("importExport.importInProgress")
-                : t("importExport.uploadFile")}
-            </Button>
+
+              <label
+                htmlFor="atomicImport"
+                className="col-span-2 flex items-center gap-2"
+              >
+                <input
+                  id="atomicImport"
+                  type="checkbox"
+                  checked={atomic}
+                  onChange={e => setAtomic(e.target.checked)}
+                />
+                {t("importExport.atomic")}
+              </label>
+            </div>
+
+            <div className="flex gap-2">
+              <Button
+                variant="outline"
+                onClick={() => handleImport(true)}
+                disabled={isImporting}
+                className="flex-1 flex items-center gap-2"
+              >
+                <Eye className="w-4 h-4" />
+                {t("importExport.preview")}
+              </Button>
+              <Button
+                onClick={() => handleImport(false)}
+                disabled={isImporting}
+                className="flex-1 flex items-center gap-2"
+              >
+                {isImporting ? (
+                  <Loader2 className="w-4 h-4 animate-spin" />
+                ) : (
+                  <Upload className="w-4 h-4" />
+                )}
+                {isImporting
+                  ? t("importExport.importInProgress")
+                  : t("importExport.uploadFile")}
+              </Button>
+            </div>
+
+            {preview && (
+              <ImportPreviewTable
+                preview={preview}
+                isCommitting={isImporting}
+                onConfirm={handleCommitPreview}
+                onCancel={discardPreview}
+              />
+            )}
+
+            {isImporting && progress?.status === "running" && (
+              <ImportProgressBar progress={progress} />
+            )}
+
+            {isImporting && (
+              <Button
+                variant="outline"
+                onClick={handleCancel}
+                className="w-full flex items-center gap-2"
+              >
+                <XCircle className="w-4 h-4" />
+                {t("importExport.cancel")}
+              </Button>
+            )}
 
             {importResult && (
               <div
@@ -350,6 +598,19 @@ export function ImportExportActions({
                   </span>
                 </div>
 
+                {importResult.data?.batchId && (
+                  <Button
+                    variant="outline"
+                    size="sm"
+                    onClick={() => handleRollback(importResult.data!.batchId!)}
+                    disabled={rollingBack !== null}
+                    className="mt-2 flex items-center gap-2"
+                  >
+                    <Undo2 className="w-4 h-4" />
+                    {t("importExport.undoImport")}
+                  </Button>
+                )}
+
                 {importResult.data?.errors &&
                   importResult.data.errors.length > 0 && (
                     <div className="mt-2 space-y-1">
@@ -374,6 +635,46 @@ export function ImportExportActions({
                   )}
               </div>
             )}
+
+            {batches.length > 0 && (
+              <div className="space-y-1 text-xs">
+                <div className="font-medium text-sm">
+                  {t("importExport.recentImports")}
+                </div>
+                {batches.map(batch => (
+                  <div
+                    key={batch.id}
+                    className="flex items-center justify-between gap-2"
+                  >
+                    <span className={batch.rolledBackAt ? "text-gray-400" : ""}>
+                      {batch.createdAt} · {batch.source.toUpperCase()} ·{" "}
+                      {t("importExport.batchSummary", {
+                        created: batch.createdCount,
+                        updated: batch.updatedCount,
+                      })}
+                    </span>
+                    {batch.rolledBackAt ? (
+                      <span className="text-gray-400">
+                        {t("importExport.rolledBack")}
+                      </span>
+                    ) : (
+                      <Button
+                        variant="outline"
+                        size="sm"
+                        onClick={() => handleRollback(batch.id)}
+                        disabled={rollingBack !== null}
+                      >
+                        {rollingBack === batch.id ? (
+                          <Loader2 className="w-3 h-3 animate-spin" />
+                        ) : (
+                          t("importExport.undoImport")
+                        )}
+                      </Button>
+                    )}
+                  </div>
+                ))}
+              </div>
+            )}
           </div>
         </DialogContent>
       </Dialog>
@@ -490,9 +791,182 @@ export function ImportExportActions({
                 {t("importExport.xlsxFormat")}
               </Button>
             </div>
+
+            {isExporting && (
+              <Button
+                variant="outline"
+                onClick={handleCancel}
+                className="w-full flex items-center gap-2"
+              >
+                <XCircle className="w-4 h-4" />
+                {t("importExport.cancel")}
+              </Button>
+            )}
           </div>
         </DialogContent>
       </Dialog>
     </div>
   );
 }
+
+const previewStatusClasses: Record<string, string> = {
+  new: "text-green-700",
+  changed: "text-blue-700",
+  unchanged: "text-gray-500",
+  skipped: "text-gray-500",
+  invalid: "text-red-700",
+};
+
+// formatEta renders a number of seconds as m:ss.
+const formatEta = (seconds: number) =>
+  `${Math.floor(seconds / 60)}:${String(seconds % 60).padStart(2, "0")}`;
+
+// ImportProgressBar shows how far a running import got, from its latest
+// import:progress event.
+function ImportProgressBar({ progress }: { progress: dto.ImportProgressDTO }) {
+  const { t } = useTranslation();
+  const percent = Math.round(progress.progress * 100);
+
+  return (
+    <div className="space-y-1 text-sm">
+      <div className="h-2 w-full bg-gray-200 rounded">
+        <div
+          className="h-2 bg-blue-500 rounded transition-all"
+          style={{ width: `${percent}%` }}
+        />
+      </div>
+      <div className="flex justify-between text-gray-600">
+        <span>
+          {t("importExport.importProgress", {
+            rows: progress.rowsProcessed,
+            errors: progress.errorCount,
+          })}
+        </span>
+        <span>
+          {progress.etaSeconds < 0
+            ? `${percent}%`
+            : t("importExport.importEta", {
+                percent,
+                eta: formatEta(progress.etaSeconds),
+              })}
+        </span>
+      </div>
+    </div>
+  );
+}
+
+interface ImportPreviewTableProps {
+  preview: dto.ImportPreview;
+  isCommitting: boolean;
+  onConfirm: () => void;
+  onCancel: () => void;
+}
+
+// ImportPreviewTable lists what a previewed import does to each row, with
+// the before and after value of every field it changes.
+function ImportPreviewTable({
+  preview,
+  isCommitting,
+  onConfirm,
+  onCancel,
+}: ImportPreviewTableProps) {
+  const { t } = useTranslation();
+  // The counts cover every row, while rows only holds the first ones.
+  const counts = preview.counts ?? {};
+  const writes = (counts.new ?? 0) + (counts.changed ?? 0);
+
+  return (
+    <div className="space-y-3">
+      <div className="text-sm font-medium">
+        {t("importExport.previewSummary", {
+          new: counts.new ?? 0,
+          changed: counts.changed ?? 0,
+          unchanged: (counts.unchanged ?? 0) + (counts.skipped ?? 0),
+          invalid: counts.invalid ?? 0,
+        })}
+      </div>
+
+      <div className="border rounded-md max-h-80 overflow-y-auto">
+        <table className="w-full text-xs">
+          <thead className="bg-gray-50 sticky top-0">
+            <tr className="text-left">
+              <th className="p-2">{t("importExport.previewRow")}</th>
+              <th className="p-2">{t("importExport.previewStatus")}</th>
+              <th className="p-2">{t("importExport.previewProduct")}</th>
+              <th className="p-2">{t("importExport.previewChanges")}</th>
+            </tr>
+          </thead>
+          <tbody>
+            {preview.rows.map(row => (
+              <tr key={row.row} className="border-t align-top">
+                <td className="p-2">{row.row}</td>
+                <td className={`p-2 ${previewStatusClasses[row.status] ?? ""}`}>
+                  {t(`importExport.previewStatuses.${row.status}`)}
+                </td>
+                <td className="p-2">
+                  {row.name}
+                  {row.productId ? ` (#${row.productId})` : ""}
+                </td>
+                <td className="p-2 space-y-0.5">
+                  {row.changes?.map(change => (
+                    <div key={change.field}>
+                      <span className="font-medium">{change.field}:</span>{" "}
+                      {row.status === "changed" && (
+                        <>
+                          <span className="line-through text-gray-500">
+                            {change.before || "—"}
+                          </span>{" "}
+                          →{" "}
+                        </>
+                      )}
+                      {change.after}
+                    </div>
+                  ))}
+                  {row.errors?.map((error, index) => (
+                    <div key={index} className="text-red-600">
+                      {error.field}: {error.message}
+                    </div>
+                  ))}
+                  {row.reason && (
+                    <div className="text-gray-500">{row.reason}</div>
+                  )}
+                </td>
+              </tr>
+            ))}
+          </tbody>
+        </table>
+      </div>
+      {preview.totalRows > preview.rows.length && (
+        <div className="text-xs text-gray-500">
+          {t("importExport.previewTruncated", {
+            shown: preview.rows.length,
+            total: preview.totalRows,
+          })}
+        </div>
+      )}
+
+      <div className="flex gap-2">
+        <Button
+          variant="outline"
+          onClick={onCancel}
+          disabled={isCommitting}
+          className="flex-1"
+        >
+          {t("importExport.cancel")}
+        </Button>
+        <Button
+          onClick={onConfirm}
+          disabled={isCommitting || writes === 0}
+          className="flex-1 flex items-center gap-2"
+        >
+          {isCommitting ? (
+            <Loader2 className="w-4 h-4 animate-spin" />
+          ) : (
+            <CheckCircle className="w-4 h-4" />
+          )}
+          {t("importExport.confirmImport", { count: writes })}
+        </Button>
+      </div>
+    </div>
+  );
+}
@@ -1,4 +1,5 @@
 import { useState, useCallback, useEffect } from "react";
+import { useTranslation } from "react-i18next";
 import { models, dto } from "../../wailsjs/go/models";
 import {
   CreateProduct,
@@ -6,6 +7,7 @@ import {
   UpdateProduct,
   DeleteProduct,
 } from "../../wailsjs/go/main/App";
+import { errorMessage } from "../lib/errors";
 
 export type Product = models.Product;
 export type CreateProductDTO = dto.CreateProductDTO;
@@ -19,6 +21,7 @@ export interface PaginationParams {
 }
 
 export function useProductList(initialParams: PaginationParams) {
+  const { t } = useTranslation();
   const [products, setProducts] = useState<Product[]>([]);
   const [totalCount, setTotalCount] = useState(0);
   const [totalPages, setTotalPages] = useState(0);
@@ -35,9 +38,9 @@ export function useProductList(initialParams: PaginationParams) {
       setTotalCount(result.totalCount || 0);
       setTotalPages(result.totalPages || 0);
     } catch (error) {
-      setError(error instanceof Error ? error.message : String(error));
+      setError(errorMessage(error, t));
     }
-  }, [paginationParams]);
+  }, [paginationParams, t]);
 
   useEffect(() => {
     loadProducts();
@@ -58,7 +61,7 @@ export function useProductList(initialParams: PaginationParams) {
         setPaginationParams(prev => ({ ...prev, page: 1 }));
         loadProducts();
       } catch (error) {
-        setError(error instanceof Error ? error.message : String(error));
+        setError(errorMessage(error, t));
       }
     }
   };
@@ -66,24 +69,29 @@ export function useProductList(initialParams: PaginationParams) {
   const handleUpdateProduct = async (product: Product) => {
     try {
       setError("");
-      await UpdateProduct(product.id, product.name, product.price);
+      await UpdateProduct(product.id, {
+        version: product.version,
+        name: product.name,
+        price: product.price,
+      });
       setEditingProduct(null);
       loadProducts();
     } catch (error) {
-      setError(error instanceof Error ? error.message : String(error));
+      setError(errorMessage(error, t));
     }
   };
 
   const handleDeleteProduct = async (id: number) => {
     try {
       setError("");
-      await DeleteProduct(id);
+      const product = products.find(p => p.id === id);
+      await DeleteProduct(id, product?.version ?? 0);
       if (products.length === 1 && paginationParams.page > 1) {
         setPaginationParams(prev => ({ ...prev, page: prev.page - 1 }));
       }
       loadProducts();
     } catch (error) {
-      setError(error instanceof Error ? error.message : String(error));
+      setError(errorMessage(error, t));
     }
   };
 
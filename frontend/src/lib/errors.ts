@@ -0,0 +1,59 @@
+import type { TFunction } from "i18next";
+
+// Error codes returned by the Go bindings, see core/errors.
+export type ErrorCode =
+  | "NOT_FOUND"
+  | "VALIDATION"
+  | "CONFLICT"
+  | "UNAVAILABLE"
+  | "CANCELLED"
+  | "INTERNAL";
+
+export interface FieldError {
+  field: string;
+  message: string;
+}
+
+export interface AppError {
+  code: ErrorCode;
+  message: string;
+  fields?: FieldError[];
+  details?: Record<string, unknown>;
+}
+
+// toAppError normalizes a rejected binding call. The backend rejects with
+// an AppError object; anything else is reported as an internal error.
+export function toAppError(error: unknown): AppError {
+  if (
+    typeof error === "object" &&
+    error !== null &&
+    "code" in error &&
+    "message" in error
+  ) {
+    return error as AppError;
+  }
+  const message = error instanceof Error ? error.message : String(error);
+  return { code: "INTERNAL", message };
+}
+
+export function isCancelled(error: unknown): boolean {
+  return toAppError(error).code === "CANCELLED";
+}
+
+// errorMessage returns the localized text for error, listing the invalid
+// fields of a validation error.
+export function errorMessage(error: unknown, t: TFunction): string {
+  const appError = toAppError(error);
+  const summary = t(`errors.codes.${appError.code}`, {
+    defaultValue: appError.message,
+    ...appError.details,
+  });
+  if (appError.code === "VALIDATION" && appError.fields?.length) {
+    const fields = appError.fields.map(f => `${f.field}: ${f.message}`);
+    return `${summary} ${fields.join("; ")}`;
+  }
+  if (appError.code === "INTERNAL") {
+    return `${summary} ${appError.message}`;
+  }
+  return summary;
+}
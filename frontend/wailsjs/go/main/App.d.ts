@@ -3,13 +3,29 @@
 import {dto} from '../models';
 import {models} from '../models';
 
+export function ApplyBulkOperation(arg1:dto.BulkOperationDTO):Promise<dto.BulkOperationResult>;
+
+export function BackupDatabase():Promise<dto.BackupDTO>;
+
+export function CancelImport(arg1:string):Promise<void>;
+
+export function CancelMaintenance(arg1:string):Promise<void>;
+
+export function CancelOperations():Promise<number>;
+
 export function ClearCurrencyCache():Promise<void>;
 
+export function CommitImport(arg1:string):Promise<dto.ImportResult>;
+
 export function ConvertCurrency(arg1:dto.CurrencyConversionRequest):Promise<dto.CurrencyConversionResponse>;
 
 export function CreateProduct(arg1:dto.CreateProductDTO):Promise<models.Product>;
 
-export function DeleteProduct(arg1:number):Promise<void>;
+export function DeleteProduct(arg1:number,arg2:number):Promise<void>;
+
+export function DiscardImportPreview(arg1:string):Promise<void>;
+
+export function ExportEncryptedBackup(arg1:string):Promise<dto.BackupDTO>;
 
 export function ExportProductsToCSV(arg1:boolean,arg2:Array<number>):Promise<string>;
 
@@ -17,28 +33,58 @@ export function ExportProductsToXLSX(arg1:boolean,arg2:Array<number>):Promise<st
 
 export function GetAllProducts(arg1:dto.PaginationDTO):Promise<dto.PaginationResponse>;
 
+export function GetBackups():Promise<Array<dto.BackupDTO>>;
+
 export function GetDatabaseStatus():Promise<Record<string, any>>;
 
 export function GetExchangeRatesForCurrency(arg1:string):Promise<dto.CurrencyRatesResponse>;
 
+export function GetImportBatches(arg1:number):Promise<Array<models.ImportBatch>>;
+
 export function GetImportTemplate():Promise<string>;
 
+export function GetMaintenanceJob(arg1:string):Promise<dto.MaintenanceJobDTO>;
+
+export function GetMaintenanceJobs():Promise<Array<dto.MaintenanceJobDTO>>;
+
 export function GetProduct(arg1:number):Promise<models.Product>;
 
+export function GetSchemaStatus():Promise<dto.SchemaStatusDTO>;
+
 export function GetSupportedCurrencies():Promise<dto.SupportedCurrenciesResponse>;
 
+export function GetTrash(arg1:dto.PaginationDTO):Promise<dto.PaginationResponse>;
+
 export function Greet(arg1:string):Promise<string>;
 
-export function ImportProductsFromCSV(arg1:string):Promise<dto.ImportResult>;
+export function ImportEncryptedBackup(arg1:string,arg2:dto.ArchiveImportMode):Promise<dto.ArchiveImportResult>;
+
+export function ImportProductsFromCSV(arg1:dto.ImportOptions):Promise<dto.ImportResult>;
+
+export function ImportProductsFromXLSX(arg1:dto.ImportOptions):Promise<dto.ImportResult>;
+
+export function PurgeProduct(arg1:number):Promise<void>;
+
+export function PurgeTrash(arg1:number):Promise<number>;
 
-export function ImportProductsFromXLSX(arg1:string):Promise<dto.ImportResult>;
+export function RestoreBackup(arg1:string):Promise<dto.RestoreResultDTO>;
+
+export function RestoreDatabase():Promise<dto.RestoreResultDTO>;
+
+export function RestoreProduct(arg1:number,arg2:number):Promise<models.Product>;
 
 export function RetryDatabaseConnection():Promise<Record<string, any>>;
 
+export function RollbackImport(arg1:number):Promise<models.ImportBatch>;
+
 export function SaveExportedCSV(arg1:boolean,arg2:Array<number>):Promise<void>;
 
 export function SaveExportedXLSX(arg1:boolean,arg2:Array<number>):Promise<void>;
 
 export function SaveImportTemplate():Promise<void>;
 
-export function UpdateProduct(arg1:number,arg2:string,arg3:number):Promise<models.Product>;
+export function SearchProducts(arg1:dto.ProductSearchDTO):Promise<dto.ProductSearchResponse>;
+
+export function StartMaintenance(arg1:dto.MaintenanceTask):Promise<dto.MaintenanceJobDTO>;
+
+export function UpdateProduct(arg1:number,arg2:dto.UpdateProductDTO):Promise<models.Product>;
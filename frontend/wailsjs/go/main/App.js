@@ -2,10 +2,34 @@
 // Cynhyrchwyd y ffeil hon yn awtomatig. PEIDIWCH Â MODIWL
 // This file is automatically generated. DO NOT EDIT
 
+export function ApplyBulkOperation(arg1) {
+  return window['go']['main']['App']['ApplyBulkOperation'](arg1);
+}
+
+export function BackupDatabase() {
+  return window['go']['main']['App']['BackupDatabase']();
+}
+
+export function CancelImport(arg1) {
+  return window['go']['main']['App']['CancelImport'](arg1);
+}
+
+export function CancelMaintenance(arg1) {
+  return window['go']['main']['App']['CancelMaintenance'](arg1);
+}
+
+export function CancelOperations() {
+  return window['go']['main']['App']['CancelOperations']();
+}
+
 export function ClearCurrencyCache() {
   return window['go']['main']['App']['ClearCurrencyCache']();
 }
 
+export function CommitImport(arg1) {
+  return window['go']['main']['App']['CommitImport'](arg1);
+}
+
 export function ConvertCurrency(arg1) {
   return window['go']['main']['App']['ConvertCurrency'](arg1);
 }
@@ -14,8 +38,16 @@ export function CreateProduct(arg1) {
   return window['go']['main']['App']['CreateProduct'](arg1);
 }
 
-export function DeleteProduct(arg1) {
-  return window['go']['main']['App']['DeleteProduct'](arg1);
+export function DeleteProduct(arg1, arg2) {
+  return window['go']['main']['App']['DeleteProduct'](arg1, arg2);
+}
+
+export function DiscardImportPreview(arg1) {
+  return window['go']['main']['App']['DiscardImportPreview'](arg1);
+}
+
+export function ExportEncryptedBackup(arg1) {
+  return window['go']['main']['App']['ExportEncryptedBackup'](arg1);
 }
 
 export function ExportProductsToCSV(arg1, arg2) {
@@ -30,6 +62,10 @@ export function GetAllProducts(arg1) {
   return window['go']['main']['App']['GetAllProducts'](arg1);
 }
 
+export function GetBackups() {
+  return window['go']['main']['App']['GetBackups']();
+}
+
 export function GetDatabaseStatus() {
   return window['go']['main']['App']['GetDatabaseStatus']();
 }
@@ -38,22 +74,46 @@ export function GetExchangeRatesForCurrency(arg1) {
   return window['go']['main']['App']['GetExchangeRatesForCurrency'](arg1);
 }
 
+export function GetImportBatches(arg1) {
+  return window['go']['main']['App']['GetImportBatches'](arg1);
+}
+
 export function GetImportTemplate() {
   return window['go']['main']['App']['GetImportTemplate']();
 }
 
+export function GetMaintenanceJob(arg1) {
+  return window['go']['main']['App']['GetMaintenanceJob'](arg1);
+}
+
+export function GetMaintenanceJobs() {
+  return window['go']['main']['App']['GetMaintenanceJobs']();
+}
+
 export function GetProduct(arg1) {
   return window['go']['main']['App']['GetProduct'](arg1);
 }
 
+export function GetSchemaStatus() {
+  return window['go']['main']['App']['GetSchemaStatus']();
+}
+
 export function GetSupportedCurrencies() {
   return window['go']['main']['App']['GetSupportedCurrencies']();
 }
 
+export function GetTrash(arg1) {
+  return window['go']['main']['App']['GetTrash'](arg1);
+}
+
 export function Greet(arg1) {
   return window['go']['main']['App']['Greet'](arg1);
 }
 
+export function ImportEncryptedBackup(arg1, arg2) {
+  return window['go']['main']['App']['ImportEncryptedBackup'](arg1, arg2);
+}
+
 export function ImportProductsFromCSV(arg1) {
   return window['go']['main']['App']['ImportProductsFromCSV'](arg1);
 }
@@ -62,10 +122,34 @@ export function ImportProductsFromXLSX(arg1) {
   return window['go']['main']['App']['ImportProductsFromXLSX'](arg1);
 }
 
+export function PurgeProduct(arg1) {
+  return window['go']['main']['App']['PurgeProduct'](arg1);
+}
+
+export function PurgeTrash(arg1) {
+  return window['go']['main']['App']['PurgeTrash'](arg1);
+}
+
+export function RestoreBackup(arg1) {
+  return window['go']['main']['App']['RestoreBackup'](arg1);
+}
+
+export function RestoreDatabase() {
+  return window['go']['main']['App']['RestoreDatabase']();
+}
+
+export function RestoreProduct(arg1, arg2) {
+  return window['go']['main']['App']['RestoreProduct'](arg1, arg2);
+}
+
 export function RetryDatabaseConnection() {
   return window['go']['main']['App']['RetryDatabaseConnection']();
 }
 
+export function RollbackImport(arg1) {
+  return window['go']['main']['App']['RollbackImport'](arg1);
+}
+
 export function SaveExportedCSV(arg1, arg2) {
   return window['go']['main']['App']['SaveExportedCSV'](arg1, arg2);
 }
@@ -78,6 +162,14 @@ export function SaveImportTemplate() {
   return window['go']['main']['App']['SaveImportTemplate']();
 }
 
-export function UpdateProduct(arg1, arg2, arg3) {
-  return window['go']['main']['App']['UpdateProduct'](arg1, arg2, arg3);
+export function SearchProducts(arg1) {
+  return window['go']['main']['App']['SearchProducts'](arg1);
+}
+
+export function StartMaintenance(arg1) {
+  return window['go']['main']['App']['StartMaintenance'](arg1);
+}
+
+export function UpdateProduct(arg1, arg2) {
+  return window['go']['main']['App']['UpdateProduct'](arg1, arg2);
 }
@@ -1,7 +1,324 @@
+export namespace apperrors {
+	
+	export class FieldError {
+	    field: string;
+	    message: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new FieldError(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.field = source["field"];
+	        this.message = source["message"];
+	    }
+	}
+
+}
+
 export namespace dto {
 	
+	export class MergeRejection {
+	    productId: number;
+	    name: string;
+	    errors: apperrors.FieldError[];
+	
+	    static createFrom(source: any = {}) {
+	        return new MergeRejection(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.productId = source["productId"];
+	        this.name = source["name"];
+	        this.errors = this.convertValues(source["errors"], apperrors.FieldError);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class MergeResult {
+	    inserted: number;
+	    updated: number;
+	    skipped: number;
+	    rejected?: MergeRejection[];
+	
+	    static createFrom(source: any = {}) {
+	        return new MergeResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.inserted = source["inserted"];
+	        this.updated = source["updated"];
+	        this.skipped = source["skipped"];
+	        this.rejected = this.convertValues(source["rejected"], MergeRejection);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class BackupDTO {
+	    path: string;
+	    size: number;
+	    createdAt: string;
+	    schemaVersion: number;
+	    productCount: number;
+	    scheduled: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new BackupDTO(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.path = source["path"];
+	        this.size = source["size"];
+	        this.createdAt = source["createdAt"];
+	        this.schemaVersion = source["schemaVersion"];
+	        this.productCount = source["productCount"];
+	        this.scheduled = source["scheduled"];
+	    }
+	}
+	export class ArchiveImportResult {
+	    mode: string;
+	    archive: BackupDTO;
+	    safetyBackup: string;
+	    merge?: MergeResult;
+	
+	    static createFrom(source: any = {}) {
+	        return new ArchiveImportResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.mode = source["mode"];
+	        this.archive = this.convertValues(source["archive"], BackupDTO);
+	        this.safetyBackup = source["safetyBackup"];
+	        this.merge = this.convertValues(source["merge"], MergeResult);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	
+	export class BulkItemResult {
+	    id: number;
+	    success: boolean;
+	    error?: string;
+	    product?: models.Product;
+	
+	    static createFrom(source: any = {}) {
+	        return new BulkItemResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.success = source["success"];
+	        this.error = source["error"];
+	        this.product = this.convertValues(source["product"], models.Product);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class PriceAdjustmentDTO {
+	    type: string;
+	    amount: number;
+	    rounding?: string;
+	    increment?: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new PriceAdjustmentDTO(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.type = source["type"];
+	        this.amount = source["amount"];
+	        this.rounding = source["rounding"];
+	        this.increment = source["increment"];
+	    }
+	}
+	export class ProductFilterDTO {
+	    minPrice?: number;
+	    maxPrice?: number;
+	    minStock?: number;
+	    maxStock?: number;
+	    categories?: string[];
+	    hasImage?: boolean;
+	    hasDescription?: boolean;
+	    createdFrom?: string;
+	    createdTo?: string;
+	    updatedFrom?: string;
+	    updatedTo?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new ProductFilterDTO(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.minPrice = source["minPrice"];
+	        this.maxPrice = source["maxPrice"];
+	        this.minStock = source["minStock"];
+	        this.maxStock = source["maxStock"];
+	        this.categories = source["categories"];
+	        this.hasImage = source["hasImage"];
+	        this.hasDescription = source["hasDescription"];
+	        this.createdFrom = source["createdFrom"];
+	        this.createdTo = source["createdTo"];
+	        this.updatedFrom = source["updatedFrom"];
+	        this.updatedTo = source["updatedTo"];
+	    }
+	}
+	export class BulkOperationDTO {
+	    operation: string;
+	    productIds?: number[];
+	    search?: string;
+	    filters?: ProductFilterDTO;
+	    category?: string;
+	    stockDelta?: number;
+	    price?: number;
+	    priceAdjustment?: PriceAdjustmentDTO;
+	
+	    static createFrom(source: any = {}) {
+	        return new BulkOperationDTO(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.operation = source["operation"];
+	        this.productIds = source["productIds"];
+	        this.search = source["search"];
+	        this.filters = this.convertValues(source["filters"], ProductFilterDTO);
+	        this.category = source["category"];
+	        this.stockDelta = source["stockDelta"];
+	        this.price = source["price"];
+	        this.priceAdjustment = this.convertValues(source["priceAdjustment"], PriceAdjustmentDTO);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class BulkOperationResult {
+	    operation: string;
+	    committed: boolean;
+	    successCount: number;
+	    errorCount: number;
+	    items: BulkItemResult[];
+	
+	    static createFrom(source: any = {}) {
+	        return new BulkOperationResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.operation = source["operation"];
+	        this.committed = source["committed"];
+	        this.successCount = source["successCount"];
+	        this.errorCount = source["errorCount"];
+	        this.items = this.convertValues(source["items"], BulkItemResult);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
 	export class CreateProductDTO {
 	    name: string;
+	    sku?: string;
 	    price: number;
 	    category?: string;
 	    stock?: number;
@@ -15,6 +332,7 @@ export namespace dto {
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
 	        this.name = source["name"];
+	        this.sku = source["sku"];
 	        this.price = source["price"];
 	        this.category = source["category"];
 	        this.stock = source["stock"];
@@ -22,43 +340,393 @@ export namespace dto {
 	        this.imageUrl = source["imageUrl"];
 	    }
 	}
-	export class CurrencyConversionRequest {
-	    amount: number;
-	    fromCurrency: string;
-	    toCurrency: string;
+	export class CurrencyConversionRequest {
+	    amount: number;
+	    fromCurrency: string;
+	    toCurrency: string;
+	    rounding?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new CurrencyConversionRequest(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.amount = source["amount"];
+	        this.fromCurrency = source["fromCurrency"];
+	        this.toCurrency = source["toCurrency"];
+	        this.rounding = source["rounding"];
+	    }
+	}
+	export class CurrencyConversionResponse {
+	    amount: number;
+	    fromCurrency: string;
+	    toCurrency: string;
+	    convertedAmount: number;
+	    exchangeRate: number;
+	    // Go type: time
+	    conversionDate: any;
+	
+	    static createFrom(source: any = {}) {
+	        return new CurrencyConversionResponse(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.amount = source["amount"];
+	        this.fromCurrency = source["fromCurrency"];
+	        this.toCurrency = source["toCurrency"];
+	        this.convertedAmount = source["convertedAmount"];
+	        this.exchangeRate = source["exchangeRate"];
+	        this.conversionDate = this.convertValues(source["conversionDate"], null);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class CurrencyInfo {
+	    code: string;
+	    symbol: string;
+	    name: string;
+	    decimals: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new CurrencyInfo(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.code = source["code"];
+	        this.symbol = source["symbol"];
+	        this.name = source["name"];
+	        this.decimals = source["decimals"];
+	    }
+	}
+	export class CurrencyRatesResponse {
+	    date: string;
+	    base?: string;
+	    rates?: Record<string, number>;
+	
+	    static createFrom(source: any = {}) {
+	        return new CurrencyRatesResponse(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.date = source["date"];
+	        this.base = source["base"];
+	        this.rates = source["rates"];
+	    }
+	}
+	export class ImportError {
+	    row: number;
+	    field: string;
+	    message: string;
+	    value: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new ImportError(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.row = source["row"];
+	        this.field = source["field"];
+	        this.message = source["message"];
+	        this.value = source["value"];
+	    }
+	}
+	export class ImportFieldChange {
+	    field: string;
+	    before: string;
+	    after: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new ImportFieldChange(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.field = source["field"];
+	        this.before = source["before"];
+	        this.after = source["after"];
+	    }
+	}
+	export class ImportOptions {
+	    columnMapping?: Record<string, string>;
+	    mode?: string;
+	    matchBy?: string;
+	    defaultMerge?: string;
+	    merge?: Record<string, string>;
+	    dryRun?: boolean;
+	    atomic?: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new ImportOptions(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.columnMapping = source["columnMapping"];
+	        this.mode = source["mode"];
+	        this.matchBy = source["matchBy"];
+	        this.defaultMerge = source["defaultMerge"];
+	        this.merge = source["merge"];
+	        this.dryRun = source["dryRun"];
+	        this.atomic = source["atomic"];
+	    }
+	}
+	export class ImportPreviewRow {
+	    row: number;
+	    status: string;
+	    productId?: number;
+	    name: string;
+	    changes?: ImportFieldChange[];
+	    errors?: ImportError[];
+	    reason?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new ImportPreviewRow(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.row = source["row"];
+	        this.status = source["status"];
+	        this.productId = source["productId"];
+	        this.name = source["name"];
+	        this.changes = this.convertValues(source["changes"], ImportFieldChange);
+	        this.errors = this.convertValues(source["errors"], ImportError);
+	        this.reason = source["reason"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class ImportPreview {
+	    id: string;
+	    expiresAt: string;
+	    rows: ImportPreviewRow[];
+	    totalRows: number;
+	    counts: Record<string, number>;
+	
+	    static createFrom(source: any = {}) {
+	        return new ImportPreview(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.expiresAt = source["expiresAt"];
+	        this.rows = this.convertValues(source["rows"], ImportPreviewRow);
+	        this.totalRows = source["totalRows"];
+	        this.counts = source["counts"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	
+	export class ImportSkip {
+	    row: number;
+	    productId?: number;
+	    reason: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new ImportSkip(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.row = source["row"];
+	        this.productId = source["productId"];
+	        this.reason = source["reason"];
+	    }
+	}
+	export class ImportResult {
+	    successCount: number;
+	    createdCount: number;
+	    updatedCount: number;
+	    skippedCount: number;
+	    errorCount: number;
+	    errors?: ImportError[];
+	    skipped?: ImportSkip[];
+	    importedItems?: models.Product[];
+	    preview?: ImportPreview;
+	    batchId?: number;
+	    rolledBack?: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new ImportResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.successCount = source["successCount"];
+	        this.createdCount = source["createdCount"];
+	        this.updatedCount = source["updatedCount"];
+	        this.skippedCount = source["skippedCount"];
+	        this.errorCount = source["errorCount"];
+	        this.errors = this.convertValues(source["errors"], ImportError);
+	        this.skipped = this.convertValues(source["skipped"], ImportSkip);
+	        this.importedItems = this.convertValues(source["importedItems"], models.Product);
+	        this.preview = this.convertValues(source["preview"], ImportPreview);
+	        this.batchId = source["batchId"];
+	        this.rolledBack = source["rolledBack"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	
+	export class MaintenanceJobDTO {
+	    id: string;
+	    task: string;
+	    status: string;
+	    progress: number;
+	    message: string;
+	    startedAt: string;
+	    finishedAt?: string;
+	    error?: string;
+	    result?: any;
+	
+	    static createFrom(source: any = {}) {
+	        return new MaintenanceJobDTO(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.task = source["task"];
+	        this.status = source["status"];
+	        this.progress = source["progress"];
+	        this.message = source["message"];
+	        this.startedAt = source["startedAt"];
+	        this.finishedAt = source["finishedAt"];
+	        this.error = source["error"];
+	        this.result = source["result"];
+	    }
+	}
+	
+	
+	export class PaginationDTO {
+	    page: number;
+	    pageSize: number;
+	    search?: string;
+	    sortBy?: string;
+	    order?: string;
+	    filters?: ProductFilterDTO;
 	
 	    static createFrom(source: any = {}) {
-	        return new CurrencyConversionRequest(source);
+	        return new PaginationDTO(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
-	        this.amount = source["amount"];
-	        this.fromCurrency = source["fromCurrency"];
-	        this.toCurrency = source["toCurrency"];
+	        this.page = source["page"];
+	        this.pageSize = source["pageSize"];
+	        this.search = source["search"];
+	        this.sortBy = source["sortBy"];
+	        this.order = source["order"];
+	        this.filters = this.convertValues(source["filters"], ProductFilterDTO);
 	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
 	}
-	export class CurrencyConversionResponse {
-	    amount: number;
-	    fromCurrency: string;
-	    toCurrency: string;
-	    convertedAmount: number;
-	    exchangeRate: number;
-	    // Go type: time
-	    conversionDate: any;
+	export class PaginationResponse {
+	    products: models.Product[];
+	    totalCount: number;
+	    totalPages: number;
+	    page: number;
+	    pageSize: number;
 	
 	    static createFrom(source: any = {}) {
-	        return new CurrencyConversionResponse(source);
+	        return new PaginationResponse(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
-	        this.amount = source["amount"];
-	        this.fromCurrency = source["fromCurrency"];
-	        this.toCurrency = source["toCurrency"];
-	        this.convertedAmount = source["convertedAmount"];
-	        this.exchangeRate = source["exchangeRate"];
-	        this.conversionDate = this.convertValues(source["conversionDate"], null);
+	        this.products = this.convertValues(source["products"], models.Product);
+	        this.totalCount = source["totalCount"];
+	        this.totalPages = source["totalPages"];
+	        this.page = source["page"];
+	        this.pageSize = source["pageSize"];
 	    }
 	
 		convertValues(a: any, classs: any, asMap: boolean = false): any {
@@ -79,72 +747,113 @@ export namespace dto {
 		    return a;
 		}
 	}
-	export class CurrencyInfo {
-	    code: string;
-	    symbol: string;
-	    name: string;
+	
+	
+	export class ProductSearchDTO {
+	    query: string;
+	    page: number;
+	    pageSize: number;
 	
 	    static createFrom(source: any = {}) {
-	        return new CurrencyInfo(source);
+	        return new ProductSearchDTO(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
-	        this.code = source["code"];
-	        this.symbol = source["symbol"];
-	        this.name = source["name"];
+	        this.query = source["query"];
+	        this.page = source["page"];
+	        this.pageSize = source["pageSize"];
 	    }
 	}
-	export class CurrencyRatesResponse {
-	    date: string;
-	    base?: string;
-	    rates?: Record<string, number>;
+	export class ProductSearchResult {
+	    product?: models.Product;
+	    rank: number;
+	    nameHighlight: string;
+	    categoryHighlight?: string;
+	    descriptionSnippet?: string;
 	
 	    static createFrom(source: any = {}) {
-	        return new CurrencyRatesResponse(source);
+	        return new ProductSearchResult(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
-	        this.date = source["date"];
-	        this.base = source["base"];
-	        this.rates = source["rates"];
+	        this.product = this.convertValues(source["product"], models.Product);
+	        this.rank = source["rank"];
+	        this.nameHighlight = source["nameHighlight"];
+	        this.categoryHighlight = source["categoryHighlight"];
+	        this.descriptionSnippet = source["descriptionSnippet"];
 	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
 	}
-	export class ImportError {
-	    row: number;
-	    field: string;
-	    message: string;
-	    value: string;
+	export class ProductSearchResponse {
+	    results: ProductSearchResult[];
+	    totalCount: number;
+	    totalPages: number;
+	    page: number;
+	    pageSize: number;
 	
 	    static createFrom(source: any = {}) {
-	        return new ImportError(source);
+	        return new ProductSearchResponse(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
-	        this.row = source["row"];
-	        this.field = source["field"];
-	        this.message = source["message"];
-	        this.value = source["value"];
+	        this.results = this.convertValues(source["results"], ProductSearchResult);
+	        this.totalCount = source["totalCount"];
+	        this.totalPages = source["totalPages"];
+	        this.page = source["page"];
+	        this.pageSize = source["pageSize"];
 	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
 	}
-	export class ImportResult {
-	    successCount: number;
-	    errorCount: number;
-	    errors?: ImportError[];
-	    importedItems?: models.Product[];
+	
+	export class RestoreResultDTO {
+	    restored: BackupDTO;
+	    safetyBackup: string;
 	
 	    static createFrom(source: any = {}) {
-	        return new ImportResult(source);
+	        return new RestoreResultDTO(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
-	        this.successCount = source["successCount"];
-	        this.errorCount = source["errorCount"];
-	        this.errors = this.convertValues(source["errors"], ImportError);
-	        this.importedItems = this.convertValues(source["importedItems"], models.Product);
+	        this.restored = this.convertValues(source["restored"], BackupDTO);
+	        this.safetyBackup = source["safetyBackup"];
 	    }
 	
 		convertValues(a: any, classs: any, asMap: boolean = false): any {
@@ -165,44 +874,42 @@ export namespace dto {
 		    return a;
 		}
 	}
-	export class PaginationDTO {
-	    page: number;
-	    pageSize: number;
-	    search?: string;
-	    sortBy?: string;
-	    order?: string;
+	export class SchemaMigrationDTO {
+	    version: number;
+	    name: string;
+	    checksum: string;
+	    appliedAt?: string;
 	
 	    static createFrom(source: any = {}) {
-	        return new PaginationDTO(source);
+	        return new SchemaMigrationDTO(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
-	        this.page = source["page"];
-	        this.pageSize = source["pageSize"];
-	        this.search = source["search"];
-	        this.sortBy = source["sortBy"];
-	        this.order = source["order"];
+	        this.version = source["version"];
+	        this.name = source["name"];
+	        this.checksum = source["checksum"];
+	        this.appliedAt = source["appliedAt"];
 	    }
 	}
-	export class PaginationResponse {
-	    products: models.Product[];
-	    totalCount: number;
-	    totalPages: number;
-	    page: number;
-	    pageSize: number;
+	export class SchemaStatusDTO {
+	    currentVersion: number;
+	    latestVersion: number;
+	    applied: SchemaMigrationDTO[];
+	    pending: SchemaMigrationDTO[];
+	    migrationBackup?: string;
 	
 	    static createFrom(source: any = {}) {
-	        return new PaginationResponse(source);
+	        return new SchemaStatusDTO(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
-	        this.products = this.convertValues(source["products"], models.Product);
-	        this.totalCount = source["totalCount"];
-	        this.totalPages = source["totalPages"];
-	        this.page = source["page"];
-	        this.pageSize = source["pageSize"];
+	        this.currentVersion = source["currentVersion"];
+	        this.latestVersion = source["latestVersion"];
+	        this.applied = this.convertValues(source["applied"], SchemaMigrationDTO);
+	        this.pending = this.convertValues(source["pending"], SchemaMigrationDTO);
+	        this.migrationBackup = source["migrationBackup"];
 	    }
 	
 		convertValues(a: any, classs: any, asMap: boolean = false): any {
@@ -253,6 +960,34 @@ export namespace dto {
 		    return a;
 		}
 	}
+	export class UpdateProductDTO {
+	    version: number;
+	    name?: string;
+	    sku?: string;
+	    price?: number;
+	    category?: string;
+	    stock?: number;
+	    description?: string;
+	    imageUrl?: string;
+	    clear?: string[];
+	
+	    static createFrom(source: any = {}) {
+	        return new UpdateProductDTO(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.version = source["version"];
+	        this.name = source["name"];
+	        this.sku = source["sku"];
+	        this.price = source["price"];
+	        this.category = source["category"];
+	        this.stock = source["stock"];
+	        this.description = source["description"];
+	        this.imageUrl = source["imageUrl"];
+	        this.clear = source["clear"];
+	    }
+	}
 
 }
 
@@ -261,6 +996,7 @@ export namespace models {
 	export class Product {
 	    id: number;
 	    name: string;
+	    sku?: string;
 	    price: number;
 	    category?: string;
 	    stock: number;
@@ -268,6 +1004,8 @@ export namespace models {
 	    imageUrl?: string;
 	    createdAt: string;
 	    updatedAt?: string;
+	    version: number;
+	    deletedAt?: string;
 	
 	    static createFrom(source: any = {}) {
 	        return new Product(source);
@@ -277,6 +1015,7 @@ export namespace models {
 	        if ('string' === typeof source) source = JSON.parse(source);
 	        this.id = source["id"];
 	        this.name = source["name"];
+	        this.sku = source["sku"];
 	        this.price = source["price"];
 	        this.category = source["category"];
 	        this.stock = source["stock"];
@@ -284,8 +1023,89 @@ export namespace models {
 	        this.imageUrl = source["imageUrl"];
 	        this.createdAt = source["createdAt"];
 	        this.updatedAt = source["updatedAt"];
+	        this.version = source["version"];
+	        this.deletedAt = source["deletedAt"];
+	    }
+	}
+	export class ImportBatchItem {
+	    productId: number;
+	    before?: Product;
+	    version: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new ImportBatchItem(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.productId = source["productId"];
+	        this.before = this.convertValues(source["before"], Product);
+	        this.version = source["version"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class ImportBatch {
+	    id: number;
+	    source: string;
+	    mode: string;
+	    createdCount: number;
+	    updatedCount: number;
+	    createdAt: string;
+	    rolledBackAt?: string;
+	    items?: ImportBatchItem[];
+	
+	    static createFrom(source: any = {}) {
+	        return new ImportBatch(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.source = source["source"];
+	        this.mode = source["mode"];
+	        this.createdCount = source["createdCount"];
+	        this.updatedCount = source["updatedCount"];
+	        this.createdAt = source["createdAt"];
+	        this.rolledBackAt = source["rolledBackAt"];
+	        this.items = this.convertValues(source["items"], ImportBatchItem);
 	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
 	}
+	
 
 }
 
@@ -3,6 +3,10 @@ package main
 import (
 	"embed"
 	"log"
+	"os"
+
+	"product-management-app/core/config"
+	apperrors "product-management-app/core/errors"
 
 	"github.com/wailsapp/wails/v2"
 	"github.com/wailsapp/wails/v2/pkg/logger"
@@ -19,8 +23,11 @@ var assets embed.FS
 var icon []byte
 
 func main() {
+	// Resolve the configuration from flags, environment and config file
+	cfg, cfgErr := config.Load(os.Args[1:])
+
 	// Create an instance of the app structure
-	app := NewApp()
+	app := NewApp(cfg, cfgErr)
 
 	// Create application with options
 	err := wails.Run(&options.App{
@@ -44,6 +51,11 @@ func main() {
 		Logger:    nil,
 		LogLevel:  logger.DEBUG,
 		OnStartup: app.startup,
+		// Errors reach the frontend as {code, message, fields, details}
+		// objects rather than bare strings.
+		ErrorFormatter: func(err error) any {
+			return apperrors.ToResponse(err)
+		},
 		SingleInstanceLock: &options.SingleInstanceLock{
 			UniqueId:               "e3984e08-28dc-4e3d-b70a-45e961589cdc",
 			OnSecondInstanceLaunch: app.onSecondInstanceLaunch,
@@ -150,6 +150,7 @@ test() {
 
     print_info "Running Go tests..."
     go test -v -race ./...
+    go test -v -race -tags "sqlite_cgo sqlite_fts5" ./...
     print_success "Go tests passed"
 
     if [ -f "frontend/package.json" ] && grep -q '"test"' frontend/package.json; then
@@ -0,0 +1,95 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+// wailsLogHandler is a slog.Handler that writes records to the Wails
+// runtime logger, so the core services can log through a plain *slog.Logger
+// and stay usable outside the app. Attributes are appended to the message as
+// key=value pairs, since the Wails logger only takes a string.
+type wailsLogHandler struct {
+	// ctx is the context Wails passed to startup; the runtime needs it to
+	// find its logger.
+	ctx    context.Context
+	attrs  []slog.Attr
+	prefix string
+}
+
+func newWailsLogHandler(ctx context.Context) *wailsLogHandler {
+	return &wailsLogHandler{ctx: ctx}
+}
+
+// Enabled accepts every level; the Wails logger applies its own LogLevel.
+func (h *wailsLogHandler) Enabled(context.Context, slog.Level) bool {
+	return true
+}
+
+func (h *wailsLogHandler) Handle(_ context.Context, record slog.Record) error {
+	var b strings.Builder
+	b.WriteString(record.Message)
+	for _, attr := range h.attrs {
+		writeAttr(&b, "", attr)
+	}
+	record.Attrs(func(attr slog.Attr) bool {
+		writeAttr(&b, h.prefix, attr)
+		return true
+	})
+	message := b.String()
+
+	switch {
+	case record.Level >= slog.LevelError:
+		runtime.LogError(h.ctx, message)
+	case record.Level >= slog.LevelWarn:
+		runtime.LogWarning(h.ctx, message)
+	case record.Level >= slog.LevelInfo:
+		runtime.LogInfo(h.ctx, message)
+	default:
+		runtime.LogDebug(h.ctx, message)
+	}
+	return nil
+}
+
+func (h *wailsLogHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	clone := *h
+	clone.attrs = make([]slog.Attr, 0, len(h.attrs)+len(attrs))
+	clone.attrs = append(clone.attrs, h.attrs...)
+	for _, attr := range attrs {
+		attr.Key = h.prefix + attr.Key
+		clone.attrs = append(clone.attrs, attr)
+	}
+	return &clone
+}
+
+func (h *wailsLogHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	clone := *h
+	clone.prefix = h.prefix + name + "."
+	return &clone
+}
+
+// writeAttr appends attr to b as " key=value", flattening groups into
+// dotted keys.
+func writeAttr(b *strings.Builder, prefix string, attr slog.Attr) {
+	attr.Value = attr.Value.Resolve()
+	if attr.Equal(slog.Attr{}) {
+		return
+	}
+	if attr.Value.Kind() == slog.KindGroup {
+		if attr.Key != "" {
+			prefix += attr.Key + "."
+		}
+		for _, member := range attr.Value.Group() {
+			writeAttr(b, prefix, member)
+		}
+		return
+	}
+	fmt.Fprintf(b, " %s%s=%v", prefix, attr.Key, attr.Value)
+}